@@ -15,6 +15,7 @@
 package pd
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
@@ -34,6 +35,7 @@ import (
 	"github.com/tikv/pd/client/tlsutil"
 	"github.com/tikv/pd/client/tsoutil"
 	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
 	"google.golang.org/grpc"
 )
 
@@ -61,6 +63,55 @@ type Region struct {
 	Buckets      *metapb.Buckets
 }
 
+// TotalReadBytes returns the sum of the read bytes of all buckets.
+func (r *Region) TotalReadBytes() uint64 {
+	return sumUint64(r.Buckets.GetStats().GetReadBytes())
+}
+
+// TotalWriteBytes returns the sum of the write bytes of all buckets.
+func (r *Region) TotalWriteBytes() uint64 {
+	return sumUint64(r.Buckets.GetStats().GetWriteBytes())
+}
+
+// TotalReadKeys returns the sum of the read keys of all buckets.
+func (r *Region) TotalReadKeys() uint64 {
+	return sumUint64(r.Buckets.GetStats().GetReadKeys())
+}
+
+// TotalWriteKeys returns the sum of the write keys of all buckets.
+func (r *Region) TotalWriteKeys() uint64 {
+	return sumUint64(r.Buckets.GetStats().GetWriteKeys())
+}
+
+// HotBuckets returns the indices of the buckets whose read QPS plus write QPS
+// is not less than the threshold.
+func (r *Region) HotBuckets(thresholdQPS uint64) []int {
+	stats := r.Buckets.GetStats()
+	readQPS, writeQPS := stats.GetReadQps(), stats.GetWriteQps()
+	hotBuckets := make([]int, 0)
+	for i := 0; i < len(readQPS) || i < len(writeQPS); i++ {
+		var qps uint64
+		if i < len(readQPS) {
+			qps += readQPS[i]
+		}
+		if i < len(writeQPS) {
+			qps += writeQPS[i]
+		}
+		if qps >= thresholdQPS {
+			hotBuckets = append(hotBuckets, i)
+		}
+	}
+	return hotBuckets
+}
+
+func sumUint64(values []uint64) uint64 {
+	var sum uint64
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
 // GlobalConfigItem standard format of KV pair in GlobalConfig client
 type GlobalConfigItem struct {
 	EventType pdpb.EventType
@@ -90,10 +141,15 @@ type RPCClient interface {
 	// If a region has no leader, corresponding leader will be placed by a peer
 	// with empty value (PeerID is 0).
 	ScanRegions(ctx context.Context, key, endKey []byte, limit int, opts ...GetRegionOption) ([]*Region, error)
+	// StreamAllRegions scans all regions page by page and calls cb for each of them in key order,
+	// which can be used to rebuild the region cache in one pass. It stops once cb returns false.
+	StreamAllRegions(ctx context.Context, cb func(*Region) bool, opts ...GetRegionOption) error
 	// GetStore gets a store from PD by store id.
 	// The store may expire later. Caller is responsible for caching and taking care
 	// of store change.
 	GetStore(ctx context.Context, storeID uint64) (*metapb.Store, error)
+	// GetStoreLag returns how long it has been since PD last received the heartbeat of the store.
+	GetStoreLag(ctx context.Context, storeID uint64) (time.Duration, error)
 	// GetAllStores gets all stores from pd.
 	// The store may expire later. Caller is responsible for caching and taking care
 	// of store change.
@@ -160,14 +216,25 @@ type Client interface {
 
 	// UpdateOption updates the client option.
 	UpdateOption(option DynamicOption, value any) error
+	// UpdateOptions updates multiple client options atomically. All the values
+	// are checked before any of them is applied.
+	UpdateOptions(options map[DynamicOption]any) error
+	// UpdateOptionWithResult updates the client option and reports whether
+	// the value of the option is changed by the update.
+	UpdateOptionWithResult(option DynamicOption, value any) (bool, error)
 
 	// Close closes the client.
 	Close()
+	// CloseGracefully closes the client like Close, but it rejects the new region requests
+	// and waits for the in-flight ones to finish first. The client is closed anyway once
+	// the context is done, and the error of the context is returned.
+	CloseGracefully(ctx context.Context) error
 }
 
 // GetStoreOp represents available options when getting stores.
 type GetStoreOp struct {
 	excludeTombstone bool
+	labelFilters     []*metapb.StoreLabel
 }
 
 // GetStoreOption configures GetStoreOp.
@@ -178,6 +245,14 @@ func WithExcludeTombstone() GetStoreOption {
 	return func(op *GetStoreOp) { op.excludeTombstone = true }
 }
 
+// WithStoreLabelFilter only keeps the stores with the given label in the result.
+// Multiple filters are ANDed together.
+func WithStoreLabelFilter(key, value string) GetStoreOption {
+	return func(op *GetStoreOp) {
+		op.labelFilters = append(op.labelFilters, &metapb.StoreLabel{Key: key, Value: value})
+	}
+}
+
 // RegionsOp represents available options when operate regions
 type RegionsOp struct {
 	group          string
@@ -207,6 +282,10 @@ func WithSkipStoreLimit() RegionsOption {
 type GetRegionOp struct {
 	needBuckets         bool
 	allowFollowerHandle bool
+	leaderStoreIDs      []uint64
+	sortByLeaderStore   bool
+	preferHealthy       bool
+	rawResponse         *pdpb.GetRegionResponse
 }
 
 // GetRegionOption configures GetRegionOp.
@@ -222,6 +301,35 @@ func WithAllowFollowerHandle() GetRegionOption {
 	return func(op *GetRegionOp) { op.allowFollowerHandle = true }
 }
 
+// WithLeaderOnStores means only returning the scanned regions whose leader is on one of the given stores.
+// The filtering is done by the client since the scan request cannot carry it, so ScanRegions may send
+// more than one request to collect up to the limit of the matched regions.
+func WithLeaderOnStores(storeIDs []uint64) GetRegionOption {
+	return func(op *GetRegionOp) { op.leaderStoreIDs = storeIDs }
+}
+
+// WithSortByLeaderStore means returning the scanned regions ordered by the store ID of their leaders,
+// the regions with the same leader store keep the key order. It's ignored by StreamAllRegions, which
+// always streams the regions in the key order.
+func WithSortByLeaderStore() GetRegionOption {
+	return func(op *GetRegionOp) { op.sortByLeaderStore = true }
+}
+
+// WithRawResponse means copying the raw response which the returned region is built from into
+// the given sink, so the fields dropped by Region, e.g. the header, can be inspected. It only
+// works for GetRegion, GetPrevRegion and GetRegionByID, and the sink is left untouched if no
+// response is received, e.g. the request fails or the negative cache is hit.
+func WithRawResponse(sink *pdpb.GetRegionResponse) GetRegionOption {
+	return func(op *GetRegionOp) { op.rawResponse = sink }
+}
+
+// WithPreferHealthyReplication means returning the scanned regions fully replicated before the ones
+// with any down or pending peer, each part keeps the order it would have without the option. It's
+// ignored by StreamAllRegions, which always streams the regions in the key order.
+func WithPreferHealthyReplication() GetRegionOption {
+	return func(op *GetRegionOp) { op.preferHealthy = true }
+}
+
 var (
 	// errUnmatchedClusterID is returned when found a PD with a different cluster ID.
 	errUnmatchedClusterID = errors.New("[pd] unmatched cluster id")
@@ -245,6 +353,36 @@ func WithGRPCDialOptions(opts ...grpc.DialOption) ClientOption {
 	}
 }
 
+// WithGRPCCompression configures the client to compress the unary gRPC requests,
+// e.g. the region lookups, with the given compressor, such as "gzip". The
+// compression is disabled if the compressor is unknown or unsupported by the server.
+func WithGRPCCompression(compressor string) ClientOption {
+	return func(c *client) {
+		if interceptor := newCompressionInterceptor(compressor); interceptor != nil {
+			c.option.gRPCDialOptions = append(c.option.gRPCDialOptions, grpc.WithChainUnaryInterceptor(interceptor))
+		}
+	}
+}
+
+// WithMaxRecvMsgSize configures the max size in bytes of the gRPC response the
+// client can receive, which is 4MiB by default. A larger unary response, e.g.
+// a region with too many peers, fails with errs.ErrClientResponseTooLarge.
+func WithMaxRecvMsgSize(size int) ClientOption {
+	return func(c *client) {
+		c.option.maxRecvMsgSize = size
+	}
+}
+
+// WithFollowerConnIdleTTL configures the client to close the connections to the
+// PD followers which have been idle for the given TTL while neither the follower
+// handle nor the forwarding is enabled. The connections are reopened once they
+// are needed again. The connection to the PD leader is never closed.
+func WithFollowerConnIdleTTL(ttl time.Duration) ClientOption {
+	return func(c *client) {
+		c.option.followerConnIdleTTL = ttl
+	}
+}
+
 // WithCustomTimeoutOption configures the client with timeout option.
 func WithCustomTimeoutOption(timeout time.Duration) ClientOption {
 	return func(c *client) {
@@ -252,6 +390,25 @@ func WithCustomTimeoutOption(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithDialTimeout configures the client with the timeout to connect to PD when it's created,
+// it fails with ErrClientDialTimeout if none of the PD members is reachable in time. It doesn't
+// affect the requests sent after the client is created.
+func WithDialTimeout(timeout time.Duration) ClientOption {
+	return func(c *client) {
+		c.option.dialTimeout = timeout
+	}
+}
+
+// WithNegativeCacheTTL configures the client to cache the "no region found" result of
+// GetRegion for the given TTL, so the repeated lookups of a key without region don't
+// hit the server until the TTL expires. The failed lookups are never cached. 0 means
+// no caching, which is the default.
+func WithNegativeCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *client) {
+		c.option.negativeCacheTTL = ttl
+	}
+}
+
 // WithForwardingOption configures the client with forwarding option.
 func WithForwardingOption(enableForwarding bool) ClientOption {
 	return func(c *client) {
@@ -280,6 +437,15 @@ func WithInitMetricsOption(initMetrics bool) ClientOption {
 	}
 }
 
+// WithLeaderOnlyOption configures the client to only send region requests to the leader.
+// The follower handle will be refused for such a client even if EnableFollowerHandle is
+// turned on later.
+func WithLeaderOnlyOption(leaderOnly bool) ClientOption {
+	return func(c *client) {
+		c.option.leaderOnly = leaderOnly
+	}
+}
+
 var _ Client = (*client)(nil)
 
 // serviceModeKeeper is for service mode switching.
@@ -324,6 +490,11 @@ type client struct {
 	wg     sync.WaitGroup
 	tlsCfg *tls.Config
 	option *option
+
+	// inflight tracks the in-flight region requests for CloseGracefully.
+	inflight inflightRequests
+	// regionMisses caches the keys which have no region if the negative cache TTL is set.
+	regionMisses regionMissCache
 }
 
 // SecurityOption records options about tls
@@ -603,6 +774,13 @@ func (c *client) Close() {
 	}
 }
 
+// CloseGracefully closes the client after the in-flight region requests finish.
+func (c *client) CloseGracefully(ctx context.Context) error {
+	err := c.inflight.closeAndWait(ctx)
+	c.Close()
+	return err
+}
+
 func (c *client) setServiceMode(newMode pdpb.ServiceMode) {
 	c.Lock()
 	defer c.Unlock()
@@ -711,36 +889,62 @@ func (c *client) GetServiceDiscovery() ServiceDiscovery {
 
 // UpdateOption updates the client option.
 func (c *client) UpdateOption(option DynamicOption, value any) error {
+	return c.UpdateOptions(map[DynamicOption]any{option: value})
+}
+
+// UpdateOptions updates multiple client options atomically. No option is
+// updated if any of the values is invalid, and the readers never observe a
+// state in which only a part of the options is updated.
+func (c *client) UpdateOptions(options map[DynamicOption]any) error {
+	_, err := c.updateOptions(options)
+	return err
+}
+
+// updateOptions updates the client options atomically and returns the options
+// whose values are changed by this update.
+func (c *client) updateOptions(options map[DynamicOption]any) ([]DynamicOption, error) {
+	for option, value := range options {
+		if err := c.checkOption(option, value); err != nil {
+			return nil, err
+		}
+	}
+	return c.option.setDynamicOptions(options), nil
+}
+
+// checkOption checks whether the value is valid for the client option.
+func (c *client) checkOption(option DynamicOption, value any) error {
 	switch option {
 	case MaxTSOBatchWaitInterval:
 		interval, ok := value.(time.Duration)
 		if !ok {
 			return errors.New("[pd] invalid value type for MaxTSOBatchWaitInterval option, it should be time.Duration")
 		}
-		if err := c.option.setMaxTSOBatchWaitInterval(interval); err != nil {
-			return err
-		}
+		return checkMaxTSOBatchWaitInterval(interval)
 	case EnableTSOFollowerProxy:
 		if c.getServiceMode() != pdpb.ServiceMode_PD_SVC_MODE {
 			return errors.New("[pd] tso follower proxy is only supported in PD service mode")
 		}
-		enable, ok := value.(bool)
-		if !ok {
+		if _, ok := value.(bool); !ok {
 			return errors.New("[pd] invalid value type for EnableTSOFollowerProxy option, it should be bool")
 		}
-		c.option.setEnableTSOFollowerProxy(enable)
 	case EnableFollowerHandle:
-		enable, ok := value.(bool)
-		if !ok {
+		if _, ok := value.(bool); !ok {
 			return errors.New("[pd] invalid value type for EnableFollowerHandle option, it should be bool")
 		}
-		c.option.setEnableFollowerHandle(enable)
 	default:
 		return errors.New("[pd] unsupported client option")
 	}
 	return nil
 }
 
+// UpdateOptionWithResult updates the client option like UpdateOption, and reports
+// whether the value of the option is changed, so the caller could skip the
+// re-initialization on a no-op update.
+func (c *client) UpdateOptionWithResult(option DynamicOption, value any) (bool, error) {
+	changed, err := c.updateOptions(map[DynamicOption]any{option: value})
+	return len(changed) > 0, err
+}
+
 func (c *client) GetAllMembers(ctx context.Context) ([]*pdpb.Member, error) {
 	start := time.Now()
 	defer func() { cmdDurationGetAllMembers.Observe(time.Since(start).Seconds()) }()
@@ -897,6 +1101,13 @@ func (c *client) GetMinTS(ctx context.Context) (physical int64, logical int64, e
 	return minTS.Physical, tsoutil.AddLogical(minTS.Logical, 0, minTS.SuffixBits), nil
 }
 
+// captureRawResponse copies the raw response into the sink set by WithRawResponse.
+func (op *GetRegionOp) captureRawResponse(resp *pdpb.GetRegionResponse) {
+	if op.rawResponse != nil {
+		*op.rawResponse = *resp
+	}
+}
+
 func handleRegionResponse(res *pdpb.GetRegionResponse) *Region {
 	if res.Region == nil {
 		return nil
@@ -953,6 +1164,10 @@ func (c *client) GetRegionFromMember(ctx context.Context, key []byte, memberURLs
 }
 
 func (c *client) GetRegion(ctx context.Context, key []byte, opts ...GetRegionOption) (*Region, error) {
+	if !c.inflight.begin() {
+		return nil, errors.WithStack(errClosing)
+	}
+	defer c.inflight.end()
 	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
 		span = span.Tracer().StartSpan("pdclient.GetRegion", opentracing.ChildOf(span.Context()))
 		defer span.Finish()
@@ -966,6 +1181,10 @@ func (c *client) GetRegion(ctx context.Context, key []byte, opts ...GetRegionOpt
 	for _, opt := range opts {
 		opt(options)
 	}
+	negativeCacheTTL := c.option.negativeCacheTTL
+	if negativeCacheTTL > 0 && c.regionMisses.has(key, start) {
+		return nil, nil
+	}
 	req := &pdpb.GetRegionRequest{
 		Header:      c.requestHeader(),
 		RegionKey:   key,
@@ -987,10 +1206,20 @@ func (c *client) GetRegion(ctx context.Context, key []byte, opts ...GetRegionOpt
 	if err = c.respForErr(cmdFailDurationGetRegion, start, err, resp.GetHeader()); err != nil {
 		return nil, err
 	}
-	return handleRegionResponse(resp), nil
+	options.captureRawResponse(resp)
+	region := handleRegionResponse(resp)
+	// Only the succeeded lookup without region is cached, the errors may be transient.
+	if region == nil && negativeCacheTTL > 0 {
+		c.regionMisses.add(key, time.Now(), negativeCacheTTL)
+	}
+	return region, nil
 }
 
 func (c *client) GetPrevRegion(ctx context.Context, key []byte, opts ...GetRegionOption) (*Region, error) {
+	if !c.inflight.begin() {
+		return nil, errors.WithStack(errClosing)
+	}
+	defer c.inflight.end()
 	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
 		span = span.Tracer().StartSpan("pdclient.GetPrevRegion", opentracing.ChildOf(span.Context()))
 		defer span.Finish()
@@ -1025,10 +1254,15 @@ func (c *client) GetPrevRegion(ctx context.Context, key []byte, opts ...GetRegio
 	if err = c.respForErr(cmdFailDurationGetPrevRegion, start, err, resp.GetHeader()); err != nil {
 		return nil, err
 	}
+	options.captureRawResponse(resp)
 	return handleRegionResponse(resp), nil
 }
 
 func (c *client) GetRegionByID(ctx context.Context, regionID uint64, opts ...GetRegionOption) (*Region, error) {
+	if !c.inflight.begin() {
+		return nil, errors.WithStack(errClosing)
+	}
+	defer c.inflight.end()
 	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
 		span = span.Tracer().StartSpan("pdclient.GetRegionByID", opentracing.ChildOf(span.Context()))
 		defer span.Finish()
@@ -1063,10 +1297,15 @@ func (c *client) GetRegionByID(ctx context.Context, regionID uint64, opts ...Get
 	if err = c.respForErr(cmdFailedDurationGetRegionByID, start, err, resp.GetHeader()); err != nil {
 		return nil, err
 	}
+	options.captureRawResponse(resp)
 	return handleRegionResponse(resp), nil
 }
 
 func (c *client) ScanRegions(ctx context.Context, key, endKey []byte, limit int, opts ...GetRegionOption) ([]*Region, error) {
+	if !c.inflight.begin() {
+		return nil, errors.WithStack(errClosing)
+	}
+	defer c.inflight.end()
 	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
 		span = span.Tracer().StartSpan("pdclient.ScanRegions", opentracing.ChildOf(span.Context()))
 		defer span.Finish()
@@ -1084,33 +1323,154 @@ func (c *client) ScanRegions(ctx context.Context, key, endKey []byte, limit int,
 	for _, opt := range opts {
 		opt(options)
 	}
-	req := &pdpb.ScanRegionsRequest{
-		Header:   c.requestHeader(),
-		StartKey: key,
-		EndKey:   endKey,
-		Limit:    int32(limit),
+	scan := func(key []byte) ([]*Region, error) {
+		req := &pdpb.ScanRegionsRequest{
+			Header:   c.requestHeader(),
+			StartKey: key,
+			EndKey:   endKey,
+			Limit:    int32(limit),
+		}
+		serviceClient, cctx := c.getRegionAPIClientAndContext(scanCtx, options.allowFollowerHandle && c.option.getEnableFollowerHandle())
+		if serviceClient == nil {
+			return nil, errs.ErrClientGetProtoClient
+		}
+		resp, err := pdpb.NewPDClient(serviceClient.GetClientConn()).ScanRegions(cctx, req)
+		failpoint.Inject("responseNil", func() {
+			resp = nil
+		})
+		if serviceClient.NeedRetry(resp.GetHeader().GetError(), err) {
+			protoClient, cctx := c.getClientAndContext(scanCtx)
+			if protoClient == nil {
+				return nil, errs.ErrClientGetProtoClient
+			}
+			resp, err = protoClient.ScanRegions(cctx, req)
+		}
+
+		if err = c.respForErr(cmdFailedDurationScanRegions, start, err, resp.GetHeader()); err != nil {
+			return nil, err
+		}
+		return handleRegionsResponse(resp), nil
 	}
-	serviceClient, cctx := c.getRegionAPIClientAndContext(scanCtx, options.allowFollowerHandle && c.option.getEnableFollowerHandle())
-	if serviceClient == nil {
-		return nil, errs.ErrClientGetProtoClient
+	regions, err := scanRegionsOnLeaderStores(key, endKey, limit, options.leaderStoreIDs, scan)
+	if err != nil {
+		return nil, err
 	}
-	resp, err := pdpb.NewPDClient(serviceClient.GetClientConn()).ScanRegions(cctx, req)
-	failpoint.Inject("responseNil", func() {
-		resp = nil
-	})
-	if serviceClient.NeedRetry(resp.GetHeader().GetError(), err) {
-		protoClient, cctx := c.getClientAndContext(scanCtx)
-		if protoClient == nil {
-			return nil, errs.ErrClientGetProtoClient
+	return handleScannedRegions(regions, options), nil
+}
+
+// scanRegionsOnLeaderStores scans the regions in [key, endKey) and keeps the ones led by
+// the given stores. The scan request cannot carry the leader stores, so the regions are
+// filtered after the server applies the limit, and the scan goes on from where the last
+// page ends until the limit is met or no region is left.
+func scanRegionsOnLeaderStores(key, endKey []byte, limit int, storeIDs []uint64, scan func(key []byte) ([]*Region, error)) ([]*Region, error) {
+	var regions []*Region
+	for {
+		scanned, err := scan(key)
+		if err != nil {
+			return nil, err
+		}
+		regions = append(regions, filterRegionsByLeaderStores(scanned, storeIDs)...)
+		// Without the filter or the limit, all the regions are already in the first page.
+		if len(storeIDs) == 0 || limit <= 0 || len(regions) >= limit || len(scanned) == 0 {
+			break
 		}
-		resp, err = protoClient.ScanRegions(cctx, req)
+		nextKey := scanned[len(scanned)-1].Meta.GetEndKey()
+		if len(nextKey) == 0 || (len(endKey) > 0 && bytes.Compare(nextKey, endKey) >= 0) || bytes.Compare(nextKey, key) <= 0 {
+			break
+		}
+		key = nextKey
+	}
+	if limit > 0 && len(regions) > limit {
+		regions = regions[:limit]
 	}
+	return regions, nil
+}
 
-	if err = c.respForErr(cmdFailedDurationScanRegions, start, err, resp.GetHeader()); err != nil {
-		return nil, err
+// handleScannedRegions sorts the scanned regions as the options require.
+func handleScannedRegions(regions []*Region, options *GetRegionOp) []*Region {
+	if options.sortByLeaderStore {
+		sortRegionsByLeaderStore(regions)
+	}
+	if options.preferHealthy {
+		sortRegionsByReplicationHealth(regions)
+	}
+	return regions
+}
+
+// sortRegionsByReplicationHealth moves the regions with any down or pending peer to the end.
+func sortRegionsByReplicationHealth(regions []*Region) {
+	isHealthy := func(region *Region) bool {
+		return len(region.DownPeers) == 0 && len(region.PendingPeers) == 0
 	}
+	slices.SortStableFunc(regions, func(a, b *Region) bool {
+		return isHealthy(a) && !isHealthy(b)
+	})
+}
+
+func sortRegionsByLeaderStore(regions []*Region) {
+	slices.SortStableFunc(regions, func(a, b *Region) bool {
+		return a.Leader.GetStoreId() < b.Leader.GetStoreId()
+	})
+}
+
+func filterRegionsByLeaderStores(regions []*Region, storeIDs []uint64) []*Region {
+	if len(storeIDs) == 0 {
+		return regions
+	}
+	filtered := make([]*Region, 0, len(regions))
+	for _, region := range regions {
+		if slices.Contains(storeIDs, region.Leader.GetStoreId()) {
+			filtered = append(filtered, region)
+		}
+	}
+	return filtered
+}
 
-	return handleRegionsResponse(resp), nil
+// streamRegionsPageSize is the max number of regions held in memory by StreamAllRegions.
+const streamRegionsPageSize = 1024
+
+func (c *client) StreamAllRegions(ctx context.Context, cb func(*Region) bool, opts ...GetRegionOption) error {
+	return streamAllRegions(ctx, c, streamRegionsPageSize, cb, opts...)
+}
+
+func streamAllRegions(ctx context.Context, cli RPCClient, pageSize int, cb func(*Region) bool, opts ...GetRegionOption) error {
+	options := &GetRegionOp{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	// The pages are filtered here, otherwise an empty page cannot tell
+	// whether all regions have been scanned. And they must be in the key
+	// order to find where the next page starts.
+	opts = append(opts, WithLeaderOnStores(nil), func(op *GetRegionOp) {
+		op.sortByLeaderStore = false
+		op.preferHealthy = false
+	})
+	key := []byte{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		regions, err := cli.ScanRegions(ctx, key, nil, pageSize, opts...)
+		if err != nil {
+			return err
+		}
+		if len(regions) == 0 {
+			return nil
+		}
+		for _, region := range filterRegionsByLeaderStores(regions, options.leaderStoreIDs) {
+			if !cb(region) {
+				return nil
+			}
+		}
+		endKey := regions[len(regions)-1].Meta.GetEndKey()
+		if len(endKey) == 0 {
+			return nil
+		}
+		if bytes.Compare(endKey, key) <= 0 {
+			return errors.Errorf("[pd] regions are not scanned forward from key %q", key)
+		}
+		key = endKey
+	}
 }
 
 func handleRegionsResponse(resp *pdpb.ScanRegionsResponse) []*Region {
@@ -1168,6 +1528,26 @@ func (c *client) GetStore(ctx context.Context, storeID uint64) (*metapb.Store, e
 	return handleStoreResponse(resp)
 }
 
+func (c *client) GetStoreLag(ctx context.Context, storeID uint64) (time.Duration, error) {
+	store, err := c.GetStore(ctx, storeID)
+	if err != nil {
+		return 0, err
+	}
+	return getStoreLag(store, time.Now())
+}
+
+func getStoreLag(store *metapb.Store, now time.Time) (time.Duration, error) {
+	if store.GetLastHeartbeat() == 0 {
+		return 0, errors.Errorf("[pd] store %d has not sent any heartbeat", store.GetId())
+	}
+	lag := now.Sub(time.Unix(0, store.GetLastHeartbeat()))
+	// The clocks of PD and the client may be skewed.
+	if lag < 0 {
+		lag = 0
+	}
+	return lag, nil
+}
+
 func handleStoreResponse(resp *pdpb.GetStoreResponse) (*metapb.Store, error) {
 	store := resp.GetStore()
 	if store == nil {
@@ -1209,7 +1589,37 @@ func (c *client) GetAllStores(ctx context.Context, opts ...GetStoreOption) ([]*m
 	if err = c.respForErr(cmdFailedDurationGetAllStores, start, err, resp.GetHeader()); err != nil {
 		return nil, err
 	}
-	return resp.GetStores(), nil
+	return filterStoresByLabels(resp.GetStores(), options.labelFilters), nil
+}
+
+// filterStoresByLabels returns the stores which have all the given labels.
+func filterStoresByLabels(stores []*metapb.Store, labels []*metapb.StoreLabel) []*metapb.Store {
+	if len(labels) == 0 {
+		return stores
+	}
+	filtered := make([]*metapb.Store, 0, len(stores))
+	for _, store := range stores {
+		if storeHasLabels(store, labels) {
+			filtered = append(filtered, store)
+		}
+	}
+	return filtered
+}
+
+func storeHasLabels(store *metapb.Store, labels []*metapb.StoreLabel) bool {
+	for _, label := range labels {
+		found := false
+		for _, storeLabel := range store.GetLabels() {
+			if storeLabel.GetKey() == label.GetKey() && storeLabel.GetValue() == label.GetValue() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 func (c *client) UpdateGCSafePoint(ctx context.Context, safePoint uint64) (uint64, error) {
@@ -16,15 +16,21 @@ package pd
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/client/errs"
 	"github.com/tikv/pd/client/testutil"
 	"github.com/tikv/pd/client/tsoutil"
 	"go.uber.org/goleak"
+	"golang.org/x/exp/slices"
 	"google.golang.org/grpc"
 )
 
@@ -32,6 +38,30 @@ func TestMain(m *testing.M) {
 	goleak.VerifyTestMain(m, testutil.LeakOptions...)
 }
 
+// newClientWithMockPDServer creates a client connected to the given PD server as the leader.
+// startMockPDServer serves the given PD server and returns its URL.
+func startMockPDServer(t *testing.T, pdServer pdpb.PDServer) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	pdpb.RegisterPDServer(server, pdServer)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return "http://" + lis.Addr().String()
+}
+
+func newClientWithMockPDServer(t *testing.T, pdServer pdpb.PDServer) *client {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	url := startMockPDServer(t, pdServer)
+	sd := NewDefaultPDServiceDiscovery(ctx, cancel, []string{url}, nil)
+	t.Cleanup(sd.Close)
+	conn, err := sd.GetOrCreateGRPCConn(url)
+	re.NoError(err)
+	sd.leader.Store(newPDServiceClient(url, url, conn, true))
+	return &client{option: newOption(), pdSvcDiscovery: sd, ctx: ctx, cancel: cancel}
+}
+
 func TestTSLessEqual(t *testing.T) {
 	re := require.New(t)
 	re.True(tsoutil.TSLessEqual(9, 9, 9, 9))
@@ -91,6 +121,17 @@ func TestClientWithRetry(t *testing.T) {
 	re.Less(time.Since(start), time.Second*10)
 }
 
+func TestClientWithDialTimeout(t *testing.T) {
+	re := require.New(t)
+	start := time.Now()
+	// It would keep retrying for a long time without the dial timeout.
+	_, err := NewClientWithContext(context.TODO(), []string{testClientURL}, SecurityOption{},
+		WithMaxErrorRetry(100), WithDialTimeout(500*time.Millisecond))
+	re.Error(err)
+	re.ErrorIs(err, errs.ErrClientDialTimeout)
+	re.Less(time.Since(start), 2*time.Second)
+}
+
 func TestGRPCDialOption(t *testing.T) {
 	re := require.New(t)
 	start := time.Now()
@@ -136,3 +177,434 @@ func TestTsoRequestWait(t *testing.T) {
 	_, _, err = req.Wait()
 	re.ErrorIs(errors.Cause(err), context.Canceled)
 }
+
+func TestFilterStoresByLabels(t *testing.T) {
+	re := require.New(t)
+	newStore := func(id uint64, labels ...string) *metapb.Store {
+		store := &metapb.Store{Id: id}
+		for i := 0; i < len(labels); i += 2 {
+			store.Labels = append(store.Labels, &metapb.StoreLabel{Key: labels[i], Value: labels[i+1]})
+		}
+		return store
+	}
+	stores := []*metapb.Store{
+		newStore(1, "zone", "z1", "host", "h1"),
+		newStore(2, "zone", "z1", "host", "h2"),
+		newStore(3, "zone", "z2", "host", "h1"),
+		newStore(4),
+	}
+	getIDs := func(opts ...GetStoreOption) []uint64 {
+		op := &GetStoreOp{}
+		for _, opt := range opts {
+			opt(op)
+		}
+		ids := make([]uint64, 0)
+		for _, store := range filterStoresByLabels(stores, op.labelFilters) {
+			ids = append(ids, store.GetId())
+		}
+		return ids
+	}
+	re.Equal([]uint64{1, 2, 3, 4}, getIDs())
+	re.Equal([]uint64{1, 2}, getIDs(WithStoreLabelFilter("zone", "z1")))
+	re.Equal([]uint64{1}, getIDs(WithStoreLabelFilter("zone", "z1"), WithStoreLabelFilter("host", "h1")))
+	re.Empty(getIDs(WithStoreLabelFilter("zone", "z3")))
+}
+
+func TestRegionBucketStats(t *testing.T) {
+	re := require.New(t)
+	region := &Region{
+		Meta: &metapb.Region{Id: 1},
+		Buckets: &metapb.Buckets{
+			RegionId: 1,
+			Keys:     [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d")},
+			Stats: &metapb.BucketStats{
+				ReadBytes:  []uint64{10, 20, 30},
+				WriteBytes: []uint64{1, 2, 3},
+				ReadKeys:   []uint64{4, 5, 6},
+				WriteKeys:  []uint64{7, 8, 9},
+				ReadQps:    []uint64{100, 5, 50},
+				WriteQps:   []uint64{0, 10, 60},
+			},
+		},
+	}
+	re.Equal(uint64(60), region.TotalReadBytes())
+	re.Equal(uint64(6), region.TotalWriteBytes())
+	re.Equal(uint64(15), region.TotalReadKeys())
+	re.Equal(uint64(24), region.TotalWriteKeys())
+	re.Equal([]int{0, 2}, region.HotBuckets(100))
+	re.Equal([]int{0, 1, 2}, region.HotBuckets(0))
+	re.Empty(region.HotBuckets(1000))
+
+	// A region without buckets has no stats.
+	region = &Region{Meta: &metapb.Region{Id: 1}}
+	re.Zero(region.TotalReadBytes())
+	re.Zero(region.TotalWriteKeys())
+	re.Empty(region.HotBuckets(0))
+}
+
+func TestGetStoreLag(t *testing.T) {
+	re := require.New(t)
+	_, err := getStoreLag(&metapb.Store{Id: 1}, time.Now())
+	re.Error(err)
+
+	lastHeartbeat := time.Now()
+	store := &metapb.Store{Id: 1, LastHeartbeat: lastHeartbeat.UnixNano()}
+	lag, err := getStoreLag(store, lastHeartbeat.Add(time.Second))
+	re.NoError(err)
+	re.Equal(time.Second, lag)
+	lag, err = getStoreLag(store, lastHeartbeat.Add(2*time.Second))
+	re.NoError(err)
+	re.Equal(2*time.Second, lag)
+	// The lag should never be negative because of the clock skew.
+	lag, err = getStoreLag(store, lastHeartbeat.Add(-time.Second))
+	re.NoError(err)
+	re.Zero(lag)
+}
+
+func TestStreamAllRegions(t *testing.T) {
+	re := require.New(t)
+	ctx := context.Background()
+	const regionCount = 1000
+	cli := &mockRegionClient{}
+	for i := 0; i < regionCount; i++ {
+		region := &metapb.Region{Id: uint64(i + 1), StartKey: []byte{}, EndKey: []byte{}}
+		if i > 0 {
+			region.StartKey = []byte(fmt.Sprintf("%04d", i))
+		}
+		if i < regionCount-1 {
+			region.EndKey = []byte(fmt.Sprintf("%04d", i+1))
+		}
+		cli.regions = append(cli.regions, region)
+	}
+	seen := make(map[uint64]int)
+	err := streamAllRegions(ctx, cli, 64, func(region *Region) bool {
+		seen[region.Meta.GetId()]++
+		return true
+	})
+	re.NoError(err)
+	re.Len(seen, regionCount)
+	for _, count := range seen {
+		re.Equal(1, count)
+	}
+
+	// It stops once the callback returns false.
+	streamed := 0
+	err = streamAllRegions(ctx, cli, 64, func(*Region) bool {
+		streamed++
+		return streamed < 100
+	})
+	re.NoError(err)
+	re.Equal(100, streamed)
+
+	// It can be canceled.
+	cctx, cancel := context.WithCancel(ctx)
+	streamed = 0
+	err = streamAllRegions(cctx, cli, 64, func(*Region) bool {
+		streamed++
+		if streamed == 10 {
+			cancel()
+		}
+		return true
+	})
+	re.ErrorIs(err, context.Canceled)
+	re.Equal(64, streamed)
+}
+
+func TestFilterRegionsByLeaderStores(t *testing.T) {
+	re := require.New(t)
+	regions := make([]*Region, 0, 4)
+	for i := uint64(1); i <= 4; i++ {
+		regions = append(regions, &Region{
+			Meta:   &metapb.Region{Id: i},
+			Leader: &metapb.Peer{Id: i + 10, StoreId: i},
+		})
+	}
+	// A region without leader is never matched.
+	regions = append(regions, &Region{Meta: &metapb.Region{Id: 5}, Leader: &metapb.Peer{}})
+	ids := func(regions []*Region) []uint64 {
+		ids := make([]uint64, 0, len(regions))
+		for _, region := range regions {
+			ids = append(ids, region.Meta.GetId())
+		}
+		return ids
+	}
+	re.Len(filterRegionsByLeaderStores(regions, nil), 5)
+	re.Equal([]uint64{1, 3}, ids(filterRegionsByLeaderStores(regions, []uint64{1, 3})))
+	re.Equal([]uint64{4}, ids(filterRegionsByLeaderStores(regions, []uint64{4, 6})))
+	re.Empty(filterRegionsByLeaderStores(regions, []uint64{6}))
+
+	// Streaming with the filter still walks through all regions.
+	cli := &mockRegionClient{}
+	for i, key := range []string{"", "b", "c", "d"} {
+		endKey := []string{"b", "c", "d", ""}[i]
+		cli.regions = append(cli.regions, &metapb.Region{Id: uint64(i + 1), StartKey: []byte(key), EndKey: []byte(endKey)})
+	}
+	cli.leaders = map[uint64]uint64{1: 1, 2: 2, 3: 2, 4: 1}
+	var streamed []uint64
+	err := streamAllRegions(context.Background(), cli, 1, func(region *Region) bool {
+		streamed = append(streamed, region.Meta.GetId())
+		return true
+	}, WithLeaderOnStores([]uint64{1}))
+	re.NoError(err)
+	re.Equal([]uint64{1, 4}, streamed)
+
+	// The scan goes on until the limit of the matched regions is met.
+	scan := func(key, endKey []byte, limit int, storeIDs ...uint64) []uint64 {
+		cli.scanCount = 0
+		regions, err := cli.ScanRegions(context.Background(), key, endKey, limit, WithLeaderOnStores(storeIDs))
+		re.NoError(err)
+		return ids(regions)
+	}
+	re.Equal([]uint64{1, 4}, scan([]byte{}, nil, 2, 1))
+	re.Equal(2, cli.scanCount)
+	re.Equal([]uint64{2}, scan([]byte{}, nil, 1, 2))
+	re.Equal(2, cli.scanCount)
+	// Or no region is left in the range.
+	re.Equal([]uint64{1}, scan([]byte{}, []byte("d"), 2, 1))
+	re.Equal(2, cli.scanCount)
+	re.Empty(scan([]byte{}, nil, 2, 3))
+	re.Equal(2, cli.scanCount)
+	// A single scan is enough without the filter or the limit.
+	re.Equal([]uint64{1, 2}, scan([]byte{}, nil, 2))
+	re.Equal(1, cli.scanCount)
+	re.Equal([]uint64{1, 4}, scan([]byte{}, nil, 0, 1))
+	re.Equal(1, cli.scanCount)
+}
+
+func TestSortRegionsByLeaderStore(t *testing.T) {
+	re := require.New(t)
+	cli := &mockRegionClient{}
+	for i, key := range []string{"", "b", "c", "d", "e"} {
+		endKey := []string{"b", "c", "d", "e", ""}[i]
+		cli.regions = append(cli.regions, &metapb.Region{Id: uint64(i + 1), StartKey: []byte(key), EndKey: []byte(endKey)})
+	}
+	// Region 5 has no leader.
+	cli.leaders = map[uint64]uint64{1: 3, 2: 1, 3: 3, 4: 1}
+	ids := func(regions []*Region) []uint64 {
+		ids := make([]uint64, 0, len(regions))
+		for _, region := range regions {
+			ids = append(ids, region.Meta.GetId())
+		}
+		return ids
+	}
+	regions, err := cli.ScanRegions(context.Background(), []byte{}, nil, 10, WithSortByLeaderStore())
+	re.NoError(err)
+	re.Equal([]uint64{5, 2, 4, 1, 3}, ids(regions))
+	regions, err = cli.ScanRegions(context.Background(), []byte{}, nil, 10,
+		WithSortByLeaderStore(), WithLeaderOnStores([]uint64{3, 1}))
+	re.NoError(err)
+	re.Equal([]uint64{2, 4, 1, 3}, ids(regions))
+
+	// Streaming is always in the key order.
+	var streamed []uint64
+	err = streamAllRegions(context.Background(), cli, 2, func(region *Region) bool {
+		streamed = append(streamed, region.Meta.GetId())
+		return true
+	}, WithSortByLeaderStore())
+	re.NoError(err)
+	re.Equal([]uint64{1, 2, 3, 4, 5}, streamed)
+}
+
+func TestPreferHealthyReplication(t *testing.T) {
+	re := require.New(t)
+	peer := &metapb.Peer{Id: 10, StoreId: 2}
+	regions := []*Region{
+		{Meta: &metapb.Region{Id: 1}, Leader: &metapb.Peer{StoreId: 3}},
+		{Meta: &metapb.Region{Id: 2}, Leader: &metapb.Peer{StoreId: 1}, DownPeers: []*metapb.Peer{peer}},
+		{Meta: &metapb.Region{Id: 3}, Leader: &metapb.Peer{StoreId: 1}},
+		{Meta: &metapb.Region{Id: 4}, Leader: &metapb.Peer{StoreId: 1}, PendingPeers: []*metapb.Peer{peer}},
+		{Meta: &metapb.Region{Id: 5}, Leader: &metapb.Peer{StoreId: 2}},
+	}
+	ids := func(regions []*Region) []uint64 {
+		ids := make([]uint64, 0, len(regions))
+		for _, region := range regions {
+			ids = append(ids, region.Meta.GetId())
+		}
+		return ids
+	}
+	options := func(opts ...GetRegionOption) *GetRegionOp {
+		op := &GetRegionOp{}
+		for _, opt := range opts {
+			opt(op)
+		}
+		return op
+	}
+	// The under-replicated regions are moved to the end in the key order.
+	re.Equal([]uint64{1, 3, 5, 2, 4}, ids(handleScannedRegions(slices.Clone(regions), options(WithPreferHealthyReplication()))))
+	// Each part keeps the order of the leader stores.
+	re.Equal([]uint64{3, 5, 1, 2, 4}, ids(handleScannedRegions(slices.Clone(regions),
+		options(WithPreferHealthyReplication(), WithSortByLeaderStore()))))
+	re.Equal([]uint64{1, 2, 3, 4, 5}, ids(handleScannedRegions(slices.Clone(regions), options())))
+}
+
+// mockRawRegionServer serves the region lookups with the fields dropped by Region.
+type mockRawRegionServer struct {
+	pdpb.UnimplementedPDServer
+}
+
+func (*mockRawRegionServer) newResponse(regionID uint64) *pdpb.GetRegionResponse {
+	peers := []*metapb.Peer{{Id: 11, StoreId: 1}, {Id: 12, StoreId: 2}}
+	return &pdpb.GetRegionResponse{
+		Header:    &pdpb.ResponseHeader{ClusterId: 100},
+		Region:    &metapb.Region{Id: regionID, Peers: peers},
+		Leader:    peers[0],
+		DownPeers: []*pdpb.PeerStats{{Peer: peers[1], DownSeconds: 60}},
+	}
+}
+
+func (s *mockRawRegionServer) GetRegion(context.Context, *pdpb.GetRegionRequest) (*pdpb.GetRegionResponse, error) {
+	return s.newResponse(1), nil
+}
+
+func (s *mockRawRegionServer) GetRegionByID(_ context.Context, req *pdpb.GetRegionByIDRequest) (*pdpb.GetRegionResponse, error) {
+	return s.newResponse(req.GetRegionId()), nil
+}
+
+func TestWithRawResponse(t *testing.T) {
+	re := require.New(t)
+	ctx := context.Background()
+	c := newClientWithMockPDServer(t, &mockRawRegionServer{})
+
+	var raw pdpb.GetRegionResponse
+	region, err := c.GetRegion(ctx, []byte("a"), WithRawResponse(&raw))
+	re.NoError(err)
+	re.Equal(uint64(100), raw.GetHeader().GetClusterId())
+	re.Equal(region.Meta, raw.GetRegion())
+	re.Equal(region.Leader, raw.GetLeader())
+	re.Equal(uint64(60), raw.GetDownPeers()[0].GetDownSeconds())
+	re.Equal(region.DownPeers, []*metapb.Peer{raw.GetDownPeers()[0].GetPeer()})
+
+	region, err = c.GetRegionByID(ctx, 2, WithRawResponse(&raw))
+	re.NoError(err)
+	re.Equal(uint64(2), raw.GetRegion().GetId())
+	re.Equal(region.Meta, raw.GetRegion())
+	// The sink is left untouched if the request fails.
+	_, err = c.GetPrevRegion(ctx, []byte("a"), WithRawResponse(&raw))
+	re.Error(err)
+	re.Equal(uint64(2), raw.GetRegion().GetId())
+}
+
+// mockSlowRegionServer serves the region lookups once they are released.
+type mockSlowRegionServer struct {
+	pdpb.UnimplementedPDServer
+	received chan struct{}
+	release  chan struct{}
+}
+
+func (s *mockSlowRegionServer) GetRegion(context.Context, *pdpb.GetRegionRequest) (*pdpb.GetRegionResponse, error) {
+	s.received <- struct{}{}
+	<-s.release
+	return &pdpb.GetRegionResponse{Header: &pdpb.ResponseHeader{}, Region: &metapb.Region{Id: 1}}, nil
+}
+
+func TestCloseGracefully(t *testing.T) {
+	re := require.New(t)
+	ctx := context.Background()
+	regionServer := &mockSlowRegionServer{received: make(chan struct{}, 1), release: make(chan struct{})}
+	c := newClientWithMockPDServer(t, regionServer)
+
+	regionCh := make(chan *Region, 1)
+	go func() {
+		region, err := c.GetRegion(ctx, []byte("a"))
+		re.NoError(err)
+		regionCh <- region
+	}()
+	<-regionServer.received
+	closeErrCh := make(chan error, 1)
+	go func() {
+		closeErrCh <- c.CloseGracefully(ctx)
+	}()
+	// The new requests are rejected once the client is closing.
+	testutil.Eventually(re, func() bool {
+		_, err := c.GetRegion(ctx, []byte("b"))
+		return errors.Cause(err) == errClosing
+	})
+	// The in-flight request finishes before the client is closed.
+	select {
+	case <-closeErrCh:
+		re.FailNow("the client is closed with the in-flight request")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(regionServer.release)
+	re.Equal(uint64(1), (<-regionCh).Meta.GetId())
+	re.NoError(<-closeErrCh)
+	re.Error(c.ctx.Err())
+}
+
+func TestCloseGracefullyTimeout(t *testing.T) {
+	re := require.New(t)
+	regionServer := &mockSlowRegionServer{received: make(chan struct{}, 1), release: make(chan struct{})}
+	c := newClientWithMockPDServer(t, regionServer)
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := c.GetRegion(context.Background(), []byte("a"))
+		errCh <- err
+	}()
+	<-regionServer.received
+	// The client is closed anyway once the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	re.ErrorIs(c.CloseGracefully(ctx), context.DeadlineExceeded)
+	re.Error(c.ctx.Err())
+	close(regionServer.release)
+	<-errCh
+}
+
+// mockCountingRegionServer counts the region requests it receives.
+type mockCountingRegionServer struct {
+	pdpb.UnimplementedPDServer
+	count atomic.Int32
+}
+
+func (s *mockCountingRegionServer) GetRegion(context.Context, *pdpb.GetRegionRequest) (*pdpb.GetRegionResponse, error) {
+	s.count.Add(1)
+	return &pdpb.GetRegionResponse{Header: &pdpb.ResponseHeader{}, Region: &metapb.Region{Id: 1}}, nil
+}
+
+func (s *mockCountingRegionServer) GetRegionByID(_ context.Context, req *pdpb.GetRegionByIDRequest) (*pdpb.GetRegionResponse, error) {
+	s.count.Add(1)
+	return &pdpb.GetRegionResponse{Header: &pdpb.ResponseHeader{}, Region: &metapb.Region{Id: req.GetRegionId()}}, nil
+}
+
+func (s *mockCountingRegionServer) ScanRegions(context.Context, *pdpb.ScanRegionsRequest) (*pdpb.ScanRegionsResponse, error) {
+	s.count.Add(1)
+	return &pdpb.ScanRegionsResponse{Header: &pdpb.ResponseHeader{}}, nil
+}
+
+func TestLeaderOnlyClient(t *testing.T) {
+	re := require.New(t)
+	ctx := context.Background()
+	leader, follower := &mockCountingRegionServer{}, &mockCountingRegionServer{}
+	c := newClientWithMockPDServer(t, leader)
+	sd := c.pdSvcDiscovery
+	followerURL := startMockPDServer(t, follower)
+	conn, err := sd.GetOrCreateGRPCConn(followerURL)
+	re.NoError(err)
+	sd.followers.Store(followerURL, newPDServiceClient(followerURL, sd.getLeaderServiceClient().GetURL(), conn, false))
+	sd.updateServiceClientCandidates()
+	c.option.setEnableFollowerHandle(true)
+
+	sendRequests := func() {
+		for i := 0; i < 10; i++ {
+			_, err := c.GetRegion(ctx, []byte("a"), WithAllowFollowerHandle())
+			re.NoError(err)
+			_, err = c.GetRegionByID(ctx, 1, WithAllowFollowerHandle())
+			re.NoError(err)
+			_, err = c.ScanRegions(ctx, []byte("a"), nil, 10, WithAllowFollowerHandle())
+			re.NoError(err)
+		}
+	}
+	// The follower handles some of the requests once it's allowed.
+	sendRequests()
+	re.Positive(follower.count.Load())
+
+	// The leader-only client never sends a request to the follower, even if
+	// the follower handle is enabled and allowed by the requests.
+	WithLeaderOnlyOption(true)(c)
+	leader.count.Store(0)
+	follower.count.Store(0)
+	sendRequests()
+	re.Equal(int32(30), leader.count.Load())
+	re.Zero(follower.count.Load())
+}
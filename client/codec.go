@@ -0,0 +1,182 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pingcap/errors"
+)
+
+var (
+	tablePrefix  = []byte{'t'}
+	recordPrefix = []byte("_r")
+	indexPrefix  = []byte("_i")
+)
+
+const (
+	signMask uint64 = 0x8000000000000000
+
+	encGroupSize = 8
+	encMarker    = byte(0xFF)
+	encPad       = byte(0x0)
+)
+
+var pads = make([]byte, encGroupSize)
+
+// EncodeBytes encodes the raw key into the memcomparable format used by the region keys.
+// It guarantees the encoded value is in ascending order for comparison,
+// encoding with the following rule:
+//
+//	[group1][marker1]...[groupN][markerN]
+//	group is 8 bytes slice which is padding with 0.
+//	marker is `0xFF - padding 0 count`
+//
+// For example:
+//
+//	[] -> [0, 0, 0, 0, 0, 0, 0, 0, 247]
+//	[1, 2, 3] -> [1, 2, 3, 0, 0, 0, 0, 0, 250]
+//	[1, 2, 3, 0] -> [1, 2, 3, 0, 0, 0, 0, 0, 251]
+//	[1, 2, 3, 4, 5, 6, 7, 8] -> [1, 2, 3, 4, 5, 6, 7, 8, 255, 0, 0, 0, 0, 0, 0, 0, 0, 247]
+//
+// Refer: https://github.com/facebook/mysql-5.6/wiki/MyRocks-record-format#memcomparable-format
+func EncodeBytes(data []byte) []byte {
+	// Allocate more space to avoid unnecessary slice growing.
+	// Assume that the byte slice size is about `(len(data) / encGroupSize + 1) * (encGroupSize + 1)` bytes,
+	// that is `(len(data) / 8 + 1) * 9` in our implement.
+	dLen := len(data)
+	result := make([]byte, 0, (dLen/encGroupSize+1)*(encGroupSize+1))
+	for idx := 0; idx <= dLen; idx += encGroupSize {
+		remain := dLen - idx
+		padCount := 0
+		if remain >= encGroupSize {
+			result = append(result, data[idx:idx+encGroupSize]...)
+		} else {
+			padCount = encGroupSize - remain
+			result = append(result, data[idx:]...)
+			result = append(result, pads[:padCount]...)
+		}
+
+		marker := encMarker - byte(padCount)
+		result = append(result, marker)
+	}
+	return result
+}
+
+// DecodeBytes decodes the bytes encoded by EncodeBytes.
+func DecodeBytes(b []byte) ([]byte, error) {
+	buf := make([]byte, 0, len(b))
+	for {
+		if len(b) < encGroupSize+1 {
+			return nil, errors.New("insufficient bytes to decode value")
+		}
+
+		groupBytes := b[:encGroupSize+1]
+
+		group := groupBytes[:encGroupSize]
+		marker := groupBytes[encGroupSize]
+
+		padCount := encMarker - marker
+		if padCount > encGroupSize {
+			return nil, errors.Errorf("invalid marker byte, group bytes %q", groupBytes)
+		}
+
+		realGroupSize := encGroupSize - padCount
+		buf = append(buf, group[:realGroupSize]...)
+		b = b[encGroupSize+1:]
+
+		if padCount != 0 {
+			// Check validity of padding bytes.
+			for _, v := range group[realGroupSize:] {
+				if v != encPad {
+					return nil, errors.Errorf("invalid padding byte, group bytes %q", groupBytes)
+				}
+			}
+			break
+		}
+	}
+	return buf, nil
+}
+
+// encodeInt appends the int64 value to b in the ascending order for comparison.
+func encodeInt(b []byte, v int64) []byte {
+	var data [8]byte
+	binary.BigEndian.PutUint64(data[:], uint64(v)^signMask)
+	return append(b, data[:]...)
+}
+
+// decodeInt decodes the value encoded by encodeInt and returns the leftover bytes.
+func decodeInt(b []byte) ([]byte, int64, error) {
+	if len(b) < 8 {
+		return nil, 0, errors.New("insufficient bytes to decode value")
+	}
+	return b[8:], int64(binary.BigEndian.Uint64(b[:8]) ^ signMask), nil
+}
+
+// TableRegionKey returns the key to look up the region of the start of the
+// TiDB table, i.e. the encoded `t{tableID}`.
+func TableRegionKey(tableID int64) []byte {
+	buf := make([]byte, 0, len(tablePrefix)+8)
+	buf = append(buf, tablePrefix...)
+	return EncodeBytes(encodeInt(buf, tableID))
+}
+
+// TableRowRegionKey returns the key to look up the region of the TiDB row,
+// i.e. the encoded `t{tableID}_r{rowID}`.
+func TableRowRegionKey(tableID, rowID int64) []byte {
+	buf := make([]byte, 0, len(tablePrefix)+len(recordPrefix)+8*2)
+	buf = append(buf, tablePrefix...)
+	buf = encodeInt(buf, tableID)
+	buf = append(buf, recordPrefix...)
+	return EncodeBytes(encodeInt(buf, rowID))
+}
+
+// TableIndexRegionKey returns the key to look up the region of the start of
+// the TiDB index, i.e. the encoded `t{tableID}_i{indexID}`.
+func TableIndexRegionKey(tableID, indexID int64) []byte {
+	buf := make([]byte, 0, len(tablePrefix)+len(indexPrefix)+8*2)
+	buf = append(buf, tablePrefix...)
+	buf = encodeInt(buf, tableID)
+	buf = append(buf, indexPrefix...)
+	return EncodeBytes(encodeInt(buf, indexID))
+}
+
+// DecodeTableRowRegionKey decodes the table ID and the row ID from the key
+// returned by TableRowRegionKey, e.g. the region bound split at a row.
+func DecodeTableRowRegionKey(key []byte) (tableID, rowID int64, err error) {
+	rawKey, err := DecodeBytes(key)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !bytes.HasPrefix(rawKey, tablePrefix) {
+		return 0, 0, errors.Errorf("invalid table row key %q", rawKey)
+	}
+	rawKey, tableID, err = decodeInt(rawKey[len(tablePrefix):])
+	if err != nil {
+		return 0, 0, err
+	}
+	if !bytes.HasPrefix(rawKey, recordPrefix) {
+		return 0, 0, errors.Errorf("invalid table row key %q", rawKey)
+	}
+	rawKey, rowID, err = decodeInt(rawKey[len(recordPrefix):])
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(rawKey) > 0 {
+		return 0, 0, errors.Errorf("invalid table row key with the trailing bytes %q", rawKey)
+	}
+	return tableID, rowID, nil
+}
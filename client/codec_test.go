@@ -0,0 +1,140 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytesCodec(t *testing.T) {
+	inputs := []struct {
+		enc []byte
+		dec []byte
+	}{
+		{[]byte{}, []byte{0, 0, 0, 0, 0, 0, 0, 0, 247}},
+		{[]byte{0}, []byte{0, 0, 0, 0, 0, 0, 0, 0, 248}},
+		{[]byte{1, 2, 3}, []byte{1, 2, 3, 0, 0, 0, 0, 0, 250}},
+		{[]byte{1, 2, 3, 0}, []byte{1, 2, 3, 0, 0, 0, 0, 0, 251}},
+		{[]byte{1, 2, 3, 4, 5, 6, 7}, []byte{1, 2, 3, 4, 5, 6, 7, 0, 254}},
+		{[]byte{0, 0, 0, 0, 0, 0, 0, 0}, []byte{0, 0, 0, 0, 0, 0, 0, 0, 255, 0, 0, 0, 0, 0, 0, 0, 0, 247}},
+		{[]byte{1, 2, 3, 4, 5, 6, 7, 8}, []byte{1, 2, 3, 4, 5, 6, 7, 8, 255, 0, 0, 0, 0, 0, 0, 0, 0, 247}},
+		{[]byte{1, 2, 3, 4, 5, 6, 7, 8, 9}, []byte{1, 2, 3, 4, 5, 6, 7, 8, 255, 9, 0, 0, 0, 0, 0, 0, 0, 248}},
+	}
+
+	for _, input := range inputs {
+		b := EncodeBytes(input.enc)
+		require.Equal(t, input.dec, b)
+
+		d, err := DecodeBytes(b)
+		require.NoError(t, err)
+		require.Equal(t, input.enc, d)
+	}
+
+	// Test error decode.
+	errInputs := [][]byte{
+		{1, 2, 3, 4},
+		{0, 0, 0, 0, 0, 0, 0, 247},
+		{0, 0, 0, 0, 0, 0, 0, 0, 246},
+		{0, 0, 0, 0, 0, 0, 0, 1, 247},
+		{1, 2, 3, 4, 5, 6, 7, 8, 0},
+		{1, 2, 3, 4, 5, 6, 7, 8, 255, 1},
+		{1, 2, 3, 4, 5, 6, 7, 8, 255, 1, 2, 3, 4, 5, 6, 7, 8},
+		{1, 2, 3, 4, 5, 6, 7, 8, 255, 1, 2, 3, 4, 5, 6, 7, 8, 255},
+		{1, 2, 3, 4, 5, 6, 7, 8, 255, 1, 2, 3, 4, 5, 6, 7, 8, 0},
+	}
+
+	for _, input := range errInputs {
+		_, err := DecodeBytes(input)
+		require.Error(t, err)
+	}
+}
+
+func TestTableRowRegionKey(t *testing.T) {
+	re := require.New(t)
+	testCases := []struct {
+		tableID, rowID int64
+	}{
+		{1, 1},
+		{100, -1},
+		{math.MaxInt64, math.MinInt64},
+		{math.MinInt64, math.MaxInt64},
+	}
+	for _, testCase := range testCases {
+		key := TableRowRegionKey(testCase.tableID, testCase.rowID)
+		tableID, rowID, err := DecodeTableRowRegionKey(key)
+		re.NoError(err)
+		re.Equal(testCase.tableID, tableID)
+		re.Equal(testCase.rowID, rowID)
+	}
+
+	// The keys are in the order of the table, then the index and then the rows.
+	re.Negative(bytes.Compare(TableRegionKey(1), TableIndexRegionKey(1, 1)))
+	re.Negative(bytes.Compare(TableIndexRegionKey(1, 1), TableIndexRegionKey(1, 2)))
+	re.Negative(bytes.Compare(TableIndexRegionKey(1, 2), TableRowRegionKey(1, -1)))
+	re.Negative(bytes.Compare(TableRowRegionKey(1, -1), TableRowRegionKey(1, 1)))
+	re.Negative(bytes.Compare(TableRowRegionKey(1, math.MaxInt64), TableRegionKey(2)))
+
+	for _, key := range [][]byte{
+		nil,
+		[]byte("t"),
+		TableRegionKey(1),
+		TableIndexRegionKey(1, 1),
+		EncodeBytes(append(append([]byte(nil), mustDecodeBytes(re, TableRowRegionKey(1, 1))...), 'x')),
+	} {
+		_, _, err := DecodeTableRowRegionKey(key)
+		re.Error(err)
+	}
+}
+
+func mustDecodeBytes(re *require.Assertions, key []byte) []byte {
+	rawKey, err := DecodeBytes(key)
+	re.NoError(err)
+	return rawKey
+}
+
+func TestGetRegionByTableRowRegionKey(t *testing.T) {
+	re := require.New(t)
+	cli := &mockRegionClient{regions: []*metapb.Region{
+		{Id: 1, StartKey: []byte{}, EndKey: TableRegionKey(1)},
+		{Id: 2, StartKey: TableRegionKey(1), EndKey: TableRowRegionKey(1, 100)},
+		{Id: 3, StartKey: TableRowRegionKey(1, 100), EndKey: TableRegionKey(2)},
+		{Id: 4, StartKey: TableRegionKey(2), EndKey: []byte{}},
+	}}
+	for _, testCase := range []struct {
+		key      []byte
+		regionID uint64
+	}{
+		{TableIndexRegionKey(1, 1), 2},
+		{TableRowRegionKey(1, 99), 2},
+		{TableRowRegionKey(1, 100), 3},
+		{TableRowRegionKey(1, math.MaxInt64), 3},
+		{TableRowRegionKey(2, 1), 4},
+	} {
+		region, err := cli.GetRegion(context.Background(), testCase.key)
+		re.NoError(err)
+		re.Equal(testCase.regionID, region.Meta.GetId())
+	}
+
+	tableID, rowID, err := DecodeTableRowRegionKey(cli.regions[2].GetStartKey())
+	re.NoError(err)
+	re.Equal(int64(1), tableID)
+	re.Equal(int64(100), rowID)
+}
@@ -57,6 +57,9 @@ var (
 	ErrClientGetServingEndpoint       = errors.Normalize("get serving endpoint failed", errors.RFCCodeText("PD:client:ErrClientGetServingEndpoint"))
 	ErrClientFindGroupByKeyspaceID    = errors.Normalize("can't find keyspace group by keyspace id", errors.RFCCodeText("PD:client:ErrClientFindGroupByKeyspaceID"))
 	ErrClientWatchGCSafePointV2Stream = errors.Normalize("watch gc safe point v2 stream failed", errors.RFCCodeText("PD:client:ErrClientWatchGCSafePointV2Stream"))
+	ErrClientResponseTooLarge         = errors.Normalize("the response is larger than the max receive message size %d", errors.RFCCodeText("PD:client:ErrClientResponseTooLarge"))
+	ErrClientNoSafeTS                 = errors.Normalize("store %d has no safe ts yet", errors.RFCCodeText("PD:client:ErrClientNoSafeTS"))
+	ErrClientDialTimeout              = errors.Normalize("failed to connect to PD in %s", errors.RFCCodeText("PD:client:ErrClientDialTimeout"))
 )
 
 // grpcutil errors
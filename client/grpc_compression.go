@@ -0,0 +1,76 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	// Register the gzip compressor.
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
+)
+
+// compressionInterceptor compresses the unary requests with the given compressor.
+// Once the server reports that it can't decompress the requests, the
+// compression is disabled and the request is retried without compression.
+type compressionInterceptor struct {
+	name     string
+	disabled atomic.Bool
+}
+
+// newCompressionInterceptor returns a unary client interceptor compressing the
+// requests, it returns nil if the compressor is not registered.
+func newCompressionInterceptor(name string) grpc.UnaryClientInterceptor {
+	if encoding.GetCompressor(name) == nil {
+		log.Warn("[pd] the gRPC compressor is not registered, the compression is disabled",
+			zap.String("compressor", name))
+		return nil
+	}
+	ci := &compressionInterceptor{name: name}
+	return ci.intercept
+}
+
+func (ci *compressionInterceptor) intercept(
+	ctx context.Context, method string, req, reply any,
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	if ci.disabled.Load() {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.UseCompressor(ci.name))...)
+	if !isCompressionUnsupported(err) {
+		return err
+	}
+	if ci.disabled.CompareAndSwap(false, true) {
+		log.Warn("[pd] the server doesn't support the gRPC compressor, the compression is disabled",
+			zap.String("compressor", ci.name), zap.String("target", cc.Target()), zap.Error(err))
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// isCompressionUnsupported checks whether the error is caused by the server
+// not supporting the compressor of the request.
+func isCompressionUnsupported(err error) bool {
+	st, ok := status.FromError(err)
+	return ok && st.Code() == codes.Unimplemented &&
+		strings.Contains(st.Message(), "Decompressor is not installed")
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func compressorOf(opts []grpc.CallOption) string {
+	var name string
+	for _, opt := range opts {
+		if c, ok := opt.(grpc.CompressorCallOption); ok {
+			name = c.CompressorType
+		}
+	}
+	return name
+}
+
+func TestCompressionInterceptor(t *testing.T) {
+	re := require.New(t)
+	re.Nil(newCompressionInterceptor("unknown"))
+	interceptor := newCompressionInterceptor("gzip")
+	re.NotNil(interceptor)
+
+	var compressors []string
+	supported := true
+	invoker := func(_ context.Context, _ string, _, _ any, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+		compressor := compressorOf(opts)
+		compressors = append(compressors, compressor)
+		if compressor != "" && !supported {
+			return status.Errorf(codes.Unimplemented, "grpc: Decompressor is not installed for grpc-encoding %q", compressor)
+		}
+		return nil
+	}
+	cc := &grpc.ClientConn{}
+	re.NoError(interceptor(context.Background(), "method", nil, nil, cc, invoker))
+	re.Equal([]string{"gzip"}, compressors)
+
+	// Fall back to no compression if the server doesn't support it.
+	supported = false
+	compressors = nil
+	re.NoError(interceptor(context.Background(), "method", nil, nil, cc, invoker))
+	re.Equal([]string{"gzip", ""}, compressors)
+	compressors = nil
+	re.NoError(interceptor(context.Background(), "method", nil, nil, cc, invoker))
+	re.Equal([]string{""}, compressors)
+
+	// The other errors are returned directly.
+	interceptor = newCompressionInterceptor("gzip")
+	compressors = nil
+	err := interceptor(context.Background(), "method", nil, nil, cc,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			compressors = append(compressors, "called")
+			return status.Error(codes.Unimplemented, "unknown method")
+		})
+	re.Error(err)
+	re.Len(compressors, 1)
+}
+
+func TestGRPCCompression(t *testing.T) {
+	re := require.New(t)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	re.NoError(err)
+	recvCompressors := make(chan string, 1)
+	server := grpc.NewServer(grpc.UnaryInterceptor(
+		func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if s, ok := grpc.ServerTransportStreamFromContext(ctx).(interface{ RecvCompress() string }); ok {
+				recvCompressors <- s.RecvCompress()
+			}
+			return handler(ctx, req)
+		}))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(lis)
+	defer server.Stop()
+
+	c := &client{option: newOption()}
+	WithGRPCCompression("gzip")(c)
+	re.Len(c.option.gRPCDialOptions, 1)
+	cc, err := grpc.Dial(lis.Addr().String(),
+		append(c.option.gRPCDialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+	re.NoError(err)
+	defer cc.Close()
+	resp, err := healthpb.NewHealthClient(cc).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	re.NoError(err)
+	re.Equal(healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	re.Equal("gzip", <-recvCompressors)
+
+	// An unknown compressor is ignored.
+	c = &client{option: newOption()}
+	WithGRPCCompression("unknown")(c)
+	re.Empty(c.option.gRPCDialOptions)
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tikv/pd/client/errs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newRecvMsgSizeInterceptor returns a unary client interceptor which converts
+// the error of an oversized response to errs.ErrClientResponseTooLarge.
+func newRecvMsgSizeInterceptor(maxRecvMsgSize int) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context, method string, req, reply any,
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if isResponseTooLarge(err) {
+			return errs.ErrClientResponseTooLarge.GenWithStackByArgs(maxRecvMsgSize)
+		}
+		return err
+	}
+}
+
+// isResponseTooLarge checks whether the error is caused by the response
+// exceeding the max receive message size.
+func isResponseTooLarge(err error) bool {
+	st, ok := status.FromError(err)
+	return ok && st.Code() == codes.ResourceExhausted &&
+		strings.Contains(st.Message(), "received message larger than max")
+}
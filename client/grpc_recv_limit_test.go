@@ -0,0 +1,80 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/client/errs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// mockRegionServer serves the region lookups with the region of the given peer count.
+type mockRegionServer struct {
+	pdpb.UnimplementedPDServer
+	peerCount atomic.Int32
+}
+
+func (s *mockRegionServer) GetRegion(context.Context, *pdpb.GetRegionRequest) (*pdpb.GetRegionResponse, error) {
+	region := &metapb.Region{Id: 1}
+	for i := 0; i < int(s.peerCount.Load()); i++ {
+		region.Peers = append(region.Peers, &metapb.Peer{Id: uint64(i + 1), StoreId: uint64(i + 1)})
+	}
+	return &pdpb.GetRegionResponse{Header: &pdpb.ResponseHeader{}, Region: region}, nil
+}
+
+func TestMaxRecvMsgSize(t *testing.T) {
+	re := require.New(t)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	re.NoError(err)
+	regionServer := &mockRegionServer{}
+	regionServer.peerCount.Store(3)
+	server := grpc.NewServer()
+	pdpb.RegisterPDServer(server, regionServer)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	c := &client{option: newOption()}
+	re.Equal(defaultMaxRecvMsgSize, c.option.maxRecvMsgSize)
+	WithMaxRecvMsgSize(1024)(c)
+	cc, err := grpc.Dial(lis.Addr().String(),
+		append(c.option.getGRPCDialOptions(), grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+	re.NoError(err)
+	defer cc.Close()
+	pdClient := pdpb.NewPDClient(cc)
+
+	resp, err := pdClient.GetRegion(context.Background(), &pdpb.GetRegionRequest{})
+	re.NoError(err)
+	re.Len(resp.GetRegion().GetPeers(), 3)
+
+	// The oversized response yields the typed error.
+	regionServer.peerCount.Store(1000)
+	_, err = pdClient.GetRegion(context.Background(), &pdpb.GetRegionRequest{})
+	re.Error(err)
+	re.True(errs.ErrClientResponseTooLarge.Equal(err))
+	re.Contains(err.Error(), "1024")
+
+	// The other errors are returned as is.
+	_, err = pdClient.GetMembers(context.Background(), &pdpb.GetMembersRequest{})
+	re.Error(err)
+	re.False(errs.ErrClientResponseTooLarge.Equal(err))
+}
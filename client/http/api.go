@@ -75,6 +75,7 @@ const (
 	MinResolvedTSPrefix = "/pd/api/v1/min-resolved-ts"
 	Cluster             = "/pd/api/v1/cluster"
 	ClusterStatus       = "/pd/api/v1/cluster/status"
+	ClusterStats        = "/pd/api/v1/cluster/stats"
 	Status              = "/pd/api/v1/status"
 	Version             = "/pd/api/v1/version"
 	operators           = "/pd/api/v1/operators"
@@ -102,6 +103,11 @@ func RegionsByKeyRange(keyRange *KeyRange, limit int) string {
 		regionsByKey, startKeyStr, endKeyStr, limit)
 }
 
+// OperatorRecordsByRegionID returns the path of PD HTTP API to get the recent finished operators of the region.
+func OperatorRecordsByRegionID(regionID uint64) string {
+	return fmt.Sprintf("%s/records/%d", operators, regionID)
+}
+
 // RegionsByStoreID returns the path of PD HTTP API to get regions by store ID.
 func RegionsByStoreID(storeID uint64) string {
 	return fmt.Sprintf("%s/%d", RegionsByStoreIDPrefix, storeID)
@@ -125,6 +131,11 @@ func RegionStatsByKeyRange(keyRange *KeyRange, onlyCount bool) string {
 		StatsRegion, startKeyStr, endKeyStr)
 }
 
+// MinResolvedTSByStoreID returns the store min resolved ts API with store ID parameter.
+func MinResolvedTSByStoreID(id uint64) string {
+	return fmt.Sprintf("%s/%d", MinResolvedTSPrefix, id)
+}
+
 // StoreByID returns the store API with store ID parameter.
 func StoreByID(id uint64) string {
 	return fmt.Sprintf("%s/%d", store, id)
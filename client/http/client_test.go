@@ -16,6 +16,9 @@ package http
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"strings"
 	"testing"
@@ -110,3 +113,106 @@ func TestWithTargetURL(t *testing.T) {
 	_, err = c.WithTargetURL("http://127.0.0.2").GetStatus(ctx)
 	re.ErrorContains(err, "connect: connection refused")
 }
+
+type mockRoundTripper func(req *http.Request) (*http.Response, error)
+
+func (rt mockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt(req)
+}
+
+func TestGetRegionOperatorHistory(t *testing.T) {
+	re := require.New(t)
+	body := `[]`
+	httpClient := &http.Client{Transport: mockRoundTripper(func(req *http.Request) (*http.Response, error) {
+		re.Equal(OperatorRecordsByRegionID(1), req.URL.Path)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	c := newClientWithMockServiceDiscovery("test-operator-history", []string{"http://127.0.0.1"}, WithHTTPClient(httpClient))
+	defer c.Close()
+
+	records, err := c.GetRegionOperatorHistory(context.Background(), 1)
+	re.NoError(err)
+	re.NotNil(records)
+	re.Empty(records)
+
+	body = `[{"region_id":1,"desc":"transfer-leader","kind":"leader","status":"SUCCESS","moves":[{"kind":"leader","from":1,"to":2}]}]`
+	records, err = c.GetRegionOperatorHistory(context.Background(), 1)
+	re.NoError(err)
+	re.Len(records, 1)
+	re.Equal("transfer-leader", records[0].Desc)
+	re.Equal("SUCCESS", records[0].Status)
+	re.Equal([]OperatorMove{{Kind: "leader", From: 1, To: 2}}, records[0].Moves)
+}
+
+func TestGetClusterStats(t *testing.T) {
+	re := require.New(t)
+	httpClient := &http.Client{Transport: mockRoundTripper(func(req *http.Request) (*http.Response, error) {
+		re.Equal(ClusterStats, req.URL.Path)
+		body := `{"store_count":3,"store_count_by_state":{"Serving":2,"Removing":1},"region_count":10,"leader_count":9}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	c := newClientWithMockServiceDiscovery("test-cluster-stats", []string{"http://127.0.0.1"}, WithHTTPClient(httpClient))
+	defer c.Close()
+
+	stats, err := c.GetClusterStats(context.Background())
+	re.NoError(err)
+	re.Equal(3, stats.StoreCount)
+	re.Equal(map[string]int{"Serving": 2, "Removing": 1}, stats.StoreCountByState)
+	re.Equal(10, stats.RegionCount)
+	re.Equal(9, stats.LeaderCount)
+}
+
+func TestGetSafeTS(t *testing.T) {
+	re := require.New(t)
+	safeTS := atomic.NewUint64(0)
+	httpClient := &http.Client{Transport: mockRoundTripper(func(req *http.Request) (*http.Response, error) {
+		re.Equal(MinResolvedTSByStoreID(1), req.URL.Path)
+		body := fmt.Sprintf(`{"is_real_time":true,"min_resolved_ts":%d,"persist_interval":"1s"}`, safeTS.Load())
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	c := newClientWithMockServiceDiscovery("test-safe-ts", []string{"http://127.0.0.1"}, WithHTTPClient(httpClient))
+	defer c.Close()
+
+	// The store has not reported the safe ts yet.
+	ts, err := c.GetSafeTS(context.Background(), 1)
+	re.True(errs.ErrClientNoSafeTS.Equal(err))
+	re.Zero(ts)
+	safeTS.Store(math.MaxUint64)
+	_, err = c.GetSafeTS(context.Background(), 1)
+	re.True(errs.ErrClientNoSafeTS.Equal(err))
+
+	safeTS.Store(100)
+	ts, err = c.GetSafeTS(context.Background(), 1)
+	re.NoError(err)
+	re.Equal(uint64(100), ts)
+
+	// Watch the safe ts advancing.
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := c.WatchSafeTS(ctx, 1, 10*time.Millisecond)
+	re.Equal(uint64(100), <-ch)
+	safeTS.Store(200)
+	re.Equal(uint64(200), <-ch)
+	cancel()
+	for range ch {
+	}
+}
+
+func TestGetSingleReplicaRegions(t *testing.T) {
+	re := require.New(t)
+	httpClient := &http.Client{Transport: mockRoundTripper(func(req *http.Request) (*http.Response, error) {
+		re.Equal(RegionsByStoreID(1), req.URL.Path)
+		body := `{"count":3,"regions":[
+			{"id":1,"peers":[{"id":11,"store_id":1}]},
+			{"id":2,"peers":[{"id":21,"store_id":1},{"id":22,"store_id":2},{"id":23,"store_id":3}]},
+			{"id":3,"peers":[{"id":31,"store_id":1},{"id":32,"store_id":2,"is_learner":true}]}]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	c := newClientWithMockServiceDiscovery("test-single-replica-regions", []string{"http://127.0.0.1"}, WithHTTPClient(httpClient))
+	defer c.Close()
+
+	regions, err := c.GetSingleReplicaRegions(context.Background(), 1)
+	re.NoError(err)
+	re.Equal(int64(1), regions.Count)
+	re.Len(regions.Regions, 1)
+	re.Equal(int64(1), regions.Regions[0].ID)
+}
@@ -18,15 +18,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/keyspacepb"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/client/errs"
 	"github.com/tikv/pd/client/retry"
+	"go.uber.org/zap"
 )
 
 // Client is a PD (Placement Driver) HTTP client.
@@ -41,6 +46,7 @@ type Client interface {
 	GetRegions(context.Context) (*RegionsInfo, error)
 	GetRegionsByKeyRange(context.Context, *KeyRange, int) (*RegionsInfo, error)
 	GetRegionsByStoreID(context.Context, uint64) (*RegionsInfo, error)
+	GetSingleReplicaRegions(context.Context, uint64) (*RegionsInfo, error)
 	GetEmptyRegions(context.Context) (*RegionsInfo, error)
 	GetRegionsReplicatedStateByKeyRange(context.Context, *KeyRange) (string, error)
 	GetHotReadRegions(context.Context) (*StoreHotPeersInfos, error)
@@ -58,6 +64,7 @@ type Client interface {
 	GetClusterVersion(context.Context) (string, error)
 	GetCluster(context.Context) (*metapb.Cluster, error)
 	GetClusterStatus(context.Context) (*ClusterState, error)
+	GetClusterStats(context.Context) (*ClusterStatistics, error)
 	GetStatus(context.Context) (*State, error)
 	GetReplicateConfig(context.Context) (map[string]any, error)
 	/* Scheduler-related interfaces */
@@ -91,11 +98,14 @@ type Client interface {
 	DeleteSnapshotRecoveringMark(context.Context) error
 	/* Other interfaces */
 	GetMinResolvedTSByStoresIDs(context.Context, []uint64) (uint64, map[uint64]uint64, error)
+	GetSafeTS(context.Context, uint64) (uint64, error)
+	WatchSafeTS(context.Context, uint64, time.Duration) <-chan uint64
 	GetPDVersion(context.Context) (string, error)
 	/* Micro Service interfaces */
 	GetMicroServiceMembers(context.Context, string) ([]MicroServiceMember, error)
 	GetMicroServicePrimary(context.Context, string) (string, error)
 	DeleteOperators(context.Context) error
+	GetRegionOperatorHistory(context.Context, uint64) ([]OperatorRecord, error)
 
 	/* Keyspace interface */
 
@@ -231,6 +241,23 @@ func (c *client) GetRegionsByStoreID(ctx context.Context, storeID uint64) (*Regi
 	return &regions, nil
 }
 
+// GetSingleReplicaRegions gets the regions whose only peer is on the given store,
+// which would be lost if the store is removed.
+func (c *client) GetSingleReplicaRegions(ctx context.Context, storeID uint64) (*RegionsInfo, error) {
+	regions, err := c.GetRegionsByStoreID(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	singleReplicaRegions := newRegionsInfo(0)
+	for _, region := range regions.Regions {
+		if len(region.Peers) == 1 && region.Peers[0].StoreID == int64(storeID) {
+			singleReplicaRegions.Regions = append(singleReplicaRegions.Regions, region)
+		}
+	}
+	singleReplicaRegions.Count = int64(len(singleReplicaRegions.Regions))
+	return singleReplicaRegions, nil
+}
+
 // GetEmptyRegions gets the empty regions info.
 func (c *client) GetEmptyRegions(ctx context.Context) (*RegionsInfo, error) {
 	var regions RegionsInfo
@@ -467,6 +494,20 @@ func (c *client) GetClusterStatus(ctx context.Context) (*ClusterState, error) {
 	return clusterStatus, nil
 }
 
+// GetClusterStats gets the store, region and leader totals of the cluster.
+func (c *client) GetClusterStats(ctx context.Context) (*ClusterStatistics, error) {
+	var clusterStats *ClusterStatistics
+	err := c.request(ctx, newRequestInfo().
+		WithName(getClusterStatsName).
+		WithURI(ClusterStats).
+		WithMethod(http.MethodGet).
+		WithResp(&clusterStats))
+	if err != nil {
+		return nil, err
+	}
+	return clusterStats, nil
+}
+
 // GetStatus gets the status of PD.
 func (c *client) GetStatus(ctx context.Context) (*State, error) {
 	var status *State
@@ -883,6 +924,63 @@ func (c *client) GetMinResolvedTSByStoresIDs(ctx context.Context, storeIDs []uin
 	return resp.MinResolvedTS, resp.StoresMinResolvedTS, nil
 }
 
+// GetSafeTS gets the safe ts of the store for the stale reads, which is the min resolved ts
+// reported by the store. It returns 0 with `errs.ErrClientNoSafeTS` if the store has not
+// reported it yet or is not available for the stale reads.
+func (c *client) GetSafeTS(ctx context.Context, storeID uint64) (uint64, error) {
+	resp := struct {
+		MinResolvedTS uint64 `json:"min_resolved_ts"`
+		IsRealTime    bool   `json:"is_real_time,omitempty"`
+	}{}
+	err := c.request(ctx, newRequestInfo().
+		WithName(getSafeTSName).
+		WithURI(MinResolvedTSByStoreID(storeID)).
+		WithMethod(http.MethodGet).
+		WithResp(&resp))
+	if err != nil {
+		return 0, err
+	}
+	if !resp.IsRealTime {
+		return 0, errors.Trace(errors.New("min resolved ts is not enabled"))
+	}
+	if resp.MinResolvedTS == 0 || resp.MinResolvedTS == math.MaxUint64 {
+		return 0, errs.ErrClientNoSafeTS.FastGenByArgs(storeID)
+	}
+	return resp.MinResolvedTS, nil
+}
+
+// WatchSafeTS polls the safe ts of the store with the given interval, and sends it to the
+// returned channel whenever it advances. The channel is closed once the context is done.
+func (c *client) WatchSafeTS(ctx context.Context, storeID uint64, interval time.Duration) <-chan uint64 {
+	ch := make(chan uint64, 1)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var lastSafeTS uint64
+		for {
+			safeTS, err := c.GetSafeTS(ctx, storeID)
+			if err != nil && !errs.ErrClientNoSafeTS.Equal(err) {
+				log.Warn("[pd] failed to get the safe ts", zap.Uint64("store-id", storeID), zap.Error(err))
+			}
+			if err == nil && safeTS > lastSafeTS {
+				select {
+				case ch <- safeTS:
+					lastSafeTS = safeTS
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
 // GetMicroServiceMembers gets the members of the microservice.
 func (c *client) GetMicroServiceMembers(ctx context.Context, service string) ([]MicroServiceMember, error) {
 	var members []MicroServiceMember
@@ -929,6 +1027,24 @@ func (c *client) DeleteOperators(ctx context.Context) error {
 		WithMethod(http.MethodDelete))
 }
 
+// GetRegionOperatorHistory gets the recent finished operators of the region in the finishing order.
+// It returns an empty slice if there is no such operator.
+func (c *client) GetRegionOperatorHistory(ctx context.Context, regionID uint64) ([]OperatorRecord, error) {
+	var records []OperatorRecord
+	err := c.request(ctx, newRequestInfo().
+		WithName(getRegionOperatorHistoryName).
+		WithURI(OperatorRecordsByRegionID(regionID)).
+		WithMethod(http.MethodGet).
+		WithResp(&records))
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		records = []OperatorRecord{}
+	}
+	return records, nil
+}
+
 // UpdateKeyspaceGCManagementType patches the keyspace config.
 func (c *client) UpdateKeyspaceGCManagementType(ctx context.Context, keyspaceName string, keyspaceGCmanagementType *KeyspaceGCManagementTypeConfig) error {
 	keyspaceConfigPatchJSON, err := json.Marshal(keyspaceGCmanagementType)
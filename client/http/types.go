@@ -34,6 +34,15 @@ type ClusterState struct {
 	ReplicationStatus string    `json:"replication_status"`
 }
 
+// ClusterStatistics is the store, region and leader totals of the cluster.
+// NOTE: This type sync with the `ClusterStats` in server/api/cluster.go.
+type ClusterStatistics struct {
+	StoreCount        int            `json:"store_count"`
+	StoreCountByState map[string]int `json:"store_count_by_state"`
+	RegionCount       int            `json:"region_count"`
+	LeaderCount       int            `json:"leader_count"`
+}
+
 // State is the status of PD server.
 // NOTE: This type sync with https://github.com/tikv/pd/blob/1d77b25656bc18e1f5aa82337d4ab62a34b10087/pkg/versioninfo/versioninfo.go#L29
 type State struct {
@@ -624,6 +633,25 @@ type MicroServiceMember struct {
 	StartTimestamp int64  `json:"start-timestamp"`
 }
 
+// OperatorRecord is a finished operator of a region.
+type OperatorRecord struct {
+	RegionID   uint64         `json:"region_id"`
+	Desc       string         `json:"desc"`
+	Brief      string         `json:"brief"`
+	Kind       string         `json:"kind"`
+	Status     string         `json:"status"`
+	Moves      []OperatorMove `json:"moves"`
+	CreateTime time.Time      `json:"create_time"`
+	FinishTime time.Time      `json:"finish_time"`
+}
+
+// OperatorMove is the source and target store of a leader or peer move of an operator.
+type OperatorMove struct {
+	Kind string `json:"kind"`
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
 // KeyspaceGCManagementType represents parameters needed to modify the gc management type.
 // If `gc_management_type` is `global_gc`, it means the current keyspace requires a tidb without 'keyspace-name'
 // configured to run a global gc worker to calculate a global gc safe point.
@@ -0,0 +1,73 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"context"
+	"sync"
+)
+
+// inflightRequests tracks the in-flight requests, so the client can wait for
+// them to finish before being closed. The zero value is ready to use.
+type inflightRequests struct {
+	mu      sync.Mutex
+	closing bool
+	count   int
+	// drained is closed once the client is closing and no request is in flight.
+	drained chan struct{}
+}
+
+// begin registers a new request, it returns false if the client is closing,
+// and the request should be rejected. end must be called if it returns true.
+func (r *inflightRequests) begin() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closing {
+		return false
+	}
+	r.count++
+	return true
+}
+
+// end unregisters a finished request.
+func (r *inflightRequests) end() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count--
+	if r.closing && r.count == 0 {
+		close(r.drained)
+	}
+}
+
+// closeAndWait rejects the new requests, and waits for the in-flight ones to
+// finish until the context is done.
+func (r *inflightRequests) closeAndWait(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closing {
+		r.closing = true
+		r.drained = make(chan struct{})
+		if r.count == 0 {
+			close(r.drained)
+		}
+	}
+	drained := r.drained
+	r.mu.Unlock()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
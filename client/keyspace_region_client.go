@@ -0,0 +1,150 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/metapb"
+)
+
+// keyspaceTxnModePrefix is the key prefix of the keyspace in the txn mode.
+const keyspaceTxnModePrefix = 'x'
+
+// KeyspaceRegionClient looks up the regions within the txn key range of a keyspace.
+// The keys passed in and the region bounds returned are keyspace-relative and not
+// encoded, the keyspace prefixing and the memcomparable encoding are handled inside.
+// The bounds of a region crossing the keyspace bounds are truncated to empty,
+// which means unbounded within the keyspace.
+type KeyspaceRegionClient struct {
+	cli        RPCClient
+	keyspaceID uint32
+	prefix     []byte
+	// startKey and endKey are the encoded key range of the keyspace.
+	startKey []byte
+	endKey   []byte
+}
+
+// NewKeyspaceRegionClient creates a KeyspaceRegionClient for the given keyspace.
+func NewKeyspaceRegionClient(cli RPCClient, keyspaceID uint32) *KeyspaceRegionClient {
+	prefix, nextPrefix := makeKeyspacePrefix(keyspaceID), makeKeyspacePrefix(keyspaceID+1)
+	if keyspaceID >= maxKeyspaceID {
+		nextPrefix = []byte{keyspaceTxnModePrefix + 1}
+	}
+	return &KeyspaceRegionClient{
+		cli:        cli,
+		keyspaceID: keyspaceID,
+		prefix:     prefix,
+		startKey:   EncodeBytes(prefix),
+		endKey:     EncodeBytes(nextPrefix),
+	}
+}
+
+// makeKeyspacePrefix returns the txn mode prefix of the keyspace, which
+// consists of the mode byte and the 3 lower bytes of the keyspace ID.
+func makeKeyspacePrefix(keyspaceID uint32) []byte {
+	idBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(idBytes, keyspaceID)
+	return append([]byte{keyspaceTxnModePrefix}, idBytes[1:]...)
+}
+
+// GetRegion gets the region which contains the keyspace-relative key.
+func (c *KeyspaceRegionClient) GetRegion(ctx context.Context, key []byte, opts ...GetRegionOption) (*Region, error) {
+	region, err := c.cli.GetRegion(ctx, c.encodeKey(key), opts...)
+	if err != nil || region == nil {
+		return region, err
+	}
+	return c.toKeyspaceRegion(region)
+}
+
+// GetPrevRegion gets the previous region of the region which contains the keyspace-relative key.
+// It returns nil if the previous region is out of the keyspace.
+func (c *KeyspaceRegionClient) GetPrevRegion(ctx context.Context, key []byte, opts ...GetRegionOption) (*Region, error) {
+	region, err := c.cli.GetPrevRegion(ctx, c.encodeKey(key), opts...)
+	if err != nil || region == nil {
+		return region, err
+	}
+	if !c.overlaps(region.Meta) {
+		return nil, nil
+	}
+	return c.toKeyspaceRegion(region)
+}
+
+// GetRegionByID gets the region by ID, it returns an error if the region is out of the keyspace.
+func (c *KeyspaceRegionClient) GetRegionByID(ctx context.Context, regionID uint64, opts ...GetRegionOption) (*Region, error) {
+	region, err := c.cli.GetRegionByID(ctx, regionID, opts...)
+	if err != nil || region == nil {
+		return region, err
+	}
+	if !c.overlaps(region.Meta) {
+		return nil, errors.Errorf("region %d is out of keyspace %d", regionID, c.keyspaceID)
+	}
+	return c.toKeyspaceRegion(region)
+}
+
+func (c *KeyspaceRegionClient) encodeKey(key []byte) []byte {
+	rawKey := make([]byte, 0, len(c.prefix)+len(key))
+	rawKey = append(rawKey, c.prefix...)
+	rawKey = append(rawKey, key...)
+	return EncodeBytes(rawKey)
+}
+
+func (c *KeyspaceRegionClient) overlaps(meta *metapb.Region) bool {
+	endKey := meta.GetEndKey()
+	return (len(endKey) == 0 || bytes.Compare(endKey, c.startKey) > 0) &&
+		bytes.Compare(meta.GetStartKey(), c.endKey) < 0
+}
+
+// toKeyspaceRegion returns a copy of the region with the keyspace-relative bounds.
+func (c *KeyspaceRegionClient) toKeyspaceRegion(region *Region) (*Region, error) {
+	meta := proto.Clone(region.Meta).(*metapb.Region)
+	startKey, endKey := meta.GetStartKey(), meta.GetEndKey()
+	if bytes.Compare(startKey, c.startKey) <= 0 {
+		meta.StartKey = []byte{}
+	} else {
+		key, err := c.decodeKey(startKey)
+		if err != nil {
+			return nil, err
+		}
+		meta.StartKey = key
+	}
+	if len(endKey) == 0 || bytes.Compare(endKey, c.endKey) >= 0 {
+		meta.EndKey = []byte{}
+	} else {
+		key, err := c.decodeKey(endKey)
+		if err != nil {
+			return nil, err
+		}
+		meta.EndKey = key
+	}
+	keyspaceRegion := *region
+	keyspaceRegion.Meta = meta
+	return &keyspaceRegion, nil
+}
+
+func (c *KeyspaceRegionClient) decodeKey(key []byte) ([]byte, error) {
+	rawKey, err := DecodeBytes(key)
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to decode region key %q", key)
+	}
+	if !bytes.HasPrefix(rawKey, c.prefix) {
+		return nil, errors.Errorf("region key %q is out of keyspace %d", key, c.keyspaceID)
+	}
+	return rawKey[len(c.prefix):], nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRegionClient serves the region lookups from the sorted regions.
+type mockRegionClient struct {
+	RPCClient
+	regions []*metapb.Region
+	// leaders maps the region ID to its leader store ID.
+	leaders map[uint64]uint64
+	// scanCount is the number of the scan requests sent to the server.
+	scanCount int
+}
+
+func (c *mockRegionClient) GetRegion(_ context.Context, key []byte, _ ...GetRegionOption) (*Region, error) {
+	for _, region := range c.regions {
+		if bytes.Compare(key, region.GetStartKey()) >= 0 &&
+			(len(region.GetEndKey()) == 0 || bytes.Compare(key, region.GetEndKey()) < 0) {
+			return &Region{Meta: region}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *mockRegionClient) GetPrevRegion(_ context.Context, key []byte, _ ...GetRegionOption) (*Region, error) {
+	for i, region := range c.regions {
+		if i > 0 && bytes.Compare(key, region.GetStartKey()) >= 0 &&
+			(len(region.GetEndKey()) == 0 || bytes.Compare(key, region.GetEndKey()) < 0) {
+			return &Region{Meta: c.regions[i-1]}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *mockRegionClient) GetRegionByID(_ context.Context, regionID uint64, _ ...GetRegionOption) (*Region, error) {
+	for _, region := range c.regions {
+		if region.GetId() == regionID {
+			return &Region{Meta: region}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *mockRegionClient) ScanRegions(_ context.Context, key, endKey []byte, limit int, opts ...GetRegionOption) ([]*Region, error) {
+	options := &GetRegionOp{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	regions, err := scanRegionsOnLeaderStores(key, endKey, limit, options.leaderStoreIDs, func(key []byte) ([]*Region, error) {
+		c.scanCount++
+		var regions []*Region
+		for _, region := range c.regions {
+			if (limit > 0 && len(regions) >= limit) || (len(endKey) > 0 && bytes.Compare(region.GetStartKey(), endKey) >= 0) {
+				break
+			}
+			if len(region.GetEndKey()) == 0 || bytes.Compare(key, region.GetEndKey()) < 0 {
+				regions = append(regions, &Region{Meta: region, Leader: &metapb.Peer{StoreId: c.leaders[region.GetId()]}})
+			}
+		}
+		return regions, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return handleScannedRegions(regions, options), nil
+}
+
+func TestKeyspaceRegionClient(t *testing.T) {
+	re := require.New(t)
+	ctx := context.Background()
+	keyspaceKey := func(id uint32, key string) []byte {
+		return EncodeBytes(append(makeKeyspacePrefix(id), key...))
+	}
+	// The regions are split at the keyspace bounds and inside keyspace 1.
+	cli := &mockRegionClient{regions: []*metapb.Region{
+		{Id: 1, StartKey: []byte{}, EndKey: keyspaceKey(1, "")},
+		{Id: 2, StartKey: keyspaceKey(1, ""), EndKey: keyspaceKey(1, "b")},
+		{Id: 3, StartKey: keyspaceKey(1, "b"), EndKey: keyspaceKey(1, "d")},
+		{Id: 4, StartKey: keyspaceKey(1, "d"), EndKey: []byte{}},
+	}}
+	ksCli := NewKeyspaceRegionClient(cli, 1)
+
+	region, err := ksCli.GetRegion(ctx, []byte("a"))
+	re.NoError(err)
+	re.Equal(uint64(2), region.Meta.GetId())
+	re.Empty(region.Meta.GetStartKey())
+	re.Equal([]byte("b"), region.Meta.GetEndKey())
+	// The original region meta should not be changed.
+	re.Equal(keyspaceKey(1, "b"), cli.regions[1].GetEndKey())
+
+	region, err = ksCli.GetRegion(ctx, []byte("c"))
+	re.NoError(err)
+	re.Equal(uint64(3), region.Meta.GetId())
+	re.Equal([]byte("b"), region.Meta.GetStartKey())
+	re.Equal([]byte("d"), region.Meta.GetEndKey())
+
+	// The region crossing the keyspace end is unbounded within the keyspace.
+	region, err = ksCli.GetRegion(ctx, []byte("z"))
+	re.NoError(err)
+	re.Equal(uint64(4), region.Meta.GetId())
+	re.Equal([]byte("d"), region.Meta.GetStartKey())
+	re.Empty(region.Meta.GetEndKey())
+
+	region, err = ksCli.GetPrevRegion(ctx, []byte("c"))
+	re.NoError(err)
+	re.Equal(uint64(2), region.Meta.GetId())
+	// The previous region of the first one is out of the keyspace.
+	region, err = ksCli.GetPrevRegion(ctx, []byte("a"))
+	re.NoError(err)
+	re.Nil(region)
+
+	region, err = ksCli.GetRegionByID(ctx, 3)
+	re.NoError(err)
+	re.Equal([]byte("b"), region.Meta.GetStartKey())
+	_, err = ksCli.GetRegionByID(ctx, 1)
+	re.Error(err)
+	// The region in the other keyspace is unbounded within the keyspace.
+	region, err = NewKeyspaceRegionClient(cli, 2).GetRegion(ctx, []byte("a"))
+	re.NoError(err)
+	re.Equal(uint64(4), region.Meta.GetId())
+	re.Empty(region.Meta.GetStartKey())
+	re.Empty(region.Meta.GetEndKey())
+}
@@ -15,6 +15,7 @@
 package pd
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -29,6 +30,8 @@ const (
 	defaultMaxTSOBatchWaitInterval time.Duration = 0
 	defaultEnableTSOFollowerProxy                = false
 	defaultEnableFollowerHandle                  = false
+	// defaultMaxRecvMsgSize is the same as the default of gRPC.
+	defaultMaxRecvMsgSize = 4 * 1024 * 1024
 )
 
 // DynamicOption is used to distinguish the dynamic option type.
@@ -57,9 +60,24 @@ type option struct {
 	enableForwarding bool
 	metricsLabels    prometheus.Labels
 	initMetrics      bool
+	// leaderOnly means the client never sends region requests to the followers,
+	// no matter whether the follower handle option is enabled or not.
+	leaderOnly bool
+	// followerConnIdleTTL is the duration after which the idle follower connections
+	// are closed when no request could be sent to the followers. 0 means never.
+	followerConnIdleTTL time.Duration
+	// maxRecvMsgSize is the max size in bytes of the response the client can receive.
+	maxRecvMsgSize int
+	// dialTimeout bounds the initial connection to PD when the client is created,
+	// 0 means it's only bounded by the retry times.
+	dialTimeout time.Duration
+	// negativeCacheTTL is how long the key without region is cached by GetRegion, 0 means never.
+	negativeCacheTTL time.Duration
 
 	// Dynamic options.
-	dynamicOptions [dynamicOptionCount]atomic.Value
+	dynamicOptions atomic.Pointer[dynamicOptionValues]
+	// dynamicOptionsMu serializes the updates of the dynamic options.
+	dynamicOptionsMu sync.Mutex
 
 	enableTSOFollowerProxyCh chan struct{}
 }
@@ -71,58 +89,135 @@ func newOption() *option {
 		maxRetryTimes:            maxInitClusterRetries,
 		enableTSOFollowerProxyCh: make(chan struct{}, 1),
 		initMetrics:              true,
+		maxRecvMsgSize:           defaultMaxRecvMsgSize,
 	}
 
-	co.dynamicOptions[MaxTSOBatchWaitInterval].Store(defaultMaxTSOBatchWaitInterval)
-	co.dynamicOptions[EnableTSOFollowerProxy].Store(defaultEnableTSOFollowerProxy)
-	co.dynamicOptions[EnableFollowerHandle].Store(defaultEnableFollowerHandle)
+	co.dynamicOptions.Store(&dynamicOptionValues{
+		maxTSOBatchWaitInterval: defaultMaxTSOBatchWaitInterval,
+		enableTSOFollowerProxy:  defaultEnableTSOFollowerProxy,
+		enableFollowerHandle:    defaultEnableFollowerHandle,
+	})
 	return co
 }
 
-// setMaxTSOBatchWaitInterval sets the max TSO batch wait interval option.
-// It only accepts the interval value between 0 and 10ms.
-func (o *option) setMaxTSOBatchWaitInterval(interval time.Duration) error {
+// getGRPCDialOptions returns the gRPC dial options to create the connections,
+// which limit the size of the responses by maxRecvMsgSize.
+func (o *option) getGRPCDialOptions() []grpc.DialOption {
+	opts := make([]grpc.DialOption, 0, len(o.gRPCDialOptions)+2)
+	opts = append(opts, o.gRPCDialOptions...)
+	return append(opts,
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(o.maxRecvMsgSize)),
+		grpc.WithChainUnaryInterceptor(newRecvMsgSizeInterceptor(o.maxRecvMsgSize)))
+}
+
+// dynamicOptionValues is a snapshot of the dynamic options. It's never modified once
+// stored, every update stores a new one, so the readers never observe a partial update.
+type dynamicOptionValues struct {
+	maxTSOBatchWaitInterval time.Duration
+	enableTSOFollowerProxy  bool
+	enableFollowerHandle    bool
+}
+
+func (v *dynamicOptionValues) get(option DynamicOption) any {
+	switch option {
+	case MaxTSOBatchWaitInterval:
+		return v.maxTSOBatchWaitInterval
+	case EnableTSOFollowerProxy:
+		return v.enableTSOFollowerProxy
+	case EnableFollowerHandle:
+		return v.enableFollowerHandle
+	}
+	return nil
+}
+
+func (v *dynamicOptionValues) set(option DynamicOption, value any) {
+	switch option {
+	case MaxTSOBatchWaitInterval:
+		v.maxTSOBatchWaitInterval = value.(time.Duration)
+	case EnableTSOFollowerProxy:
+		v.enableTSOFollowerProxy = value.(bool)
+	case EnableFollowerHandle:
+		v.enableFollowerHandle = value.(bool)
+	}
+}
+
+// getDynamicOptions returns the current snapshot of the dynamic options, which
+// should be used if multiple options are read together.
+func (o *option) getDynamicOptions() *dynamicOptionValues {
+	return o.dynamicOptions.Load()
+}
+
+// setDynamicOptions applies the update of the dynamic options atomically and
+// returns the options whose values are changed. The values should have been
+// checked by the caller.
+func (o *option) setDynamicOptions(values map[DynamicOption]any) []DynamicOption {
+	o.dynamicOptionsMu.Lock()
+	defer o.dynamicOptionsMu.Unlock()
+	old := o.dynamicOptions.Load()
+	updated := *old
+	var changed []DynamicOption
+	for option, value := range values {
+		if old.get(option) != value {
+			updated.set(option, value)
+			changed = append(changed, option)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	o.dynamicOptions.Store(&updated)
+	if updated.enableTSOFollowerProxy != old.enableTSOFollowerProxy {
+		select {
+		case o.enableTSOFollowerProxyCh <- struct{}{}:
+		default:
+		}
+	}
+	return changed
+}
+
+// checkMaxTSOBatchWaitInterval checks whether the max TSO batch wait interval is between 0 and 10ms.
+func checkMaxTSOBatchWaitInterval(interval time.Duration) error {
 	if interval < 0 || interval > 10*time.Millisecond {
 		return errors.New("[pd] invalid max TSO batch wait interval, should be between 0 and 10ms")
 	}
-	old := o.getMaxTSOBatchWaitInterval()
-	if interval != old {
-		o.dynamicOptions[MaxTSOBatchWaitInterval].Store(interval)
+	return nil
+}
+
+// setMaxTSOBatchWaitInterval sets the max TSO batch wait interval option.
+// It only accepts the interval value between 0 and 10ms.
+func (o *option) setMaxTSOBatchWaitInterval(interval time.Duration) error {
+	if err := checkMaxTSOBatchWaitInterval(interval); err != nil {
+		return err
 	}
+	o.setDynamicOptions(map[DynamicOption]any{MaxTSOBatchWaitInterval: interval})
 	return nil
 }
 
 // setEnableFollowerHandle set the Follower Handle option.
 func (o *option) setEnableFollowerHandle(enable bool) {
-	old := o.getEnableFollowerHandle()
-	if enable != old {
-		o.dynamicOptions[EnableFollowerHandle].Store(enable)
-	}
+	o.setDynamicOptions(map[DynamicOption]any{EnableFollowerHandle: enable})
 }
 
-// getMaxTSOBatchWaitInterval gets the Follower Handle enable option.
+// getEnableFollowerHandle gets the Follower Handle enable option.
+// It always returns false if the client is declared as leader-only.
 func (o *option) getEnableFollowerHandle() bool {
-	return o.dynamicOptions[EnableFollowerHandle].Load().(bool)
+	if o.leaderOnly {
+		return false
+	}
+	return o.getDynamicOptions().enableFollowerHandle
 }
 
 // getMaxTSOBatchWaitInterval gets the max TSO batch wait interval option.
 func (o *option) getMaxTSOBatchWaitInterval() time.Duration {
-	return o.dynamicOptions[MaxTSOBatchWaitInterval].Load().(time.Duration)
+	return o.getDynamicOptions().maxTSOBatchWaitInterval
 }
 
 // setEnableTSOFollowerProxy sets the TSO Follower Proxy option.
 func (o *option) setEnableTSOFollowerProxy(enable bool) {
-	old := o.getEnableTSOFollowerProxy()
-	if enable != old {
-		o.dynamicOptions[EnableTSOFollowerProxy].Store(enable)
-		select {
-		case o.enableTSOFollowerProxyCh <- struct{}{}:
-		default:
-		}
-	}
+	o.setDynamicOptions(map[DynamicOption]any{EnableTSOFollowerProxy: enable})
 }
 
 // getEnableTSOFollowerProxy gets the TSO Follower Proxy option.
 func (o *option) getEnableTSOFollowerProxy() bool {
-	return o.dynamicOptions[EnableTSOFollowerProxy].Load().(bool)
+	return o.getDynamicOptions().enableTSOFollowerProxy
 }
@@ -15,6 +15,7 @@
 package pd
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -64,3 +65,117 @@ func TestDynamicOptionChange(t *testing.T) {
 	o.setEnableFollowerHandle(expectBool)
 	re.Equal(expectBool, o.getEnableFollowerHandle())
 }
+
+func TestLeaderOnlyOption(t *testing.T) {
+	re := require.New(t)
+	c := &client{option: newOption()}
+	WithLeaderOnlyOption(true)(c)
+	re.False(c.option.getEnableFollowerHandle())
+	// Turning on the follower handle should not take effect for a leader-only client.
+	re.NoError(c.UpdateOption(EnableFollowerHandle, true))
+	re.False(c.option.getEnableFollowerHandle())
+
+	WithLeaderOnlyOption(false)(c)
+	re.True(c.option.getEnableFollowerHandle())
+}
+
+func TestUpdateOptionWithResult(t *testing.T) {
+	re := require.New(t)
+	c := &client{option: newOption()}
+
+	changed, err := c.UpdateOptionWithResult(EnableFollowerHandle, true)
+	re.NoError(err)
+	re.True(changed)
+	changed, err = c.UpdateOptionWithResult(EnableFollowerHandle, true)
+	re.NoError(err)
+	re.False(changed)
+	changed, err = c.UpdateOptionWithResult(EnableFollowerHandle, false)
+	re.NoError(err)
+	re.True(changed)
+
+	changed, err = c.UpdateOptionWithResult(MaxTSOBatchWaitInterval, defaultMaxTSOBatchWaitInterval)
+	re.NoError(err)
+	re.False(changed)
+	changed, err = c.UpdateOptionWithResult(MaxTSOBatchWaitInterval, time.Millisecond)
+	re.NoError(err)
+	re.True(changed)
+
+	// The failed update is never a change.
+	changed, err = c.UpdateOptionWithResult(MaxTSOBatchWaitInterval, time.Second)
+	re.Error(err)
+	re.False(changed)
+	re.Equal(time.Millisecond, c.option.getMaxTSOBatchWaitInterval())
+	changed, err = c.UpdateOptionWithResult(EnableFollowerHandle, "true")
+	re.Error(err)
+	re.False(changed)
+	changed, err = c.UpdateOptionWithResult(dynamicOptionCount, true)
+	re.Error(err)
+	re.False(changed)
+	// Only one of the concurrent updates to the same value reports the change.
+	var wg sync.WaitGroup
+	results := make([]bool, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			changed, err := c.UpdateOptionWithResult(EnableFollowerHandle, true)
+			re.NoError(err)
+			results[i] = changed
+		}(i)
+	}
+	wg.Wait()
+	changedCount := 0
+	for _, changed := range results {
+		if changed {
+			changedCount++
+		}
+	}
+	re.Equal(1, changedCount)
+}
+
+func TestUpdateOptions(t *testing.T) {
+	re := require.New(t)
+	c := &client{option: newOption()}
+
+	// No option is updated if any of the values is invalid.
+	re.Error(c.UpdateOptions(map[DynamicOption]any{
+		EnableFollowerHandle:    true,
+		MaxTSOBatchWaitInterval: time.Second,
+	}))
+	re.Equal(defaultEnableFollowerHandle, c.option.getEnableFollowerHandle())
+	re.Equal(defaultMaxTSOBatchWaitInterval, c.option.getMaxTSOBatchWaitInterval())
+
+	// The readers never observe the partial update under the concurrent updates.
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				values := c.option.getDynamicOptions()
+				re.Equal(values.enableFollowerHandle, values.maxTSOBatchWaitInterval == time.Millisecond)
+			}
+		}()
+	}
+	for i := 0; i < 1000; i++ {
+		enable := i%2 == 0
+		interval := defaultMaxTSOBatchWaitInterval
+		if enable {
+			interval = time.Millisecond
+		}
+		re.NoError(c.UpdateOptions(map[DynamicOption]any{
+			EnableFollowerHandle:    enable,
+			MaxTSOBatchWaitInterval: interval,
+		}))
+	}
+	close(done)
+	wg.Wait()
+	re.False(c.option.getEnableFollowerHandle())
+	re.Equal(defaultMaxTSOBatchWaitInterval, c.option.getMaxTSOBatchWaitInterval())
+}
@@ -154,6 +154,10 @@ type pdServiceClient struct {
 	leaderURL string
 
 	networkFailure atomic.Bool
+	// lastUsed is the last time in unix nano when the client is picked to send requests.
+	lastUsed atomic.Int64
+	// idleClosed means the connection of the follower is closed for being idle.
+	idleClosed bool
 }
 
 // NOTE: In the current implementation, the URL passed in is bound to have a scheme,
@@ -169,9 +173,19 @@ func newPDServiceClient(url, leaderURL string, conn *grpc.ClientConn, isLeader b
 	if conn == nil {
 		cli.networkFailure.Store(true)
 	}
+	cli.markUsed()
 	return cli
 }
 
+func (c *pdServiceClient) markUsed() {
+	c.lastUsed.Store(time.Now().UnixNano())
+}
+
+// idleDuration returns how long the client has not been picked to send requests.
+func (c *pdServiceClient) idleDuration(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, c.lastUsed.Load()))
+}
+
 // GetURL implements ServiceClient.
 func (c *pdServiceClient) GetURL() string {
 	if c == nil {
@@ -257,6 +271,14 @@ func regionAPIErrorFn(pdErr *pdpb.Error) bool {
 	return pdErr.GetType() == pdpb.ErrorType_REGION_NOT_FOUND
 }
 
+const (
+	// unavailableCooldown is how long the follower is skipped after it's marked as unavailable.
+	unavailableCooldown = 10 * time.Second
+	// maxConsecutiveFailures is the number of the consecutive failed requests to mark the
+	// follower as unavailable, so the requests don't keep trying a crashed follower first.
+	maxConsecutiveFailures = 3
+)
+
 // pdServiceAPIClient is a specific API client for PD service.
 // It extends the pdServiceClient and adds additional fields for managing availability
 type pdServiceAPIClient struct {
@@ -265,6 +287,8 @@ type pdServiceAPIClient struct {
 
 	unavailable      atomic.Bool
 	unavailableUntil atomic.Value
+	// consecutiveFailures is the number of the failed requests since the last succeeded one.
+	consecutiveFailures atomic.Int32
 }
 
 func newPDServiceAPIClient(client ServiceClient, f errFn) ServiceClient {
@@ -290,19 +314,28 @@ func (c *pdServiceAPIClient) markAsAvailable() {
 	}
 }
 
+// markAsUnavailable marks the client as unavailable for the cooldown.
+func (c *pdServiceAPIClient) markAsUnavailable() {
+	if c.unavailable.CompareAndSwap(false, true) {
+		c.unavailableUntil.Store(time.Now().Add(unavailableCooldown))
+		failpoint.Inject("fastCheckAvailable", func() {
+			c.unavailableUntil.Store(time.Now().Add(time.Millisecond * 100))
+		})
+	}
+}
+
 // NeedRetry implements ServiceClient.
 func (c *pdServiceAPIClient) NeedRetry(pdErr *pdpb.Error, err error) bool {
 	if c.IsConnectedToLeader() {
 		return false
 	}
 	if err == nil && pdErr == nil {
+		c.consecutiveFailures.Store(0)
 		return false
 	}
-	if c.fn(pdErr) && c.unavailable.CompareAndSwap(false, true) {
-		c.unavailableUntil.Store(time.Now().Add(time.Second * 10))
-		failpoint.Inject("fastCheckAvailable", func() {
-			c.unavailableUntil.Store(time.Now().Add(time.Millisecond * 100))
-		})
+	if c.fn(pdErr) || c.consecutiveFailures.Add(1) >= maxConsecutiveFailures {
+		c.consecutiveFailures.Store(0)
+		c.markAsUnavailable()
 	}
 	return true
 }
@@ -487,11 +520,11 @@ func (c *pdServiceDiscovery) Init() error {
 		return nil
 	}
 
-	if err := c.initRetry(c.initClusterID); err != nil {
+	if err := c.initClusterIDWithDialTimeout(); err != nil {
 		c.cancel()
 		return err
 	}
-	if err := c.initRetry(c.updateMember); err != nil {
+	if err := c.initRetry(c.ctx, c.updateMember); err != nil {
 		c.cancel()
 		return err
 	}
@@ -518,7 +551,22 @@ func (c *pdServiceDiscovery) Init() error {
 	return nil
 }
 
-func (c *pdServiceDiscovery) initRetry(f func() error) error {
+// initClusterIDWithDialTimeout initializes the cluster ID, which is the first contact
+// with PD, so the dial timeout bounds it.
+func (c *pdServiceDiscovery) initClusterIDWithDialTimeout() error {
+	if c.option.dialTimeout <= 0 {
+		return c.initRetry(c.ctx, func() error { return c.initClusterID(c.ctx) })
+	}
+	ctx, cancel := context.WithTimeout(c.ctx, c.option.dialTimeout)
+	defer cancel()
+	err := c.initRetry(ctx, func() error { return c.initClusterID(ctx) })
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return errs.ErrClientDialTimeout.Wrap(err).GenWithStackByArgs(c.option.dialTimeout)
+	}
+	return err
+}
+
+func (c *pdServiceDiscovery) initRetry(ctx context.Context, f func() error) error {
 	var err error
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
@@ -527,7 +575,7 @@ func (c *pdServiceDiscovery) initRetry(f func() error) error {
 			return nil
 		}
 		select {
-		case <-c.ctx.Done():
+		case <-ctx.Done():
 			return err
 		case <-ticker.C:
 		}
@@ -618,6 +666,7 @@ func (c *pdServiceDiscovery) checkLeaderHealth(ctx context.Context) {
 }
 
 func (c *pdServiceDiscovery) checkFollowerHealth(ctx context.Context) {
+	c.updateIdleFollowerConns(time.Now())
 	c.followers.Range(func(_, value any) bool {
 		// To ensure that the leader's healthy check is not delayed, shorten the duration.
 		ctx, cancel := context.WithTimeout(ctx, MemberHealthCheckInterval/3)
@@ -739,6 +788,11 @@ func (c *pdServiceDiscovery) getServiceClientByKind(kind apiKind) ServiceClient
 	if client == nil {
 		return nil
 	}
+	if node, ok := client.(*pdServiceBalancerNode); ok {
+		if serviceClient, ok := node.ServiceClient.(*pdServiceClient); ok {
+			serviceClient.markUsed()
+		}
+	}
 	return client
 }
 
@@ -824,8 +878,8 @@ func (c *pdServiceDiscovery) getFollowerURLs() []string {
 	return followerURLs.([]string)
 }
 
-func (c *pdServiceDiscovery) initClusterID() error {
-	ctx, cancel := context.WithCancel(c.ctx)
+func (c *pdServiceDiscovery) initClusterID(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	clusterID := uint64(0)
 	for _, url := range c.GetServiceURLs() {
@@ -1033,6 +1087,11 @@ func (c *pdServiceDiscovery) updateFollowers(members []*pdpb.Member, leaderID ui
 				// FIXME: How to safely compare urls(also for leader)? For now, only allows one client url.
 				url := pickMatchedURL(member.GetClientUrls(), c.tlsCfg)
 				if client, ok := c.followers.Load(url); ok {
+					if client.(*pdServiceClient).idleClosed && !c.isFollowerConnNeeded() {
+						// Keep the idle closed follower until it is needed.
+						delete(followers, url)
+						continue
+					}
 					if client.(*pdServiceClient).GetClientConn() == nil {
 						conn, err := c.GetOrCreateGRPCConn(url)
 						if err != nil || conn == nil {
@@ -1076,6 +1135,13 @@ func (c *pdServiceDiscovery) updateServiceClient(members []*pdpb.Member, leader
 		return err
 	}
 	// If error is not nil, still updates candidates.
+	c.updateServiceClientCandidates()
+	return err
+}
+
+// updateServiceClientCandidates updates the candidates for all kinds of request
+// with the current leader and followers.
+func (c *pdServiceDiscovery) updateServiceClientCandidates() {
 	clients := make([]ServiceClient, 0)
 	leaderClient := c.getLeaderServiceClient()
 	if leaderClient != nil {
@@ -1090,7 +1156,65 @@ func (c *pdServiceDiscovery) updateServiceClient(members []*pdpb.Member, leader
 	for i := 0; i < int(apiKindCount); i++ {
 		c.apiCandidateNodes[i].set(clients)
 	}
-	return err
+}
+
+// isFollowerConnNeeded returns whether the requests could be sent to the followers.
+func (c *pdServiceDiscovery) isFollowerConnNeeded() bool {
+	return c.option.getEnableFollowerHandle() || c.option.enableForwarding || c.option.getEnableTSOFollowerProxy()
+}
+
+// updateIdleFollowerConns closes the connections of the followers which have
+// been idle for longer than the TTL if no request could be sent to the
+// followers, or reopens the closed ones if the followers are needed again.
+// The connection of the leader is never closed.
+func (c *pdServiceDiscovery) updateIdleFollowerConns(now time.Time) {
+	ttl := c.option.followerConnIdleTTL
+	if ttl <= 0 {
+		return
+	}
+	needed := c.isFollowerConnNeeded()
+	leaderURL := c.getLeaderURL()
+	changed := false
+	c.followers.Range(func(key, value any) bool {
+		url, follower := key.(string), value.(*pdServiceClient)
+		if url == leaderURL {
+			return true
+		}
+		if needed {
+			if !follower.idleClosed {
+				return true
+			}
+			conn, err := c.GetOrCreateGRPCConn(url)
+			if err != nil || conn == nil {
+				log.Warn("[pd] failed to reconnect follower", zap.String("follower", url), errs.ZapError(err))
+				return true
+			}
+			if c.followers.CompareAndSwap(url, follower, newPDServiceClient(url, follower.leaderURL, conn, false)) {
+				log.Info("[pd] reopen the idle closed follower connection", zap.String("follower", url))
+				changed = true
+			}
+			return true
+		}
+		if follower.GetClientConn() == nil || follower.idleDuration(now) < ttl {
+			return true
+		}
+		closed := newPDServiceClient(url, follower.leaderURL, nil, false).(*pdServiceClient)
+		closed.idleClosed = true
+		if !c.followers.CompareAndSwap(url, follower, closed) {
+			return true
+		}
+		if cc, ok := c.clientConns.LoadAndDelete(url); ok {
+			if err := cc.(*grpc.ClientConn).Close(); err != nil {
+				log.Warn("[pd] failed to close the idle follower connection", zap.String("follower", url), errs.ZapError(errs.ErrCloseGRPCConn, err))
+			}
+		}
+		log.Info("[pd] close the idle follower connection", zap.String("follower", url), zap.Duration("ttl", ttl))
+		changed = true
+		return true
+	})
+	if changed {
+		c.updateServiceClientCandidates()
+	}
 }
 
 func (c *pdServiceDiscovery) switchTSOAllocatorLeaders(allocatorMap map[string]*pdpb.Member) error {
@@ -1119,7 +1243,7 @@ func (c *pdServiceDiscovery) switchTSOAllocatorLeaders(allocatorMap map[string]*
 
 // GetOrCreateGRPCConn returns the corresponding grpc client connection of the given URL.
 func (c *pdServiceDiscovery) GetOrCreateGRPCConn(url string) (*grpc.ClientConn, error) {
-	return grpcutil.GetOrCreateGRPCConn(c.ctx, &c.clientConns, url, c.tlsCfg, c.option.gRPCDialOptions...)
+	return grpcutil.GetOrCreateGRPCConn(c.ctx, &c.clientConns, url, c.tlsCfg, c.option.getGRPCDialOptions()...)
 }
 
 func addrsToURLs(addrs []string, tlsCfg *tls.Config) []string {
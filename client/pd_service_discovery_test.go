@@ -32,6 +32,7 @@ import (
 	"github.com/tikv/pd/client/grpcutil"
 	"github.com/tikv/pd/client/testutil"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
 	pb "google.golang.org/grpc/examples/helloworld/helloworld"
 	"google.golang.org/grpc/health"
@@ -380,3 +381,144 @@ func TestSchemeFunction(t *testing.T) {
 	}
 	re.Equal("http://127.0.0.1:2379", pickMatchedURL(urls, nil))
 }
+
+func TestCloseIdleFollowerConns(t *testing.T) {
+	re := require.New(t)
+	leaderServer, followerServer := newTestServer(true), newTestServer(false)
+	go leaderServer.run()
+	go followerServer.run()
+	defer leaderServer.grpcServer.Stop()
+	defer followerServer.grpcServer.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	leaderURL, followerURL := "http://"+leaderServer.addr, "http://"+followerServer.addr
+	sd := NewDefaultPDServiceDiscovery(ctx, cancel, []string{leaderURL, followerURL}, nil)
+	defer sd.Close()
+	sd.option.followerConnIdleTTL = 100 * time.Millisecond
+	leaderConn, err := sd.GetOrCreateGRPCConn(leaderURL)
+	re.NoError(err)
+	followerConn, err := sd.GetOrCreateGRPCConn(followerURL)
+	re.NoError(err)
+	sd.leader.Store(newPDServiceClient(leaderURL, leaderURL, leaderConn, true))
+	sd.followers.Store(followerURL, newPDServiceClient(followerURL, leaderURL, followerConn, false))
+	sd.updateServiceClientCandidates()
+	getFollower := func() *pdServiceClient {
+		follower, ok := sd.followers.Load(followerURL)
+		re.True(ok)
+		return follower.(*pdServiceClient)
+	}
+
+	// The follower connection is kept within the TTL.
+	sd.updateIdleFollowerConns(time.Now())
+	re.Same(followerConn, getFollower().GetClientConn())
+	// The follower connection is kept if the follower handle is enabled.
+	sd.option.setEnableFollowerHandle(true)
+	sd.updateIdleFollowerConns(time.Now().Add(time.Second))
+	re.Same(followerConn, getFollower().GetClientConn())
+
+	// The follower connection is closed after being idle past the TTL.
+	sd.option.setEnableFollowerHandle(false)
+	time.Sleep(200 * time.Millisecond)
+	sd.updateIdleFollowerConns(time.Now())
+	follower := getFollower()
+	re.True(follower.idleClosed)
+	re.Nil(follower.GetClientConn())
+	re.False(follower.Available())
+	re.Equal(connectivity.Shutdown, followerConn.GetState())
+	_, ok := sd.GetClientConns().Load(followerURL)
+	re.False(ok)
+	// The leader connection is exempt.
+	re.NotEqual(connectivity.Shutdown, leaderConn.GetState())
+	re.Same(leaderConn, sd.GetServingEndpointClientConn())
+	// The idle closed follower is kept while updating the members.
+	re.False(sd.updateFollowers([]*pdpb.Member{
+		{MemberId: 1, ClientUrls: []string{leaderURL}},
+		{MemberId: 2, ClientUrls: []string{followerURL}},
+	}, 1, leaderURL))
+	re.True(getFollower().idleClosed)
+
+	// The follower connection is reopened once the follower handle is enabled.
+	sd.option.setEnableFollowerHandle(true)
+	sd.updateIdleFollowerConns(time.Now())
+	follower = getFollower()
+	re.False(follower.idleClosed)
+	re.NotNil(follower.GetClientConn())
+	re.NotSame(followerConn, follower.GetClientConn())
+	re.Len(sd.GetAllServiceClients(), 2)
+	for _, client := range sd.GetAllServiceClients() {
+		re.NotNil(client.GetClientConn())
+	}
+	// The follower picked to send requests is not idle.
+	sd.option.setEnableFollowerHandle(false)
+	getFollower().lastUsed.Store(0)
+	for i := 0; i < 2; i++ {
+		re.NotNil(sd.getServiceClientByKind(regionAPIKind))
+	}
+	sd.updateIdleFollowerConns(time.Now().Add(50 * time.Millisecond))
+	re.False(getFollower().idleClosed)
+}
+
+func TestFollowerCircuitBreaker(t *testing.T) {
+	re := require.New(t)
+	newClient := func(addr string, isLeader bool) ServiceClient {
+		// The connection is never used to send the requests.
+		conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		re.NoError(err)
+		t.Cleanup(func() { conn.Close() })
+		return newPDServiceClient(modifyURLScheme(addr, nil), modifyURLScheme("127.0.0.1:1", nil), conn, isLeader)
+	}
+	leader, follower1, follower2 := newClient("127.0.0.1:1", true), newClient("127.0.0.1:2", false), newClient("127.0.0.1:3", false)
+	b := newPDServiceBalancer(regionAPIErrorFn)
+	b.set([]ServiceClient{leader, follower1, follower2})
+
+	tried := make(map[string]int)
+	// send simulates a request which always fails on the follower 1, and returns
+	// the URL of the server which serves it.
+	send := func() string {
+		client := b.get()
+		tried[client.GetURL()]++
+		var err error
+		if client.GetURL() == follower1.GetURL() {
+			err = errors.New("follower is crashed")
+		}
+		if client.NeedRetry(nil, err) {
+			return leader.GetURL()
+		}
+		return client.GetURL()
+	}
+	for i := 0; i < 30; i++ {
+		re.NotEqual(follower1.GetURL(), send())
+	}
+	// The follower 1 is skipped during the cooldown after the consecutive failures,
+	// while the leader and the follower 2 continue serving.
+	re.Equal(maxConsecutiveFailures, tried[follower1.GetURL()])
+	re.Equal(30-maxConsecutiveFailures, tried[leader.GetURL()]+tried[follower2.GetURL()])
+	re.Positive(tried[follower2.GetURL()])
+
+	// The follower 1 is tried again after the cooldown.
+	for i := 0; i < b.totalNode; i++ {
+		if b.now.GetURL() == follower1.GetURL() {
+			b.now.unavailableUntil.Store(time.Now().Add(-time.Second))
+		}
+		b.next()
+	}
+	b.check()
+	for i := 0; i < 3; i++ {
+		send()
+	}
+	re.Equal(maxConsecutiveFailures+1, tried[follower1.GetURL()])
+
+	// The succeeded request resets the consecutive failures.
+	client := newPDServiceAPIClient(follower2, regionAPIErrorFn)
+	for i := 0; i < 2*maxConsecutiveFailures; i++ {
+		re.True(client.NeedRetry(nil, errors.New("timeout")))
+		if i%2 == 1 {
+			re.False(client.NeedRetry(nil, nil))
+		}
+	}
+	re.True(client.Available())
+	// The follower which can't handle the request is marked as unavailable at once.
+	re.True(client.NeedRetry(&pdpb.Error{Type: pdpb.ErrorType_REGION_NOT_FOUND}, nil))
+	re.False(client.Available())
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import "bytes"
+
+// KeyRange is a range of keys, the empty start key and end key mean the
+// start and the end of the whole key space respectively.
+type KeyRange struct {
+	StartKey []byte
+	EndKey   []byte
+}
+
+// FindCoverageGaps returns the key ranges not covered by any of the regions, which
+// must be sorted by the start key, e.g. the regions returned by ScanRegions. It can be
+// used to check the consistency of the region metadata. The whole key space is checked,
+// so the range before the first region is reported as a gap too if it's not empty.
+func FindCoverageGaps(regions []*Region) []KeyRange {
+	var gaps []KeyRange
+	covered := []byte{}
+	for _, region := range regions {
+		startKey, endKey := region.Meta.GetStartKey(), region.Meta.GetEndKey()
+		if bytes.Compare(startKey, covered) > 0 {
+			gaps = append(gaps, KeyRange{StartKey: covered, EndKey: startKey})
+		}
+		// The region reaches the end of the key space.
+		if len(endKey) == 0 {
+			return gaps
+		}
+		if bytes.Compare(endKey, covered) > 0 {
+			covered = endKey
+		}
+	}
+	return append(gaps, KeyRange{StartKey: covered, EndKey: []byte{}})
+}
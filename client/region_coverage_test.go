@@ -0,0 +1,47 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCoverageGaps(t *testing.T) {
+	re := require.New(t)
+	newRegions := func(keys ...string) []*Region {
+		regions := make([]*Region, 0, len(keys)/2)
+		for i := 0; i < len(keys); i += 2 {
+			regions = append(regions, &Region{Meta: &metapb.Region{StartKey: []byte(keys[i]), EndKey: []byte(keys[i+1])}})
+		}
+		return regions
+	}
+	newKeyRange := func(startKey, endKey string) KeyRange {
+		return KeyRange{StartKey: []byte(startKey), EndKey: []byte(endKey)}
+	}
+
+	// The contiguous regions cover the whole key space.
+	re.Empty(FindCoverageGaps(newRegions("", "b", "b", "d", "d", "")))
+	re.Empty(FindCoverageGaps(newRegions("", "")))
+	// The hole between the regions.
+	re.Equal([]KeyRange{newKeyRange("b", "c")}, FindCoverageGaps(newRegions("", "b", "c", "d", "d", "")))
+	// The holes at the start and the end of the key space.
+	re.Equal([]KeyRange{newKeyRange("", "a"), newKeyRange("d", "")}, FindCoverageGaps(newRegions("a", "b", "b", "d")))
+	// The overlapped region doesn't hide the hole after it.
+	re.Equal([]KeyRange{newKeyRange("e", "f")}, FindCoverageGaps(newRegions("", "e", "b", "c", "f", "")))
+	re.Equal([]KeyRange{newKeyRange("", "")}, FindCoverageGaps(nil))
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/client/errs"
+	"go.uber.org/zap"
+)
+
+const regionHeartbeatRespChanSize = 128
+
+// RegionHeartbeatStream manages the region heartbeat stream to the PD leader for
+// the clients forwarding the region heartbeats. The stream is created on the
+// first Send, and is recreated by the next Send after the leader switch or a
+// stream failure. The responses from PD, e.g. the operators and the split
+// requests, are delivered through the Responses channel.
+type RegionHeartbeatStream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	sd     ServiceDiscovery
+	respCh chan *pdpb.RegionHeartbeatResponse
+
+	mu           sync.Mutex
+	stream       pdpb.PD_RegionHeartbeatClient
+	streamCancel context.CancelFunc
+}
+
+// NewRegionHeartbeatStream creates a RegionHeartbeatStream sending the heartbeats
+// to the serving PD of the service discovery, e.g. Client.GetServiceDiscovery().
+func NewRegionHeartbeatStream(ctx context.Context, sd ServiceDiscovery) *RegionHeartbeatStream {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &RegionHeartbeatStream{
+		ctx:    ctx,
+		cancel: cancel,
+		sd:     sd,
+		respCh: make(chan *pdpb.RegionHeartbeatResponse, regionHeartbeatRespChanSize),
+	}
+	// Reconnect to the new leader by the next Send.
+	sd.AddServingURLSwitchedCallback(s.resetStream)
+	return s
+}
+
+// Send sends the region heartbeat, the header is filled with the cluster ID if
+// it's absent. The stream is reset if it fails to send, so the caller could
+// retry the heartbeat on a new stream.
+func (s *RegionHeartbeatStream) Send(req *pdpb.RegionHeartbeatRequest) error {
+	if req.Header == nil {
+		req.Header = &pdpb.RequestHeader{ClusterId: s.sd.GetClusterID()}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx.Err() != nil {
+		return errors.WithStack(errClosing)
+	}
+	if s.stream == nil {
+		if err := s.createStreamLocked(); err != nil {
+			return err
+		}
+	}
+	if err := s.stream.Send(req); err != nil {
+		s.resetStreamLocked()
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Responses returns the channel of the heartbeat responses, it's closed after
+// the stream is closed.
+func (s *RegionHeartbeatStream) Responses() <-chan *pdpb.RegionHeartbeatResponse {
+	return s.respCh
+}
+
+// Close closes the stream, it should be called only once.
+func (s *RegionHeartbeatStream) Close() {
+	s.cancel()
+	s.resetStream()
+	s.wg.Wait()
+	close(s.respCh)
+}
+
+func (s *RegionHeartbeatStream) createStreamLocked() error {
+	cc := s.sd.GetServingEndpointClientConn()
+	if cc == nil {
+		return errs.ErrClientGetProtoClient
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	stream, err := pdpb.NewPDClient(cc).RegionHeartbeat(ctx)
+	if err != nil {
+		cancel()
+		return errors.WithStack(err)
+	}
+	s.stream, s.streamCancel = stream, cancel
+	s.wg.Add(1)
+	go s.recvLoop(ctx, stream)
+	return nil
+}
+
+func (s *RegionHeartbeatStream) recvLoop(ctx context.Context, stream pdpb.PD_RegionHeartbeatClient) {
+	defer s.wg.Done()
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Warn("[pd] region heartbeat stream is broken, it will be recreated by the next heartbeat", zap.Error(err))
+			}
+			s.mu.Lock()
+			if s.stream == stream {
+				s.resetStreamLocked()
+			}
+			s.mu.Unlock()
+			return
+		}
+		select {
+		case s.respCh <- resp:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *RegionHeartbeatStream) resetStream() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resetStreamLocked()
+}
+
+func (s *RegionHeartbeatStream) resetStreamLocked() {
+	if s.streamCancel != nil {
+		s.streamCancel()
+	}
+	s.stream, s.streamCancel = nil, nil
+}
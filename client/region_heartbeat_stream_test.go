@@ -0,0 +1,134 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/eraftpb"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// mockHeartbeatServer responds every region heartbeat with a change-peer
+// adding a peer on its store.
+type mockHeartbeatServer struct {
+	pdpb.UnimplementedPDServer
+	storeID uint64
+}
+
+func (s *mockHeartbeatServer) RegionHeartbeat(stream pdpb.PD_RegionHeartbeatServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		resp := &pdpb.RegionHeartbeatResponse{
+			Header:   &pdpb.ResponseHeader{ClusterId: req.GetHeader().GetClusterId()},
+			RegionId: req.GetRegion().GetId(),
+			ChangePeer: &pdpb.ChangePeer{
+				Peer:       &metapb.Peer{StoreId: s.storeID},
+				ChangeType: eraftpb.ConfChangeType_AddNode,
+			},
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// mockLeaderServiceDiscovery serves the connection to the current leader.
+type mockLeaderServiceDiscovery struct {
+	ServiceDiscovery
+	mu        sync.Mutex
+	leader    *grpc.ClientConn
+	callbacks []func()
+}
+
+func (*mockLeaderServiceDiscovery) GetClusterID() uint64 { return 1 }
+
+func (sd *mockLeaderServiceDiscovery) GetServingEndpointClientConn() *grpc.ClientConn {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	return sd.leader
+}
+
+func (sd *mockLeaderServiceDiscovery) AddServingURLSwitchedCallback(callbacks ...func()) {
+	sd.callbacks = append(sd.callbacks, callbacks...)
+}
+
+func (sd *mockLeaderServiceDiscovery) switchLeader(leader *grpc.ClientConn) {
+	sd.mu.Lock()
+	sd.leader = leader
+	sd.mu.Unlock()
+	for _, cb := range sd.callbacks {
+		cb()
+	}
+}
+
+func startMockHeartbeatServer(re *require.Assertions, storeID uint64) (*grpc.ClientConn, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	re.NoError(err)
+	server := grpc.NewServer()
+	pdpb.RegisterPDServer(server, &mockHeartbeatServer{storeID: storeID})
+	go server.Serve(lis)
+	cc, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	re.NoError(err)
+	return cc, func() {
+		cc.Close()
+		server.Stop()
+	}
+}
+
+func TestRegionHeartbeatStream(t *testing.T) {
+	re := require.New(t)
+	cc1, stop1 := startMockHeartbeatServer(re, 1)
+	defer stop1()
+	cc2, stop2 := startMockHeartbeatServer(re, 2)
+	defer stop2()
+
+	sd := &mockLeaderServiceDiscovery{}
+	s := NewRegionHeartbeatStream(context.Background(), sd)
+	// No leader to send to.
+	re.Error(s.Send(&pdpb.RegionHeartbeatRequest{Region: &metapb.Region{Id: 1}}))
+
+	sd.switchLeader(cc1)
+	req := &pdpb.RegionHeartbeatRequest{Region: &metapb.Region{Id: 1}}
+	re.NoError(s.Send(req))
+	re.Equal(uint64(1), req.GetHeader().GetClusterId())
+	resp := <-s.Responses()
+	re.Equal(uint64(1), resp.GetHeader().GetClusterId())
+	re.Equal(uint64(1), resp.GetRegionId())
+	re.Equal(eraftpb.ConfChangeType_AddNode, resp.GetChangePeer().GetChangeType())
+	re.Equal(uint64(1), resp.GetChangePeer().GetPeer().GetStoreId())
+
+	// The stream is recreated to the new leader.
+	sd.switchLeader(cc2)
+	re.NoError(s.Send(&pdpb.RegionHeartbeatRequest{Region: &metapb.Region{Id: 2}}))
+	resp = <-s.Responses()
+	re.Equal(uint64(2), resp.GetRegionId())
+	re.Equal(uint64(2), resp.GetChangePeer().GetPeer().GetStoreId())
+
+	s.Close()
+	_, ok := <-s.Responses()
+	re.False(ok)
+	re.Error(s.Send(&pdpb.RegionHeartbeatRequest{Region: &metapb.Region{Id: 3}}))
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"bytes"
+	"context"
+)
+
+// regionLayout is the identity of a region in the layout. The epoch version
+// of a region is increased by every split and merge, so the same ID and
+// version mean the region keeps the same key range.
+type regionLayout struct {
+	id      uint64
+	version uint64
+}
+
+// RegionLayoutToken is the layout of the regions in a key range at the time
+// it's issued. It can be used to detect whether any region in the range is
+// split or merged since then, so the caller of a multi-step operation only
+// needs to retry when the layout actually changed.
+type RegionLayoutToken struct {
+	key, endKey []byte
+	limit       int
+	// opts are used by the rescan too, since the options filtering or sorting
+	// the regions change the regions compared with the token.
+	opts    []GetRegionOption
+	regions []regionLayout
+}
+
+// newRegionLayoutToken creates the layout token of the regions scanned from
+// the given range with the given options.
+func newRegionLayoutToken(key, endKey []byte, limit int, opts []GetRegionOption, regions []*Region) *RegionLayoutToken {
+	token := &RegionLayoutToken{
+		key:     bytes.Clone(key),
+		endKey:  bytes.Clone(endKey),
+		limit:   limit,
+		opts:    append([]GetRegionOption(nil), opts...),
+		regions: make([]regionLayout, 0, len(regions)),
+	}
+	for _, region := range regions {
+		token.regions = append(token.regions, regionLayout{
+			id:      region.Meta.GetId(),
+			version: region.Meta.GetRegionEpoch().GetVersion(),
+		})
+	}
+	return token
+}
+
+// isChangedBy returns whether the regions scanned from the same range have a
+// different layout from the token.
+func (t *RegionLayoutToken) isChangedBy(regions []*Region) bool {
+	if len(regions) != len(t.regions) {
+		return true
+	}
+	for i, region := range regions {
+		if region.Meta.GetId() != t.regions[i].id ||
+			region.Meta.GetRegionEpoch().GetVersion() != t.regions[i].version {
+			return true
+		}
+	}
+	return false
+}
+
+// ScanRegionsWithLayoutToken scans the regions like ScanRegions, and returns
+// the layout token of the scanned regions alongside.
+func ScanRegionsWithLayoutToken(ctx context.Context, cli RPCClient, key, endKey []byte, limit int, opts ...GetRegionOption) ([]*Region, *RegionLayoutToken, error) {
+	regions, err := cli.ScanRegions(ctx, key, endKey, limit, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return regions, newRegionLayoutToken(key, endKey, limit, opts, regions), nil
+}
+
+// IsRegionLayoutChanged rescans the range of the token with the same options, and returns whether
+// any region in the range is split or merged since the token was issued.
+func IsRegionLayoutChanged(ctx context.Context, cli RPCClient, token *RegionLayoutToken) (bool, error) {
+	regions, err := cli.ScanRegions(ctx, token.key, token.endKey, token.limit, token.opts...)
+	if err != nil {
+		return false, err
+	}
+	return token.isChangedBy(regions), nil
+}
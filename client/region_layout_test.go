@@ -0,0 +1,120 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRegionScanner struct {
+	RPCClient
+	regions []*Region
+	lastKey []byte
+}
+
+func (s *mockRegionScanner) ScanRegions(_ context.Context, key, _ []byte, _ int, opts ...GetRegionOption) ([]*Region, error) {
+	s.lastKey = key
+	options := &GetRegionOp{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if !options.sortByLeaderStore {
+		return s.regions, nil
+	}
+	// Reverse the regions to simulate an order different from the key order.
+	regions := make([]*Region, 0, len(s.regions))
+	for i := len(s.regions) - 1; i >= 0; i-- {
+		regions = append(regions, s.regions[i])
+	}
+	return regions, nil
+}
+
+func newLayoutRegion(id, version uint64, startKey, endKey string) *Region {
+	return &Region{Meta: &metapb.Region{
+		Id:          id,
+		StartKey:    []byte(startKey),
+		EndKey:      []byte(endKey),
+		RegionEpoch: &metapb.RegionEpoch{Version: version},
+	}}
+}
+
+func TestRegionLayoutToken(t *testing.T) {
+	re := require.New(t)
+	ctx := context.Background()
+	cli := &mockRegionScanner{regions: []*Region{
+		newLayoutRegion(1, 1, "", "b"),
+		newLayoutRegion(2, 1, "b", ""),
+	}}
+
+	regions, token, err := ScanRegionsWithLayoutToken(ctx, cli, []byte(""), []byte(""), 0)
+	re.NoError(err)
+	re.Len(regions, 2)
+	changed, err := IsRegionLayoutChanged(ctx, cli, token)
+	re.NoError(err)
+	re.False(changed)
+
+	// The peer changes don't change the layout.
+	cli.regions = []*Region{
+		newLayoutRegion(1, 1, "", "b"),
+		newLayoutRegion(2, 1, "b", ""),
+	}
+	cli.regions[0].Meta.RegionEpoch.ConfVer = 2
+	changed, err = IsRegionLayoutChanged(ctx, cli, token)
+	re.NoError(err)
+	re.False(changed)
+
+	// Split the region 2.
+	cli.regions = []*Region{
+		newLayoutRegion(1, 1, "", "b"),
+		newLayoutRegion(3, 2, "b", "c"),
+		newLayoutRegion(2, 2, "c", ""),
+	}
+	changed, err = IsRegionLayoutChanged(ctx, cli, token)
+	re.NoError(err)
+	re.True(changed)
+
+	// Merge the region 1 into the region 2.
+	cli.regions = []*Region{newLayoutRegion(2, 2, "", "")}
+	changed, err = IsRegionLayoutChanged(ctx, cli, token)
+	re.NoError(err)
+	re.True(changed)
+}
+
+func TestRegionLayoutTokenWithOptions(t *testing.T) {
+	re := require.New(t)
+	ctx := context.Background()
+	cli := &mockRegionScanner{regions: []*Region{
+		newLayoutRegion(1, 1, "", "b"),
+		newLayoutRegion(2, 1, "b", ""),
+	}}
+
+	// The rescan uses the same options, so the order is the same.
+	key := []byte("a")
+	_, token, err := ScanRegionsWithLayoutToken(ctx, cli, key, []byte(""), 0, WithSortByLeaderStore())
+	re.NoError(err)
+	changed, err := IsRegionLayoutChanged(ctx, cli, token)
+	re.NoError(err)
+	re.False(changed)
+
+	// The token doesn't share the key with the caller.
+	key[0] = 'z'
+	_, err = IsRegionLayoutChanged(ctx, cli, token)
+	re.NoError(err)
+	re.Equal([]byte("a"), cli.lastKey)
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"sync"
+	"time"
+)
+
+// maxRegionMisses is the max number of the keys kept in the regionMissCache,
+// the new misses are not cached once it's full of the unexpired keys.
+const maxRegionMisses = 4096
+
+// regionMissCache caches the keys which have no region for a while, so the
+// repeated lookups of them don't hit the server. The zero value is ready to use.
+type regionMissCache struct {
+	mu sync.Mutex
+	// misses is the expiration time of each cached key.
+	misses map[string]time.Time
+}
+
+// has returns whether the key is cached as no region and not expired.
+func (c *regionMissCache) has(key []byte, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiration, ok := c.misses[string(key)]
+	if !ok {
+		return false
+	}
+	if now.After(expiration) {
+		delete(c.misses, string(key))
+		return false
+	}
+	return true
+}
+
+// add caches the key as no region until now+ttl.
+func (c *regionMissCache) add(key []byte, now time.Time, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.misses == nil {
+		c.misses = make(map[string]time.Time)
+	}
+	if len(c.misses) >= maxRegionMisses {
+		for k, expiration := range c.misses {
+			if now.After(expiration) {
+				delete(c.misses, k)
+			}
+		}
+		if len(c.misses) >= maxRegionMisses {
+			return
+		}
+	}
+	c.misses[string(key)] = now.Add(ttl)
+}
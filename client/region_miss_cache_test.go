@@ -0,0 +1,90 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRegionMissServer has no region for any key but "a", and fails the lookups
+// of the key "error" if failing is set.
+type mockRegionMissServer struct {
+	pdpb.UnimplementedPDServer
+	failing atomic.Bool
+	calls   atomic.Int32
+}
+
+func (s *mockRegionMissServer) GetRegion(_ context.Context, req *pdpb.GetRegionRequest) (*pdpb.GetRegionResponse, error) {
+	s.calls.Add(1)
+	resp := &pdpb.GetRegionResponse{Header: &pdpb.ResponseHeader{}}
+	switch string(req.GetRegionKey()) {
+	case "a":
+		resp.Region = &metapb.Region{Id: 1}
+	case "error":
+		if s.failing.Load() {
+			return nil, errors.New("transient error")
+		}
+	}
+	return resp, nil
+}
+
+func TestNegativeCacheTTL(t *testing.T) {
+	re := require.New(t)
+	ctx := context.Background()
+	regionServer := &mockRegionMissServer{}
+	c := newClientWithMockPDServer(t, regionServer)
+	getRegion := func(key string) *Region {
+		region, err := c.GetRegion(ctx, []byte(key))
+		re.NoError(err)
+		return region
+	}
+
+	// Every lookup hits the server without the negative cache.
+	re.Nil(getRegion("b"))
+	re.Nil(getRegion("b"))
+	re.Equal(int32(2), regionServer.calls.Swap(0))
+
+	// The repeated lookups of a missing key hit the server only once within the TTL.
+	WithNegativeCacheTTL(200 * time.Millisecond)(c)
+	for i := 0; i < 10; i++ {
+		re.Nil(getRegion("b"))
+	}
+	re.Equal(int32(1), regionServer.calls.Swap(0))
+	// The existing region is never cached.
+	re.NotNil(getRegion("a"))
+	re.NotNil(getRegion("a"))
+	re.Equal(int32(2), regionServer.calls.Swap(0))
+	// The lookup is refreshed after the TTL.
+	time.Sleep(300 * time.Millisecond)
+	re.Nil(getRegion("b"))
+	re.Equal(int32(1), regionServer.calls.Swap(0))
+
+	// The transient error is not cached as no region.
+	regionServer.failing.Store(true)
+	_, err := c.GetRegion(ctx, []byte("error"))
+	re.Error(err)
+	regionServer.failing.Store(false)
+	re.Nil(getRegion("error"))
+	re.Nil(getRegion("error"))
+	re.Equal(int32(2), regionServer.calls.Swap(0))
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/pingcap/errors"
+)
+
+// SplitRegionsAtKeys splits the regions at the given keys through SplitRegions,
+// e.g. to pre-split the hot ranges before a bulk load. Unlike SplitRegions, the
+// duplicated keys are ignored, it fails without splitting anything if any key is
+// not in a known region, and it fails if only a part of the regions are split.
+func SplitRegionsAtKeys(ctx context.Context, cli RPCClient, keys [][]byte, opts ...RegionsOption) error {
+	splitKeys, err := checkSplitKeys(ctx, cli, keys)
+	if err != nil {
+		return err
+	}
+	if len(splitKeys) == 0 {
+		return nil
+	}
+	resp, err := cli.SplitRegions(ctx, splitKeys, opts...)
+	if err != nil {
+		return err
+	}
+	if resp.GetHeader().GetError() != nil {
+		return errors.New(resp.GetHeader().GetError().String())
+	}
+	if resp.GetFinishedPercentage() < 100 {
+		return errors.Errorf("only %d%% of the regions are split, the new regions are %v",
+			resp.GetFinishedPercentage(), resp.GetRegionsId())
+	}
+	return nil
+}
+
+// checkSplitKeys sorts and deduplicates the split keys, and checks whether every
+// key is in a known region. The regions are scanned in batches from the smallest
+// key instead of being looked up key by key.
+func checkSplitKeys(ctx context.Context, cli RPCClient, keys [][]byte) ([][]byte, error) {
+	splitKeys := append([][]byte(nil), keys...)
+	sort.Slice(splitKeys, func(i, j int) bool { return bytes.Compare(splitKeys[i], splitKeys[j]) < 0 })
+	n := 0
+	for i, key := range splitKeys {
+		if i == 0 || !bytes.Equal(key, splitKeys[n-1]) {
+			splitKeys[n] = key
+			n++
+		}
+	}
+	splitKeys = splitKeys[:n]
+	if len(splitKeys) == 0 {
+		return splitKeys, nil
+	}
+	// The end key is right behind the largest key, so the scan includes it.
+	endKey := append(bytes.Clone(splitKeys[len(splitKeys)-1]), 0)
+	for i := 0; i < len(splitKeys); {
+		regions, err := cli.ScanRegions(ctx, splitKeys[i], endKey, len(splitKeys)-i)
+		if err != nil {
+			return nil, err
+		}
+		checked := i
+		for _, region := range regions {
+			if i == len(splitKeys) || bytes.Compare(splitKeys[i], region.Meta.GetStartKey()) < 0 {
+				break
+			}
+			regionEndKey := region.Meta.GetEndKey()
+			for i < len(splitKeys) && (len(regionEndKey) == 0 || bytes.Compare(splitKeys[i], regionEndKey) < 0) {
+				i++
+			}
+		}
+		// No scanned region contains the key.
+		if i == checked {
+			return nil, errors.Errorf("split key %q is not in any known region", splitKeys[i])
+		}
+	}
+	return splitKeys, nil
+}
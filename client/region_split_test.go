@@ -0,0 +1,88 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSplitClient struct {
+	mockRegionClient
+	scans     int
+	splitKeys [][]byte
+	finished  uint64
+}
+
+func (c *mockSplitClient) ScanRegions(ctx context.Context, key, endKey []byte, limit int, opts ...GetRegionOption) ([]*Region, error) {
+	c.scans++
+	return c.mockRegionClient.ScanRegions(ctx, key, endKey, limit, opts...)
+}
+
+func (c *mockSplitClient) SplitRegions(_ context.Context, splitKeys [][]byte, _ ...RegionsOption) (*pdpb.SplitRegionsResponse, error) {
+	c.splitKeys = splitKeys
+	return &pdpb.SplitRegionsResponse{FinishedPercentage: c.finished, RegionsId: []uint64{3}}, nil
+}
+
+func newMockSplitClient() *mockSplitClient {
+	return &mockSplitClient{mockRegionClient: mockRegionClient{regions: []*metapb.Region{
+		{Id: 1, StartKey: []byte{}, EndKey: []byte("m")},
+		{Id: 2, StartKey: []byte("m"), EndKey: []byte("x")},
+	}}}
+}
+
+func TestCheckSplitKeys(t *testing.T) {
+	re := require.New(t)
+	ctx := context.Background()
+	cli := newMockSplitClient()
+	keys := [][]byte{[]byte("n"), []byte("b"), []byte("c"), []byte("b")}
+	splitKeys, err := checkSplitKeys(ctx, cli, keys)
+	re.NoError(err)
+	re.Equal([][]byte{[]byte("b"), []byte("c"), []byte("n")}, splitKeys)
+	// The regions are scanned at once instead of key by key.
+	re.Equal(1, cli.scans)
+	// The keys of the caller are not changed.
+	re.Equal([]byte("n"), keys[0])
+
+	_, err = checkSplitKeys(ctx, cli, [][]byte{[]byte("b"), []byte("y")})
+	re.ErrorContains(err, "not in any known region")
+	// The key in the gap between the regions is rejected too.
+	cli.regions[1].StartKey = []byte("o")
+	_, err = checkSplitKeys(ctx, cli, [][]byte{[]byte("b"), []byte("n")})
+	re.ErrorContains(err, "not in any known region")
+}
+
+func TestSplitRegionsAtKeys(t *testing.T) {
+	re := require.New(t)
+	ctx := context.Background()
+	cli := newMockSplitClient()
+	cli.finished = 100
+	re.NoError(SplitRegionsAtKeys(ctx, cli, [][]byte{[]byte("n"), []byte("b"), []byte("n")}))
+	re.Equal([][]byte{[]byte("b"), []byte("n")}, cli.splitKeys)
+
+	// The partial split is reported as an error.
+	cli.finished = 50
+	err := SplitRegionsAtKeys(ctx, cli, [][]byte{[]byte("b"), []byte("n")})
+	re.ErrorContains(err, "only 50% of the regions are split")
+
+	// Nothing is split if any key is not in a known region.
+	cli.splitKeys = nil
+	re.Error(SplitRegionsAtKeys(ctx, cli, [][]byte{[]byte("b"), []byte("y")}))
+	re.Nil(cli.splitKeys)
+}
@@ -47,6 +47,8 @@ const (
 	defaultGroupCleanupInterval = 5 * time.Minute
 	// defaultGroupStateUpdateInterval is the interval to update the state of the resource groups.
 	defaultGroupStateUpdateInterval = 1 * time.Second
+	// defaultGroupStateUpdateJitter is the jitter ratio of the interval to update the state of the resource groups.
+	defaultGroupStateUpdateJitter = 0.1
 	// targetPeriod indicate how long it is expected to cost token when acquiring token.
 	// According to the resource control Grafana panel and Prometheus sampling period, the period should be the factor of 15.
 	defaultTargetPeriod = 5 * time.Second
@@ -32,6 +32,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	pd "github.com/tikv/pd/client"
 	"github.com/tikv/pd/client/errs"
+	"github.com/tikv/pd/client/timerpool"
 	atomicutil "go.uber.org/atomic"
 	"go.uber.org/zap"
 	"golang.org/x/exp/slices"
@@ -235,7 +236,8 @@ func (c *ResourceGroupsController) Start(ctx context.Context) {
 		}
 		cleanupTicker := time.NewTicker(defaultGroupCleanupInterval)
 		defer cleanupTicker.Stop()
-		stateUpdateTicker := time.NewTicker(defaultGroupStateUpdateInterval)
+		// Jitter the state update to spread the periodic reports of the clients.
+		stateUpdateTicker := timerpool.NewJitterTicker(defaultGroupStateUpdateInterval, defaultGroupStateUpdateJitter)
 		defer stateUpdateTicker.Stop()
 		emergencyTokenAcquisitionTicker := time.NewTicker(defaultTargetPeriod)
 		defer emergencyTokenAcquisitionTicker.Stop()
@@ -246,11 +248,11 @@ func (c *ResourceGroupsController) Start(ctx context.Context) {
 			// because of checking `gc.run.consumption` in cleanupTicker,
 			// so should also change the stateUpdateTicker.
 			stateUpdateTicker.Stop()
-			stateUpdateTicker = time.NewTicker(200 * time.Millisecond)
+			stateUpdateTicker = timerpool.NewJitterTicker(200*time.Millisecond, 0)
 		})
 		failpoint.Inject("acceleratedReportingPeriod", func() {
 			stateUpdateTicker.Stop()
-			stateUpdateTicker = time.NewTicker(time.Millisecond * 100)
+			stateUpdateTicker = timerpool.NewJitterTicker(time.Millisecond*100, 0)
 		})
 
 		_, metaRevision, err := c.provider.LoadResourceGroups(ctx)
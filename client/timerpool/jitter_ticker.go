@@ -0,0 +1,97 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timerpool
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// JitterTicker is like time.Ticker, but each interval is randomly picked from
+// [interval*(1-jitter), interval*(1+jitter)], so the periodic work of many
+// clients started at the same time spreads out instead of being aligned.
+type JitterTicker struct {
+	// C is the channel on which the ticks are delivered.
+	C <-chan time.Time
+
+	c        chan time.Time
+	interval time.Duration
+	jitter   float64
+	rand     *rand.Rand
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewJitterTicker returns a new JitterTicker. The jitter is the ratio of the
+// interval, which is clamped to [0, 1]. It panics if interval is not positive.
+// Stop the ticker to release the associated resources.
+func NewJitterTicker(interval time.Duration, jitter float64) *JitterTicker {
+	t := newJitterTicker(interval, jitter)
+	go t.run()
+	return t
+}
+
+func newJitterTicker(interval time.Duration, jitter float64) *JitterTicker {
+	if interval <= 0 {
+		panic("non-positive interval for NewJitterTicker")
+	}
+	jitter = min(max(jitter, 0), 1)
+	c := make(chan time.Time, 1)
+	return &JitterTicker{
+		C:        c,
+		c:        c,
+		interval: interval,
+		jitter:   jitter,
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+func (t *JitterTicker) run() {
+	timer := time.NewTimer(t.nextInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-t.stopCh:
+			return
+		case now := <-timer.C:
+			// Drop the tick for the slow receiver like time.Ticker.
+			select {
+			case t.c <- now:
+			default:
+			}
+			timer.Reset(t.nextInterval())
+		}
+	}
+}
+
+// nextInterval returns the next jittered interval. It is only called by the
+// goroutine of the ticker, so the rand is not shared.
+func (t *JitterTicker) nextInterval() time.Duration {
+	if t.jitter == 0 {
+		return t.interval
+	}
+	delta := (t.rand.Float64()*2 - 1) * t.jitter * float64(t.interval)
+	return max(t.interval+time.Duration(delta), time.Nanosecond)
+}
+
+// Stop turns off the ticker. It does not close the channel like time.Ticker.
+func (t *JitterTicker) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stopCh)
+	})
+}
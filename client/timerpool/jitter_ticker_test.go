@@ -0,0 +1,79 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timerpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJitterTickerInterval(t *testing.T) {
+	re := require.New(t)
+	ticker := newJitterTicker(time.Second, 0.2)
+	var minInterval, maxInterval time.Duration
+	for i := 0; i < 10000; i++ {
+		interval := ticker.nextInterval()
+		re.GreaterOrEqual(interval, 800*time.Millisecond)
+		re.LessOrEqual(interval, 1200*time.Millisecond)
+		if i == 0 || interval < minInterval {
+			minInterval = interval
+		}
+		if interval > maxInterval {
+			maxInterval = interval
+		}
+	}
+	// The intervals should spread over the band.
+	re.Less(minInterval, 850*time.Millisecond)
+	re.Greater(maxInterval, 1150*time.Millisecond)
+
+	// The jitter is clamped.
+	ticker = newJitterTicker(time.Second, -1)
+	re.Equal(time.Second, ticker.nextInterval())
+	ticker = newJitterTicker(time.Second, 2)
+	for i := 0; i < 1000; i++ {
+		interval := ticker.nextInterval()
+		re.Positive(interval)
+		re.LessOrEqual(interval, 2*time.Second)
+	}
+	re.Panics(func() { NewJitterTicker(0, 0.1) })
+}
+
+func TestJitterTicker(t *testing.T) {
+	re := require.New(t)
+	ticker := NewJitterTicker(10*time.Millisecond, 0.5)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		select {
+		case <-ticker.C:
+		case <-time.After(time.Second):
+			re.FailNow("the ticker didn't tick on time")
+		}
+	}
+	re.GreaterOrEqual(time.Since(start), 25*time.Millisecond)
+	ticker.Stop()
+	// Stop is idempotent and no tick is delivered after the pending one.
+	ticker.Stop()
+	select {
+	case <-ticker.C:
+	default:
+	}
+	select {
+	case <-ticker.C:
+		re.FailNow("the ticker ticked after being stopped")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
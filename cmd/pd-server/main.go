@@ -96,6 +96,7 @@ func NewTSOServiceCommand() *cobra.Command {
 	}
 	cmd.Flags().BoolP("version", "V", false, "print version information and exit")
 	cmd.Flags().StringP("config", "", "", "config file")
+	cmd.Flags().BoolP("config-check", "", false, "check config file validity and exit")
 	cmd.Flags().StringP("backend-endpoints", "", "", "url for etcd client")
 	cmd.Flags().StringP("listen-addr", "", "", "listen address for tso service")
 	cmd.Flags().StringP("advertise-listen-addr", "", "", "advertise urls for listen address (default '${listen-addr}')")
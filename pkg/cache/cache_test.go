@@ -16,7 +16,10 @@ package cache
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -391,3 +394,93 @@ func TestPriorityQueue(t *testing.T) {
 	re.Nil(pq.Peek())
 	re.Nil(pq.Tail())
 }
+
+func TestGenericLRU(t *testing.T) {
+	re := require.New(t)
+	var evictedKeys []string
+	var evictedValues []int
+	cache := NewGenericLRU(3, func(k string, v int) {
+		evictedKeys = append(evictedKeys, k)
+		evictedValues = append(evictedValues, v)
+	})
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	re.Equal(3, cache.Len())
+	re.Empty(evictedKeys)
+
+	// "a" becomes the most recently used, so "b" is evicted.
+	v, ok := cache.Get("a")
+	re.True(ok)
+	re.Equal(1, v)
+	cache.Put("d", 4)
+	re.Equal([]string{"b"}, evictedKeys)
+	re.Equal([]int{2}, evictedValues)
+	_, ok = cache.Get("b")
+	re.False(ok)
+
+	// Updating an existing item also marks it as used and does not evict.
+	cache.Put("c", 30)
+	re.Equal(3, cache.Len())
+	re.Len(evictedKeys, 1)
+	cache.Put("e", 5)
+	re.Equal([]string{"b", "a"}, evictedKeys)
+	v, ok = cache.Get("c")
+	re.True(ok)
+	re.Equal(30, v)
+
+	// Removing does not fire the callback.
+	cache.Remove("d")
+	cache.Remove("not-exist")
+	re.Equal(2, cache.Len())
+	re.Len(evictedKeys, 2)
+
+	// Shrinking the capacity evicts the least recently used items.
+	cache.Put("f", 6)
+	cache.SetCapacity(1)
+	re.Equal(1, cache.Len())
+	re.Equal([]string{"b", "a", "e", "c"}, evictedKeys)
+	re.Equal([]int{2, 1, 5, 30}, evictedValues)
+	v, ok = cache.Get("f")
+	re.True(ok)
+	re.Equal(6, v)
+
+	// 0 means no limit.
+	cache.SetCapacity(0)
+	for i := 0; i < 100; i++ {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	re.Equal(101, cache.Len())
+	re.Len(evictedKeys, 4)
+}
+
+func TestGenericLRUConcurrent(t *testing.T) {
+	re := require.New(t)
+	var evicted atomic.Int64
+	cache := NewGenericLRU(100, func(uint64, uint64) {
+		evicted.Add(1)
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := uint64(i*1000 + j)
+				cache.Put(key, key)
+				if v, ok := cache.Get(key); ok {
+					re.Equal(key, v)
+				}
+				if j%3 == 0 {
+					cache.Remove(key)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	re.LessOrEqual(cache.Len(), 100)
+	// Every item is either evicted, removed or still in the cache, and the
+	// removed one may have been evicted before.
+	re.LessOrEqual(evicted.Load()+int64(cache.Len()), int64(10000))
+	re.GreaterOrEqual(evicted.Load()+int64(cache.Len())+10*334, int64(10000))
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"container/list"
+
+	"github.com/tikv/pd/pkg/utils/syncutil"
+)
+
+type genericItem[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// GenericLRU is a thread-safe 'Least-Recently-Used' cache with typed keys and values.
+type GenericLRU[K comparable, V any] struct {
+	mu syncutil.Mutex
+	// capacity is the maximum number of items.
+	// 0 means no limit.
+	capacity int
+	// onEvict is called with the evicted item when the cache is over capacity.
+	// It is called without holding the lock, so it is safe to access the cache in it.
+	onEvict func(K, V)
+
+	ll    *list.List
+	items map[K]*list.Element
+}
+
+// NewGenericLRU returns a new GenericLRU. onEvict can be nil.
+func NewGenericLRU[K comparable, V any](capacity int, onEvict func(K, V)) *GenericLRU[K, V] {
+	return &GenericLRU[K, V]{
+		capacity: capacity,
+		onEvict:  onEvict,
+		ll:       list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+// Get retrieves an item from cache and marks it as the most recently used.
+func (c *GenericLRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ele, ok := c.items[key]; ok {
+		c.ll.MoveToFront(ele)
+		return ele.Value.(*genericItem[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put puts an item into cache, the least recently used items are evicted if
+// the cache is over capacity.
+func (c *GenericLRU[K, V]) Put(key K, value V) {
+	c.mu.Lock()
+	if ele, ok := c.items[key]; ok {
+		c.ll.MoveToFront(ele)
+		ele.Value.(*genericItem[K, V]).value = value
+		c.mu.Unlock()
+		return
+	}
+	c.items[key] = c.ll.PushFront(&genericItem[K, V]{key: key, value: value})
+	evicted := c.evictLocked()
+	c.mu.Unlock()
+	c.notifyEvicted(evicted)
+}
+
+// Remove removes an item from cache, the eviction callback is not called.
+func (c *GenericLRU[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ele, ok := c.items[key]; ok {
+		c.ll.Remove(ele)
+		delete(c.items, key)
+	}
+}
+
+// Len returns the number of items in cache.
+func (c *GenericLRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// SetCapacity changes the capacity of the cache, the least recently used items
+// are evicted if the cache is over the new capacity. 0 means no limit.
+func (c *GenericLRU[K, V]) SetCapacity(capacity int) {
+	c.mu.Lock()
+	c.capacity = capacity
+	evicted := c.evictLocked()
+	c.mu.Unlock()
+	c.notifyEvicted(evicted)
+}
+
+func (c *GenericLRU[K, V]) evictLocked() []*genericItem[K, V] {
+	var evicted []*genericItem[K, V]
+	for c.capacity > 0 && c.ll.Len() > c.capacity {
+		ele := c.ll.Back()
+		item := c.ll.Remove(ele).(*genericItem[K, V])
+		delete(c.items, item.key)
+		evicted = append(evicted, item)
+	}
+	return evicted
+}
+
+func (c *GenericLRU[K, V]) notifyEvicted(evicted []*genericItem[K, V]) {
+	if c.onEvict == nil {
+		return
+	}
+	for _, item := range evicted {
+		c.onEvict(item.key, item.value)
+	}
+}
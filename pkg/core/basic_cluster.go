@@ -17,6 +17,7 @@ package core
 import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/tikv/pd/pkg/core/storelimit"
+	"github.com/tikv/pd/pkg/utils/keyutil"
 	"github.com/tikv/pd/pkg/utils/syncutil"
 )
 
@@ -128,6 +129,13 @@ func (bc *BasicCluster) PauseLeaderTransfer(storeID uint64) error {
 	return bc.Stores.PauseLeaderTransfer(storeID)
 }
 
+// PauseLeaderTransferWithReason is like PauseLeaderTransfer, and records the reason of the pause.
+func (bc *BasicCluster) PauseLeaderTransferWithReason(storeID uint64, reason string) error {
+	bc.Stores.mu.Lock()
+	defer bc.Stores.mu.Unlock()
+	return bc.Stores.PauseLeaderTransferWithReason(storeID, reason)
+}
+
 // ResumeLeaderTransfer cleans a store's pause state. The store can be selected
 // as source or target of TransferLeader again.
 func (bc *BasicCluster) ResumeLeaderTransfer(storeID uint64) {
@@ -136,6 +144,58 @@ func (bc *BasicCluster) ResumeLeaderTransfer(storeID uint64) {
 	bc.Stores.ResumeLeaderTransfer(storeID)
 }
 
+// PauseLeaderTransferBatch is like PauseLeaderTransferWithReason for multiple stores
+// under one lock acquisition. It keeps pausing the rest of the stores if any of them
+// fails, and returns the first error.
+func (bc *BasicCluster) PauseLeaderTransferBatch(storeIDs []uint64, reason string) error {
+	bc.Stores.mu.Lock()
+	defer bc.Stores.mu.Unlock()
+	var res error
+	for _, storeID := range storeIDs {
+		if err := bc.Stores.PauseLeaderTransferWithReason(storeID, reason); err != nil && res == nil {
+			res = err
+		}
+	}
+	return res
+}
+
+// ResumeLeaderTransferBatch is like ResumeLeaderTransfer for multiple stores under
+// one lock acquisition.
+func (bc *BasicCluster) ResumeLeaderTransferBatch(storeIDs []uint64) {
+	bc.Stores.mu.Lock()
+	defer bc.Stores.mu.Unlock()
+	for _, storeID := range storeIDs {
+		bc.Stores.ResumeLeaderTransfer(storeID)
+	}
+}
+
+// AddLeaderTransferPauser pauses the leader transfer of the store on behalf of the pauser,
+// the pause can be shared by multiple pausers.
+func (bc *BasicCluster) AddLeaderTransferPauser(storeID uint64, pauser string) error {
+	bc.Stores.mu.Lock()
+	defer bc.Stores.mu.Unlock()
+	return bc.Stores.AddLeaderTransferPauser(storeID, pauser)
+}
+
+// RemoveLeaderTransferPauser releases the pause of leader transfer held by the pauser.
+func (bc *BasicCluster) RemoveLeaderTransferPauser(storeID uint64, pauser string) {
+	bc.Stores.mu.Lock()
+	defer bc.Stores.mu.Unlock()
+	bc.Stores.RemoveLeaderTransferPauser(storeID, pauser)
+}
+
+// GetLeaderTransferPausers returns the names of the sources currently pausing the
+// leader transfer of the store. It's useful to find out who blocks a store.
+func (bc *BasicCluster) GetLeaderTransferPausers(storeID uint64) []string {
+	bc.Stores.mu.RLock()
+	defer bc.Stores.mu.RUnlock()
+	store := bc.Stores.GetStore(storeID)
+	if store == nil {
+		return nil
+	}
+	return store.GetLeaderTransferPausers()
+}
+
 // SlowStoreEvicted marks a store as a slow store and prevents transferring
 // leader to the store
 func (bc *BasicCluster) SlowStoreEvicted(storeID uint64) error {
@@ -288,7 +348,12 @@ type StoreSetInformer interface {
 // StoreSetController is used to control stores' status.
 type StoreSetController interface {
 	PauseLeaderTransfer(id uint64) error
+	PauseLeaderTransferWithReason(id uint64, reason string) error
 	ResumeLeaderTransfer(id uint64)
+	PauseLeaderTransferBatch(ids []uint64, reason string) error
+	ResumeLeaderTransferBatch(ids []uint64)
+	AddLeaderTransferPauser(id uint64, pauser string) error
+	RemoveLeaderTransferPauser(id uint64, pauser string)
 
 	SlowStoreEvicted(id uint64) error
 	SlowStoreRecovered(id uint64)
@@ -297,10 +362,7 @@ type StoreSetController interface {
 }
 
 // KeyRange is a key range.
-type KeyRange struct {
-	StartKey []byte `json:"start-key"`
-	EndKey   []byte `json:"end-key"`
-}
+type KeyRange = keyutil.KeyRange
 
 // NewKeyRange create a KeyRange with the given start key and end key.
 func NewKeyRange(startKey, endKey string) KeyRange {
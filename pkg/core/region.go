@@ -1721,6 +1721,17 @@ func (r *RegionsInfo) GetStoreLeaderCount(storeID uint64) int {
 	return r.leaders[storeID].length()
 }
 
+// GetTotalLeaderCount gets the total count of the regions with a leader.
+func (r *RegionsInfo) GetTotalLeaderCount() int {
+	r.st.RLock()
+	defer r.st.RUnlock()
+	count := 0
+	for _, leaders := range r.leaders {
+		count += leaders.length()
+	}
+	return count
+}
+
 // GetStoreFollowerCount get the total count of a store's follower RegionInfo
 func (r *RegionsInfo) GetStoreFollowerCount(storeID uint64) int {
 	r.st.RLock()
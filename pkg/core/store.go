@@ -43,6 +43,9 @@ const (
 	EngineTiFlash = "tiflash"
 	// EngineTiKV indicates the tikv engine in metrics
 	EngineTiKV = "tikv"
+	// UnknownLeaderTransferPauser names the holder of the exclusive pause of
+	// leader transfer which has no reason.
+	UnknownLeaderTransferPauser = "unknown"
 )
 
 // StoreInfo contains information about a store.
@@ -66,6 +69,11 @@ type StoreInfo struct {
 	limiter             storelimit.StoreLimit
 	minResolvedTS       uint64
 	lastAwakenTime      time.Time
+	// leaderPauseReason is the reason of pausing leader transfer, e.g. the scheduler name.
+	leaderPauseReason string
+	// leaderPausers are the sources sharing the pause of leader transfer, which are
+	// kept sorted and never modified in place since the StoreInfo is cloned shallowly.
+	leaderPausers []string
 }
 
 // NewStoreInfo creates StoreInfo with meta data.
@@ -140,7 +148,28 @@ func (s *StoreInfo) ShallowClone(opts ...StoreCreateOption) *StoreInfo {
 // AllowLeaderTransfer returns if the store is allowed to be selected
 // as source or target of transfer leader.
 func (s *StoreInfo) AllowLeaderTransfer() bool {
-	return !s.pauseLeaderTransfer
+	return !s.pauseLeaderTransfer && len(s.leaderPausers) == 0
+}
+
+// GetPauseLeaderTransferReason returns the reason why the leader transfer of the store is paused,
+// it's empty if the leader transfer is not paused or paused without a reason.
+func (s *StoreInfo) GetPauseLeaderTransferReason() string {
+	return s.leaderPauseReason
+}
+
+// GetLeaderTransferPausers returns the names of the sources holding the pause of leader
+// transfer. The holder of the exclusive pause comes first, it's named by the reason of
+// the pause, or UnknownLeaderTransferPauser if the pause has no reason.
+func (s *StoreInfo) GetLeaderTransferPausers() []string {
+	pausers := make([]string, 0, len(s.leaderPausers)+1)
+	if s.pauseLeaderTransfer {
+		if len(s.leaderPauseReason) > 0 {
+			pausers = append(pausers, s.leaderPauseReason)
+		} else {
+			pausers = append(pausers, UnknownLeaderTransferPauser)
+		}
+	}
+	return append(pausers, s.leaderPausers...)
 }
 
 // EvictedAsSlowStore returns if the store should be evicted as a slow store.
@@ -665,14 +694,20 @@ func (s *StoresInfo) SetStore(store *StoreInfo) {
 
 // PauseLeaderTransfer pauses a StoreInfo with storeID.
 func (s *StoresInfo) PauseLeaderTransfer(storeID uint64) error {
+	return s.PauseLeaderTransferWithReason(storeID, "")
+}
+
+// PauseLeaderTransferWithReason pauses a StoreInfo with storeID and records the reason.
+func (s *StoresInfo) PauseLeaderTransferWithReason(storeID uint64, reason string) error {
 	store, ok := s.stores[storeID]
 	if !ok {
 		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
 	}
-	if !store.AllowLeaderTransfer() {
+	// Only another exclusive pause conflicts, the shared pausers can coexist with it.
+	if store.pauseLeaderTransfer {
 		return errs.ErrPauseLeaderTransfer.FastGenByArgs(storeID)
 	}
-	s.stores[storeID] = store.Clone(PauseLeaderTransfer())
+	s.stores[storeID] = store.Clone(PauseLeaderTransfer(), SetPauseLeaderTransferReason(reason))
 	return nil
 }
 
@@ -688,6 +723,29 @@ func (s *StoresInfo) ResumeLeaderTransfer(storeID uint64) {
 	s.stores[storeID] = store.Clone(ResumeLeaderTransfer())
 }
 
+// AddLeaderTransferPauser pauses the leader transfer of a store on behalf of the pauser.
+// Unlike PauseLeaderTransferWithReason, the pause can be shared by multiple pausers, and
+// the store stays paused until all of them are removed.
+func (s *StoresInfo) AddLeaderTransferPauser(storeID uint64, pauser string) error {
+	store, ok := s.stores[storeID]
+	if !ok {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	s.stores[storeID] = store.Clone(AddLeaderTransferPauser(pauser))
+	return nil
+}
+
+// RemoveLeaderTransferPauser releases the pause of leader transfer held by the pauser.
+func (s *StoresInfo) RemoveLeaderTransferPauser(storeID uint64, pauser string) {
+	store, ok := s.stores[storeID]
+	if !ok {
+		log.Warn("try to remove a store's leader transfer pauser, but it is not found. It may be cleanup",
+			zap.Uint64("store-id", storeID), zap.String("pauser", pauser))
+		return
+	}
+	s.stores[storeID] = store.Clone(RemoveLeaderTransferPauser(pauser))
+}
+
 // SlowStoreEvicted marks a store as a slow store and prevents transferring
 // leader to the store
 func (s *StoresInfo) SlowStoreEvicted(storeID uint64) error {
@@ -15,6 +15,7 @@
 package core
 
 import (
+	"slices"
 	"time"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -111,6 +112,36 @@ func PauseLeaderTransfer() StoreCreateOption {
 func ResumeLeaderTransfer() StoreCreateOption {
 	return func(store *StoreInfo) {
 		store.pauseLeaderTransfer = false
+		store.leaderPauseReason = ""
+	}
+}
+
+// SetPauseLeaderTransferReason sets the reason why the leader transfer of the store is paused.
+func SetPauseLeaderTransferReason(reason string) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.leaderPauseReason = reason
+	}
+}
+
+// AddLeaderTransferPauser adds a source sharing the pause of leader transfer.
+func AddLeaderTransferPauser(pauser string) StoreCreateOption {
+	return func(store *StoreInfo) {
+		i, found := slices.BinarySearch(store.leaderPausers, pauser)
+		if found {
+			return
+		}
+		store.leaderPausers = slices.Insert(slices.Clone(store.leaderPausers), i, pauser)
+	}
+}
+
+// RemoveLeaderTransferPauser removes a source sharing the pause of leader transfer.
+func RemoveLeaderTransferPauser(pauser string) StoreCreateOption {
+	return func(store *StoreInfo) {
+		i, found := slices.BinarySearch(store.leaderPausers, pauser)
+		if !found {
+			return
+		}
+		store.leaderPausers = slices.Delete(slices.Clone(store.leaderPausers), i, i+1)
 	}
 }
 
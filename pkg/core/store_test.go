@@ -235,3 +235,98 @@ func newStoreInfoWithDisk(id, used, available, capacity, regionSize uint64) *Sto
 	)
 	return store
 }
+
+func TestPauseLeaderTransferReason(t *testing.T) {
+	re := require.New(t)
+	stores := NewStoresInfo()
+	stores.SetStore(NewStoreInfo(&metapb.Store{Id: 1}))
+	re.Empty(stores.GetStore(1).GetPauseLeaderTransferReason())
+
+	re.NoError(stores.PauseLeaderTransferWithReason(1, "evict-leader-scheduler"))
+	re.False(stores.GetStore(1).AllowLeaderTransfer())
+	re.Equal("evict-leader-scheduler", stores.GetStore(1).GetPauseLeaderTransferReason())
+	// The reason of the first pause is kept.
+	re.Error(stores.PauseLeaderTransferWithReason(1, "grant-leader-scheduler"))
+	re.Equal("evict-leader-scheduler", stores.GetStore(1).GetPauseLeaderTransferReason())
+
+	stores.ResumeLeaderTransfer(1)
+	re.True(stores.GetStore(1).AllowLeaderTransfer())
+	re.Empty(stores.GetStore(1).GetPauseLeaderTransferReason())
+	re.NoError(stores.PauseLeaderTransfer(1))
+	re.Empty(stores.GetStore(1).GetPauseLeaderTransferReason())
+}
+
+func TestPauseLeaderTransferBatch(t *testing.T) {
+	re := require.New(t)
+	batch, individual := NewBasicCluster(), NewBasicCluster()
+	for id := uint64(1); id <= 4; id++ {
+		batch.PutStore(NewStoreInfo(&metapb.Store{Id: id}))
+		individual.PutStore(NewStoreInfo(&metapb.Store{Id: id}))
+	}
+	checkSameState := func() {
+		for id := uint64(1); id <= 4; id++ {
+			re.Equal(individual.GetStore(id).AllowLeaderTransfer(), batch.GetStore(id).AllowLeaderTransfer())
+			re.Equal(individual.GetStore(id).GetPauseLeaderTransferReason(), batch.GetStore(id).GetPauseLeaderTransferReason())
+		}
+	}
+
+	re.NoError(batch.PauseLeaderTransferWithReason(3, "grant-leader-scheduler"))
+	re.NoError(individual.PauseLeaderTransferWithReason(3, "grant-leader-scheduler"))
+	// The paused and the missing stores fail, while the rest are still paused.
+	err := batch.PauseLeaderTransferBatch([]uint64{1, 2, 3, 5}, "evict-leader-scheduler")
+	re.ErrorContains(err, "store 3 is paused")
+	for _, id := range []uint64{1, 2, 3, 5} {
+		individual.PauseLeaderTransferWithReason(id, "evict-leader-scheduler")
+	}
+	checkSameState()
+	re.False(batch.GetStore(1).AllowLeaderTransfer())
+	re.Equal("grant-leader-scheduler", batch.GetStore(3).GetPauseLeaderTransferReason())
+	re.True(batch.GetStore(4).AllowLeaderTransfer())
+
+	batch.ResumeLeaderTransferBatch([]uint64{1, 3, 5})
+	for _, id := range []uint64{1, 3, 5} {
+		individual.ResumeLeaderTransfer(id)
+	}
+	checkSameState()
+	re.False(batch.GetStore(2).AllowLeaderTransfer())
+	re.True(batch.GetStore(3).AllowLeaderTransfer())
+}
+
+func TestGetLeaderTransferPausers(t *testing.T) {
+	re := require.New(t)
+	cluster := NewBasicCluster()
+	cluster.PutStore(NewStoreInfo(&metapb.Store{Id: 1}))
+	re.Empty(cluster.GetLeaderTransferPausers(1))
+	re.Nil(cluster.GetLeaderTransferPausers(2))
+	re.Error(cluster.AddLeaderTransferPauser(2, "evict-leader-scheduler"))
+
+	// Two sources pause the same store.
+	re.NoError(cluster.AddLeaderTransferPauser(1, "evict-hot-cpu-store-scheduler"))
+	re.NoError(cluster.AddLeaderTransferPauser(1, "balance-witness-scheduler"))
+	re.NoError(cluster.AddLeaderTransferPauser(1, "evict-hot-cpu-store-scheduler"))
+	re.False(cluster.GetStore(1).AllowLeaderTransfer())
+	re.Equal([]string{"balance-witness-scheduler", "evict-hot-cpu-store-scheduler"}, cluster.GetLeaderTransferPausers(1))
+
+	// Removing one of them still leaves the store paused.
+	cluster.RemoveLeaderTransferPauser(1, "evict-hot-cpu-store-scheduler")
+	re.False(cluster.GetStore(1).AllowLeaderTransfer())
+	re.Equal([]string{"balance-witness-scheduler"}, cluster.GetLeaderTransferPausers(1))
+	cluster.RemoveLeaderTransferPauser(1, "balance-witness-scheduler")
+	re.True(cluster.GetStore(1).AllowLeaderTransfer())
+	re.Empty(cluster.GetLeaderTransferPausers(1))
+
+	// The exclusive pause coexists with the shared ones, and its holder comes first.
+	re.NoError(cluster.AddLeaderTransferPauser(1, "evict-hot-cpu-store-scheduler"))
+	re.NoError(cluster.PauseLeaderTransferWithReason(1, "evict-leader-scheduler"))
+	re.Equal([]string{"evict-leader-scheduler", "evict-hot-cpu-store-scheduler"}, cluster.GetLeaderTransferPausers(1))
+	// But it still conflicts with another exclusive pause.
+	re.Error(cluster.PauseLeaderTransferWithReason(1, "grant-leader-scheduler"))
+	cluster.ResumeLeaderTransfer(1)
+	re.False(cluster.GetStore(1).AllowLeaderTransfer())
+	re.Equal([]string{"evict-hot-cpu-store-scheduler"}, cluster.GetLeaderTransferPausers(1))
+	cluster.RemoveLeaderTransferPauser(1, "evict-hot-cpu-store-scheduler")
+
+	// The exclusive pause without a reason is reported too.
+	re.NoError(cluster.PauseLeaderTransfer(1))
+	re.Equal([]string{UnknownLeaderTransferPauser}, cluster.GetLeaderTransferPausers(1))
+}
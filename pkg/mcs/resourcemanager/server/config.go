@@ -89,6 +89,8 @@ type Config struct {
 	LeaderLease int64 `toml:"lease" json:"lease"`
 
 	Controller ControllerConfig `toml:"controller" json:"controller"`
+
+	Manager ManagerConfig `toml:"manager" json:"manager"`
 }
 
 // ControllerConfig is the configuration of the resource manager controller which includes some option for client needed.
@@ -124,6 +126,23 @@ func (rmc *ControllerConfig) Adjust(meta *configutil.ConfigMetaData) {
 	})
 }
 
+// ManagerConfig is the configuration of the resource group manager. Unlike ControllerConfig,
+// it's only used by the server, so it's neither persisted nor sent to the clients.
+type ManagerConfig struct {
+	// MaxPerSecCostTrackerIdleTimeout is how long the max per-sec cost of a resource group keeps
+	// being reported after the group stops consuming, 0 means it's always reported.
+	MaxPerSecCostTrackerIdleTimeout typeutil.Duration `toml:"max-per-sec-cost-tracker-idle-timeout" json:"max-per-sec-cost-tracker-idle-timeout"`
+}
+
+// Validate checks whether the manager configuration is valid.
+func (mc *ManagerConfig) Validate() error {
+	if mc.MaxPerSecCostTrackerIdleTimeout.Duration < 0 {
+		return errors.Errorf("max-per-sec-cost-tracker-idle-timeout %v should not be negative",
+			mc.MaxPerSecCostTrackerIdleTimeout.Duration)
+	}
+	return nil
+}
+
 // RequestUnitConfig is the configuration of the request units, which determines the coefficients of
 // the RRU and WRU cost. This configuration should be modified carefully.
 type RequestUnitConfig struct {
@@ -240,6 +259,9 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 	c.Security.Encryption.Adjust()
 
 	c.Controller.Adjust(configMetaData.Child("controller"))
+	if err := c.Manager.Validate(); err != nil {
+		return err
+	}
 	configutil.AdjustInt64(&c.LeaderLease, utils.DefaultLeaderLease)
 
 	return nil
@@ -50,3 +50,30 @@ read-cpu-ms-cost =  5.0
 	re.LessOrEqual(math.Abs(cfg.Controller.RequestUnit.ReadCostPerByte-2), 1e-7)
 	re.LessOrEqual(math.Abs(cfg.Controller.RequestUnit.ReadBaseCost-1), 1e-7)
 }
+
+func TestMaxPerSecCostTrackerIdleTimeout(t *testing.T) {
+	re := require.New(t)
+	for _, tc := range []struct {
+		cfgData  string
+		expected time.Duration
+		hasError bool
+	}{
+		// The trackers never become idle by default.
+		{"", 0, false},
+		{`max-per-sec-cost-tracker-idle-timeout = "1m"`, time.Minute, false},
+		{`max-per-sec-cost-tracker-idle-timeout = "-1m"`, 0, true},
+	} {
+		cfg := NewConfig()
+		meta, err := toml.Decode("[manager]\n"+tc.cfgData, &cfg)
+		re.NoError(err)
+		err = cfg.Adjust(&meta)
+		if tc.hasError {
+			re.Error(err)
+			continue
+		}
+		re.NoError(err)
+		re.Equal(tc.expected, cfg.Manager.MaxPerSecCostTrackerIdleTimeout.Duration)
+		m := &Manager{managerConfig: &cfg.Manager}
+		re.Equal(tc.expected, m.getTrackerIdleTimeout())
+	}
+}
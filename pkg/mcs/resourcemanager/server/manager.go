@@ -44,7 +44,9 @@ const (
 	metricsCleanupTimeout      = 20 * time.Minute
 	metricsAvailableRUInterval = 1 * time.Second
 	defaultCollectIntervalSec  = 20
-	tickPerSecond              = time.Second
+	// forecastWindowSize is the number of the recent per-sec costs used to forecast.
+	forecastWindowSize = 5
+	tickPerSecond      = time.Second
 
 	reservedDefaultGroupName = "default"
 	middlePriority           = 8
@@ -55,6 +57,7 @@ type Manager struct {
 	syncutil.RWMutex
 	srv              bs.Server
 	controllerConfig *ControllerConfig
+	managerConfig    *ManagerConfig
 	groups           map[string]*ResourceGroup
 	storage          endpoint.ResourceGroupStorage
 	// consumptionChan is used to send the consumption
@@ -78,6 +81,7 @@ type consumptionRecordKey struct {
 // `bs.server` without modifying its interface.
 type ConfigProvider interface {
 	GetControllerConfig() *ControllerConfig
+	GetResourceManagerConfig() *ManagerConfig
 }
 
 // NewManager returns a new manager base on the given server,
@@ -85,6 +89,7 @@ type ConfigProvider interface {
 func NewManager[T ConfigProvider](srv bs.Server) *Manager {
 	m := &Manager{
 		controllerConfig: srv.(T).GetControllerConfig(),
+		managerConfig:    srv.(T).GetResourceManagerConfig(),
 		groups:           make(map[string]*ResourceGroup),
 		consumptionDispatcher: make(chan struct {
 			resourceGroupName string
@@ -227,6 +232,11 @@ func (m *Manager) GetControllerConfig() *ControllerConfig {
 	return m.controllerConfig
 }
 
+// getTrackerIdleTimeout returns the idle timeout of the max-per-sec cost trackers.
+func (m *Manager) getTrackerIdleTimeout() time.Duration {
+	return m.managerConfig.MaxPerSecCostTrackerIdleTimeout.Duration
+}
+
 // AddResourceGroup puts a resource group.
 // NOTE: AddResourceGroup should also be idempotent because tidb depends
 // on this retry mechanism.
@@ -360,6 +370,8 @@ func (m *Manager) backgroundMetricsFlush(ctx context.Context) {
 	defer cleanUpTicker.Stop()
 	availableRUTicker := time.NewTicker(metricsAvailableRUInterval)
 	defer availableRUTicker.Stop()
+	// The max per-sec costs are measured by the ticks, so unlike the periodic reports
+	// of the clients, this ticker must not be jittered.
 	recordMaxTicker := time.NewTicker(tickPerSecond)
 	defer recordMaxTicker.Stop()
 	maxPerSecTrackers := make(map[string]*maxPerSecCostTracker)
@@ -394,7 +406,7 @@ func (m *Manager) backgroundMetricsFlush(ctx context.Context) {
 			)
 			t, ok := maxPerSecTrackers[name]
 			if !ok {
-				t = newMaxPerSecCostTracker(name, defaultCollectIntervalSec)
+				t = newMaxPerSecCostTracker(name, defaultCollectIntervalSec, m.getTrackerIdleTimeout())
 				maxPerSecTrackers[name] = t
 			}
 			t.CollectConsumption(consumption)
@@ -453,6 +465,7 @@ func (m *Manager) backgroundMetricsFlush(ctx context.Context) {
 					delete(maxPerSecTrackers, r.name)
 					readRequestUnitMaxPerSecCost.DeleteLabelValues(r.name)
 					writeRequestUnitMaxPerSecCost.DeleteLabelValues(r.name)
+					requestMaxPerSecCount.DeleteLabelValues(r.name)
 				}
 			}
 		case <-availableRUTicker.C:
@@ -484,7 +497,7 @@ func (m *Manager) backgroundMetricsFlush(ctx context.Context) {
 			m.RUnlock()
 			for _, name := range names {
 				if t, ok := maxPerSecTrackers[name]; !ok {
-					maxPerSecTrackers[name] = newMaxPerSecCostTracker(name, defaultCollectIntervalSec)
+					maxPerSecTrackers[name] = newMaxPerSecCostTracker(name, defaultCollectIntervalSec, m.getTrackerIdleTimeout())
 				} else {
 					t.FlushMetrics()
 				}
@@ -494,57 +507,201 @@ func (m *Manager) backgroundMetricsFlush(ctx context.Context) {
 }
 
 type maxPerSecCostTracker struct {
-	name          string
-	maxPerSecRRU  float64
-	maxPerSecWRU  float64
-	rruSum        float64
-	wruSum        float64
-	lastRRUSum    float64
-	lastWRUSum    float64
-	flushPeriod   int
-	cnt           int
-	rruMaxMetrics prometheus.Gauge
-	wruMaxMetrics prometheus.Gauge
+	name              string
+	maxPerSecRRU      float64
+	maxPerSecWRU      float64
+	maxPerSecRequests uint64
+	rruSum            float64
+	wruSum            float64
+	requestSum        uint64
+	lastRRUSum        float64
+	lastWRUSum        float64
+	lastRequestSum    uint64
+	flushPeriod       int
+	cnt               int
+	rruMaxMetrics     prometheus.Gauge
+	wruMaxMetrics     prometheus.Gauge
+	requestMaxMetrics prometheus.Gauge
+	// idleTimeout is how long the tracker keeps flushing since the last collection, 0 means
+	// never idle. An idle tracker stops flushing and deletes its metrics until the next collection.
+	idleTimeout     time.Duration
+	lastCollectTime time.Time
+	idle            bool
+	// rruHistory and wruHistory are the recent per-sec costs, the oldest first.
+	rruHistory []float64
+	wruHistory []float64
 }
 
-func newMaxPerSecCostTracker(name string, flushPeriod int) *maxPerSecCostTracker {
-	return &maxPerSecCostTracker{
-		name:          name,
-		flushPeriod:   flushPeriod,
-		rruMaxMetrics: readRequestUnitMaxPerSecCost.WithLabelValues(name),
-		wruMaxMetrics: writeRequestUnitMaxPerSecCost.WithLabelValues(name),
+func newMaxPerSecCostTracker(name string, flushPeriod int, idleTimeout time.Duration) *maxPerSecCostTracker {
+	if flushPeriod < 1 {
+		log.Warn("invalid flush period of the max-per-sec cost tracker, use 1 instead",
+			zap.String("resource-group", name), zap.Int("flush-period", flushPeriod))
+		flushPeriod = 1
 	}
+	t := &maxPerSecCostTracker{
+		name:            name,
+		flushPeriod:     flushPeriod,
+		idleTimeout:     idleTimeout,
+		lastCollectTime: time.Now(),
+	}
+	t.initMetrics()
+	return t
+}
+
+func (t *maxPerSecCostTracker) initMetrics() {
+	t.rruMaxMetrics = readRequestUnitMaxPerSecCost.WithLabelValues(t.name)
+	t.wruMaxMetrics = writeRequestUnitMaxPerSecCost.WithLabelValues(t.name)
+	t.requestMaxMetrics = requestMaxPerSecCount.WithLabelValues(t.name)
 }
 
-// CollectConsumption collects the consumption info.
+// CollectConsumption collects the consumption info, each call is counted as a request.
+// It restarts the tracker if it's idle.
 func (t *maxPerSecCostTracker) CollectConsumption(consume *rmpb.Consumption) {
+	if t.idle {
+		t.idle = false
+		t.initMetrics()
+	}
+	t.lastCollectTime = time.Now()
 	t.rruSum += consume.RRU
 	t.wruSum += consume.WRU
+	t.requestSum++
 }
 
-// FlushMetrics and set the maxPerSecRRU and maxPerSecWRU to the metrics.
+// FlushMetrics and set the maxPerSecRRU, maxPerSecWRU and maxPerSecRequests to the metrics.
+// It makes the tracker idle if nothing is collected within the idle timeout.
 func (t *maxPerSecCostTracker) FlushMetrics() {
+	if t.idle {
+		return
+	}
+	if t.idleTimeout > 0 && time.Since(t.lastCollectTime) > t.idleTimeout {
+		t.becomeIdle()
+		return
+	}
 	if t.lastRRUSum == 0 && t.lastWRUSum == 0 {
 		t.lastRRUSum = t.rruSum
 		t.lastWRUSum = t.wruSum
+		t.lastRequestSum = t.requestSum
 		return
 	}
 	deltaRRU := t.rruSum - t.lastRRUSum
 	deltaWRU := t.wruSum - t.lastWRUSum
+	deltaRequests := t.requestSum - t.lastRequestSum
 	t.lastRRUSum = t.rruSum
 	t.lastWRUSum = t.wruSum
+	t.lastRequestSum = t.requestSum
+	t.rruHistory = appendWindow(t.rruHistory, deltaRRU, forecastWindowSize)
+	t.wruHistory = appendWindow(t.wruHistory, deltaWRU, forecastWindowSize)
 	if deltaRRU > t.maxPerSecRRU {
 		t.maxPerSecRRU = deltaRRU
 	}
 	if deltaWRU > t.maxPerSecWRU {
 		t.maxPerSecWRU = deltaWRU
 	}
+	if deltaRequests > t.maxPerSecRequests {
+		t.maxPerSecRequests = deltaRequests
+	}
 	t.cnt++
 	// flush to metrics in every flushPeriod.
 	if t.cnt%t.flushPeriod == 0 {
 		t.rruMaxMetrics.Set(t.maxPerSecRRU)
 		t.wruMaxMetrics.Set(t.maxPerSecWRU)
+		t.requestMaxMetrics.Set(float64(t.maxPerSecRequests))
 		t.maxPerSecRRU = 0
 		t.maxPerSecWRU = 0
+		t.maxPerSecRequests = 0
+	}
+}
+
+// Merge combines the state of the other tracker into this one, e.g. to aggregate the trackers
+// of the same resource group on different nodes. The sums are added up and the maxes take the
+// larger one, so the merged max is the peak of any single tracker rather than the peak of their
+// total. The trackers are assumed to be flushed at the same ticks with the same flush period,
+// the per-sec history is aligned from the latest second and the flush count of the receiver is
+// kept. The other tracker is left unchanged.
+func (t *maxPerSecCostTracker) Merge(other *maxPerSecCostTracker) {
+	if other.idle {
+		return
+	}
+	if t.idle {
+		t.idle = false
+		t.initMetrics()
+	}
+	if other.lastCollectTime.After(t.lastCollectTime) {
+		t.lastCollectTime = other.lastCollectTime
+	}
+	t.rruSum += other.rruSum
+	t.wruSum += other.wruSum
+	t.requestSum += other.requestSum
+	t.lastRRUSum += other.lastRRUSum
+	t.lastWRUSum += other.lastWRUSum
+	t.lastRequestSum += other.lastRequestSum
+	t.maxPerSecRRU = max(t.maxPerSecRRU, other.maxPerSecRRU)
+	t.maxPerSecWRU = max(t.maxPerSecWRU, other.maxPerSecWRU)
+	t.maxPerSecRequests = max(t.maxPerSecRequests, other.maxPerSecRequests)
+	t.rruHistory = mergeWindow(t.rruHistory, other.rruHistory)
+	t.wruHistory = mergeWindow(t.wruHistory, other.wruHistory)
+}
+
+// mergeWindow adds up the two windows aligned from their latest values.
+func mergeWindow(window, other []float64) []float64 {
+	if len(other) > len(window) {
+		window = append(make([]float64, len(other)-len(window), len(other)), window...)
+	}
+	offset := len(window) - len(other)
+	for i, value := range other {
+		window[offset+i] += value
+	}
+	return window
+}
+
+// Forecast returns the per-sec RRU and WRU projected for the next second. It's a naive
+// estimate which fits a line through the recent per-sec costs by the least squares, so it
+// only follows the short-term trend. The latest per-sec costs are returned as they are if
+// the history is not enough to tell the trend.
+func (t *maxPerSecCostTracker) Forecast() (rru, wru float64) {
+	return forecastNext(t.rruHistory), forecastNext(t.wruHistory)
+}
+
+func appendWindow(window []float64, value float64, size int) []float64 {
+	if len(window) >= size {
+		window = append(window[:0], window[len(window)-size+1:]...)
+	}
+	return append(window, value)
+}
+
+// forecastNext fits the values at x = 0, 1, ..., n-1 with a line and returns its value at x = n.
+func forecastNext(values []float64) float64 {
+	n := len(values)
+	switch n {
+	case 0:
+		return 0
+	case 1:
+		return values[0]
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range values {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	count := float64(n)
+	slope := (count*sumXY - sumX*sumY) / (count*sumXX - sumX*sumX)
+	intercept := (sumY - slope*sumX) / count
+	return max(slope*count+intercept, 0)
+}
+
+// becomeIdle deletes the metrics and resets the tracker, the max values are
+// tracked from scratch once it's restarted.
+func (t *maxPerSecCostTracker) becomeIdle() {
+	readRequestUnitMaxPerSecCost.DeleteLabelValues(t.name)
+	writeRequestUnitMaxPerSecCost.DeleteLabelValues(t.name)
+	requestMaxPerSecCount.DeleteLabelValues(t.name)
+	*t = maxPerSecCostTracker{
+		name:        t.name,
+		flushPeriod: t.flushPeriod,
+		idleTimeout: t.idleTimeout,
+		idle:        true,
 	}
 }
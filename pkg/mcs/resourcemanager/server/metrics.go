@@ -63,6 +63,13 @@ var (
 			Name:      "write_request_unit_max_per_sec",
 			Help:      "Gauge of the max write request unit per second for all resource groups.",
 		}, []string{newResourceGroupNameLabel})
+	requestMaxPerSecCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: resourceSubsystem,
+			Name:      "request_max_per_sec",
+			Help:      "Gauge of the max number of requests per second for all resource groups.",
+		}, []string{newResourceGroupNameLabel})
 
 	sqlLayerRequestUnitCost = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -130,4 +137,5 @@ func init() {
 	prometheus.MustRegister(availableRUCounter)
 	prometheus.MustRegister(readRequestUnitMaxPerSecCost)
 	prometheus.MustRegister(writeRequestUnitMaxPerSecCost)
+	prometheus.MustRegister(requestMaxPerSecCount)
 }
@@ -17,18 +17,23 @@ package server
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	rmpb "github.com/pingcap/kvproto/pkg/resource_manager"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
 func TestMaxPerSecCostTracker(t *testing.T) {
-	tracker := newMaxPerSecCostTracker("test", defaultCollectIntervalSec)
+	tracker := newMaxPerSecCostTracker("test", defaultCollectIntervalSec, 0)
 	re := require.New(t)
 
 	// Define the expected max values for each flushPeriod
 	expectedMaxRU := []float64{19, 39, 59}
 	expectedSum := []float64{190, 780, 1770}
+	// Each iteration collects one request, so there is one request per second.
+	expectedMaxRequests := []uint64{1, 1, 1}
+	expectedRequestSum := []uint64{20, 40, 60}
 
 	for i := 0; i < 60; i++ {
 		// Record data
@@ -46,6 +51,118 @@ func TestMaxPerSecCostTracker(t *testing.T) {
 			re.Equal(tracker.maxPerSecWRU, expectedMaxRU[period], fmt.Sprintf("maxPerSecWRU in period %d is incorrect", period+1))
 			re.Equal(tracker.rruSum, expectedSum[period])
 			re.Equal(tracker.rruSum, expectedSum[period])
+			re.Equal(expectedMaxRequests[period], tracker.maxPerSecRequests, fmt.Sprintf("maxPerSecRequests in period %d is incorrect", period+1))
+			re.Equal(expectedRequestSum[period], tracker.requestSum)
+			// The per-sec RU increases by 1 every second.
+			rru, wru := tracker.Forecast()
+			re.InDelta(float64(i+1), rru, 1e-9)
+			re.InDelta(float64(i+1), wru, 1e-9)
+		}
+	}
+}
+
+func TestMaxPerSecCostTrackerForecast(t *testing.T) {
+	re := require.New(t)
+	tracker := newMaxPerSecCostTracker("test-forecast", defaultCollectIntervalSec, 0)
+	rru, wru := tracker.Forecast()
+	re.Zero(rru)
+	re.Zero(wru)
+	// The first flush is the baseline, and the current values are returned
+	// until there is enough history.
+	tracker.CollectConsumption(&rmpb.Consumption{RRU: 10, WRU: 1})
+	tracker.FlushMetrics()
+	tracker.CollectConsumption(&rmpb.Consumption{RRU: 10, WRU: 1})
+	tracker.FlushMetrics()
+	rru, wru = tracker.Forecast()
+	re.Equal(10.0, rru)
+	re.Equal(1.0, wru)
+	// The forecast never goes negative.
+	for _, ru := range []float64{8, 4, 0} {
+		tracker.CollectConsumption(&rmpb.Consumption{RRU: ru, WRU: 1})
+		tracker.FlushMetrics()
+	}
+	rru, wru = tracker.Forecast()
+	re.Zero(rru)
+	re.InDelta(1.0, wru, 1e-9)
+}
+
+func TestMaxPerSecCostTrackerIdle(t *testing.T) {
+	re := require.New(t)
+	name := "test-idle"
+	tracker := newMaxPerSecCostTracker(name, 2, time.Minute)
+	consume := func(ru float64) {
+		tracker.CollectConsumption(&rmpb.Consumption{RRU: ru, WRU: ru})
+		tracker.FlushMetrics()
+	}
+	for i := 1; i <= 4; i++ {
+		consume(float64(i))
+	}
+	re.False(tracker.idle)
+	re.Equal(3.0, testutil.ToFloat64(tracker.rruMaxMetrics))
+
+	// Nothing is collected within the idle timeout.
+	tracker.lastCollectTime = time.Now().Add(-2 * time.Minute)
+	tracker.FlushMetrics()
+	re.True(tracker.idle)
+	re.Zero(tracker.rruSum)
+	// The metrics have been deleted.
+	re.False(readRequestUnitMaxPerSecCost.DeleteLabelValues(name))
+	re.False(requestMaxPerSecCount.DeleteLabelValues(name))
+	tracker.FlushMetrics()
+	re.True(tracker.idle)
+
+	// It's restarted by the next collection and tracks from scratch.
+	for i := 1; i <= 5; i++ {
+		consume(float64(10 * i))
+	}
+	re.False(tracker.idle)
+	re.Equal(50.0, testutil.ToFloat64(tracker.rruMaxMetrics))
+	re.True(readRequestUnitMaxPerSecCost.DeleteLabelValues(name))
+}
+
+func TestMaxPerSecCostTrackerMerge(t *testing.T) {
+	re := require.New(t)
+	newTracker := func(name string, rus []float64) *maxPerSecCostTracker {
+		tracker := newMaxPerSecCostTracker(name, defaultCollectIntervalSec, 0)
+		for _, ru := range rus {
+			tracker.CollectConsumption(&rmpb.Consumption{RRU: ru, WRU: 2 * ru})
+			tracker.FlushMetrics()
+		}
+		return tracker
+	}
+	// The per-sec RU are 5, 1, 1 and 3, 8, 2 after the baselines.
+	t1 := newTracker("test-merge-1", []float64{1, 5, 1, 1})
+	t2 := newTracker("test-merge-2", []float64{2, 3, 8, 2})
+	t1.Merge(t2)
+	re.Equal(8.0, t1.maxPerSecRRU)
+	re.Equal(16.0, t1.maxPerSecWRU)
+	re.Equal(uint64(1), t1.maxPerSecRequests)
+	re.Equal(23.0, t1.rruSum)
+	re.Equal(46.0, t1.wruSum)
+	re.Equal(uint64(8), t1.requestSum)
+	re.Equal([]float64{8, 9, 3}, t1.rruHistory)
+	// The other tracker is not changed.
+	re.Equal(15.0, t2.rruSum)
+	re.Equal([]float64{3, 8, 2}, t2.rruHistory)
+
+	// The next flush goes on with the merged sums.
+	t1.CollectConsumption(&rmpb.Consumption{RRU: 10, WRU: 20})
+	t1.FlushMetrics()
+	re.Equal(10.0, t1.rruHistory[len(t1.rruHistory)-1])
+}
+
+func TestMaxPerSecCostTrackerInvalidFlushPeriod(t *testing.T) {
+	re := require.New(t)
+	for _, flushPeriod := range []int{0, -1} {
+		tracker := newMaxPerSecCostTracker("test-invalid-flush-period", flushPeriod, 0)
+		re.Equal(1, tracker.flushPeriod)
+		// Every flush sets the metrics.
+		for _, ru := range []float64{1, 3, 2} {
+			tracker.CollectConsumption(&rmpb.Consumption{RRU: ru})
+			tracker.FlushMetrics()
+			if tracker.cnt > 0 {
+				re.Equal(ru, testutil.ToFloat64(tracker.rruMaxMetrics))
+			}
 		}
 	}
 }
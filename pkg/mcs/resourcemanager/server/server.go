@@ -239,6 +239,11 @@ func (s *Server) GetControllerConfig() *ControllerConfig {
 	return &s.cfg.Controller
 }
 
+// GetResourceManagerConfig returns the resource group manager config.
+func (s *Server) GetResourceManagerConfig() *ManagerConfig {
+	return &s.cfg.Manager
+}
+
 // IsServing returns whether the server is the leader, if there is embedded etcd, or the primary otherwise.
 func (s *Server) IsServing() bool {
 	return !s.IsClosed() && s.participant.IsLeader()
@@ -168,6 +168,7 @@ func (s *Service) RegisterOperatorsRouter() {
 	router.GET("/:id", getOperatorByRegion)
 	router.DELETE("/:id", deleteOperatorByRegion)
 	router.GET("/records", getOperatorRecords)
+	router.GET("/records/:id", getRegionOperatorRecords)
 }
 
 // RegisterStoresRouter registers the router of the stores handler.
@@ -431,6 +432,29 @@ func getOperatorRecords(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, records)
 }
 
+// @Tags     operator
+// @Summary  lists the recent finished operators of the region.
+// @Param    id  path  integer  true  "A Region's Id"
+// @Produce  json
+// @Success  200  {object}  []operator.RegionOpRecord
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /operators/records/{id} [get]
+func getRegionOperatorRecords(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+	regionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	records, err := handler.GetRegionOperatorRecords(regionID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, records)
+}
+
 // FIXME: details of input json body params
 // @Tags     operator
 // @Summary  Create an operator.
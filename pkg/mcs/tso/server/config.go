@@ -15,10 +15,14 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -30,6 +34,7 @@ import (
 	"github.com/tikv/pd/pkg/utils/configutil"
 	"github.com/tikv/pd/pkg/utils/grpcutil"
 	"github.com/tikv/pd/pkg/utils/metricutil"
+	"github.com/tikv/pd/pkg/utils/syncutil"
 	"github.com/tikv/pd/pkg/utils/typeutil"
 	"go.uber.org/zap"
 )
@@ -45,6 +50,9 @@ const (
 	defaultTSOUpdatePhysicalInterval = 50 * time.Millisecond
 	maxTSOUpdatePhysicalInterval     = 10 * time.Second
 	minTSOUpdatePhysicalInterval     = 1 * time.Millisecond
+
+	// defaultPreflightDialTimeout is the timeout to dial each backend endpoint during the pre-flight check.
+	defaultPreflightDialTimeout = 3 * time.Second
 )
 
 var _ tso.ServiceConfig = (*Config)(nil)
@@ -87,6 +95,8 @@ type Config struct {
 
 	// WarningMsgs contains all warnings during parsing.
 	WarningMsgs []string
+	// Warnings contains all warnings during parsing with their codes.
+	Warnings []ConfigWarning `toml:"-" json:"-"`
 
 	// Log related config.
 	Log log.Config `toml:"log" json:"log"`
@@ -95,6 +105,35 @@ type Config struct {
 	LogProps *log.ZapProperties
 
 	Security configutil.SecurityConfig `toml:"security" json:"security"`
+
+	// mu protects the fields which can be reloaded at runtime,
+	// i.e. LeaderLease, TSOSaveInterval, TSOUpdatePhysicalInterval and MaxResetTSGap.
+	mu syncutil.RWMutex
+}
+
+// ConfigWarningCode identifies the kind of a config warning.
+type ConfigWarningCode string
+
+const (
+	// WarningUndecodedItem means the config contains undefined items.
+	WarningUndecodedItem ConfigWarningCode = "undecoded-item"
+	// WarningNonDefaultUpdatePhysicalInterval means the TSO update physical interval is non-default.
+	WarningNonDefaultUpdatePhysicalInterval ConfigWarningCode = "non-default-update-physical-interval"
+	// WarningClampedItem means a config item is out of its valid range and clamped.
+	WarningClampedItem ConfigWarningCode = "clamped-item"
+	// WarningMalformedBackendEndpoint means a backend endpoint is malformed and ignored.
+	WarningMalformedBackendEndpoint ConfigWarningCode = "malformed-backend-endpoint"
+)
+
+// ConfigWarning is a warning found when adjusting the config.
+type ConfigWarning struct {
+	Code    ConfigWarningCode
+	Message string
+}
+
+// String renders the warning for logging.
+func (w ConfigWarning) String() string {
+	return fmt.Sprintf("[%s] %s", w.Code, w.Message)
 }
 
 // NewConfig creates a new config.
@@ -124,9 +163,21 @@ func (c *Config) GetAdvertiseListenAddr() string {
 
 // GetLeaderLease returns the leader lease.
 func (c *Config) GetLeaderLease() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.LeaderLease
 }
 
+// GetLeaderLeaseRemaining returns how much of the leader lease remains at now
+// since the last renewal. It returns 0 once the lease has expired.
+func (c *Config) GetLeaderLeaseRemaining(lastRenewal, now time.Time) time.Duration {
+	remaining := time.Duration(c.GetLeaderLease())*time.Second - now.Sub(lastRenewal)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
 // IsLocalTSOEnabled returns if the local TSO is enabled.
 func (c *Config) IsLocalTSOEnabled() bool {
 	return c.EnableLocalTSO
@@ -134,16 +185,22 @@ func (c *Config) IsLocalTSOEnabled() bool {
 
 // GetTSOUpdatePhysicalInterval returns TSO update physical interval.
 func (c *Config) GetTSOUpdatePhysicalInterval() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.TSOUpdatePhysicalInterval.Duration
 }
 
 // GetTSOSaveInterval returns TSO save interval.
 func (c *Config) GetTSOSaveInterval() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.TSOSaveInterval.Duration
 }
 
 // GetMaxResetTSGap returns the MaxResetTSGap.
 func (c *Config) GetMaxResetTSGap() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.MaxResetTSGap.Duration
 }
 
@@ -152,6 +209,21 @@ func (c *Config) GetTLSConfig() *grpcutil.TLSConfig {
 	return &c.Security.TLSConfig
 }
 
+// Reload updates the fields which can be changed at runtime from the adjusted new config.
+func (c *Config) Reload(newCfg *Config) {
+	newCfg.mu.RLock()
+	leaderLease, saveInterval := newCfg.LeaderLease, newCfg.TSOSaveInterval
+	updatePhysicalInterval, maxResetTSGap := newCfg.TSOUpdatePhysicalInterval, newCfg.MaxResetTSGap
+	newCfg.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.LeaderLease = leaderLease
+	c.TSOSaveInterval = saveInterval
+	c.TSOUpdatePhysicalInterval = updatePhysicalInterval
+	c.MaxResetTSGap = maxResetTSGap
+}
+
 // Parse parses flag definitions from the argument list.
 func (c *Config) Parse(flagSet *pflag.FlagSet) error {
 	// Load config file if specified.
@@ -184,7 +256,7 @@ func (c *Config) Parse(flagSet *pflag.FlagSet) error {
 func (c *Config) Adjust(meta *toml.MetaData) error {
 	configMetaData := configutil.NewConfigMetadata(meta)
 	if err := configMetaData.CheckUndecoded(); err != nil {
-		c.WarningMsgs = append(c.WarningMsgs, err.Error())
+		c.addWarning(WarningUndecodedItem, err.Error())
 	}
 	if c.Name == "" {
 		hostname, err := os.Hostname()
@@ -201,6 +273,7 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 	}
 
 	configutil.AdjustString(&c.BackendEndpoints, defaultBackendEndpoints)
+	c.adjustBackendEndpoints()
 	configutil.AdjustString(&c.ListenAddr, defaultListenAddr)
 	configutil.AdjustString(&c.AdvertiseListenAddr, c.ListenAddr)
 
@@ -209,14 +282,18 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 	configutil.AdjustDuration(&c.TSOSaveInterval, defaultTSOSaveInterval)
 	configutil.AdjustDuration(&c.TSOUpdatePhysicalInterval, defaultTSOUpdatePhysicalInterval)
 
-	if c.TSOUpdatePhysicalInterval.Duration > maxTSOUpdatePhysicalInterval {
+	if interval := c.TSOUpdatePhysicalInterval.Duration; interval > maxTSOUpdatePhysicalInterval {
 		c.TSOUpdatePhysicalInterval.Duration = maxTSOUpdatePhysicalInterval
-	} else if c.TSOUpdatePhysicalInterval.Duration < minTSOUpdatePhysicalInterval {
+		c.addClampWarning("tso-update-physical-interval", interval, maxTSOUpdatePhysicalInterval)
+	} else if interval < minTSOUpdatePhysicalInterval {
 		c.TSOUpdatePhysicalInterval.Duration = minTSOUpdatePhysicalInterval
+		c.addClampWarning("tso-update-physical-interval", interval, minTSOUpdatePhysicalInterval)
 	}
 	if c.TSOUpdatePhysicalInterval.Duration != defaultTSOUpdatePhysicalInterval {
 		log.Warn("tso update physical interval is non-default",
 			zap.Duration("update-physical-interval", c.TSOUpdatePhysicalInterval.Duration))
+		c.addWarning(WarningNonDefaultUpdatePhysicalInterval,
+			fmt.Sprintf("tso update physical interval %s is non-default", c.TSOUpdatePhysicalInterval.Duration))
 	}
 
 	if !configMetaData.IsDefined("enable-grpc-gateway") {
@@ -229,6 +306,34 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 	return nil
 }
 
+// addWarning records a warning with its code, the message is also kept in WarningMsgs.
+func (c *Config) addWarning(code ConfigWarningCode, msg string) {
+	c.Warnings = append(c.Warnings, ConfigWarning{Code: code, Message: msg})
+	c.WarningMsgs = append(c.WarningMsgs, msg)
+}
+
+// addClampWarning records that the config item is clamped from the original value.
+func (c *Config) addClampWarning(item string, original, clamped any) {
+	c.addWarning(WarningClampedItem, fmt.Sprintf("%s is clamped from %v to %v", item, original, clamped))
+}
+
+// adjustBackendEndpoints normalizes the backend endpoints to be comma separated,
+// the malformed ones are dropped with a warning unless none of them is valid.
+func (c *Config) adjustBackendEndpoints() {
+	var valid []string
+	for _, endpoint := range utils.SplitBackendEndpoints(c.BackendEndpoints) {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			c.addWarning(WarningMalformedBackendEndpoint, fmt.Sprintf("malformed backend endpoint %q is ignored", endpoint))
+			continue
+		}
+		valid = append(valid, endpoint)
+	}
+	if len(valid) > 0 {
+		c.BackendEndpoints = strings.Join(valid, ",")
+	}
+}
+
 func (c *Config) adjustLog(meta *configutil.ConfigMetaData) {
 	if !meta.IsDefined("disable-error-verbose") {
 		c.Log.DisableErrorVerbose = utils.DefaultDisableErrorVerbose
@@ -257,3 +362,46 @@ func (c *Config) Validate() error {
 
 	return nil
 }
+
+// PreflightCheck dials all the backend endpoints in parallel with a short timeout.
+// It's used to fail fast on misconfigured endpoints before the server enters its
+// main loop. Since the backend stays available with some members down, it only
+// fails if none of the endpoints is reachable, and warns about the unreachable
+// ones otherwise.
+func (c *Config) PreflightCheck(ctx context.Context) error {
+	endpoints := utils.SplitBackendEndpoints(c.BackendEndpoints)
+	failures := make([]error, len(endpoints))
+	dialer := &net.Dialer{Timeout: defaultPreflightDialTimeout}
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			u, err := url.Parse(endpoint)
+			if err != nil {
+				failures[i] = err
+				return
+			}
+			conn, err := dialer.DialContext(ctx, "tcp", u.Host)
+			if err != nil {
+				failures[i] = err
+				return
+			}
+			conn.Close()
+		}(i, endpoint)
+	}
+	wg.Wait()
+	unreachable := make([]string, 0, len(endpoints))
+	for i, err := range failures {
+		if err != nil {
+			unreachable = append(unreachable, fmt.Sprintf("%s: %v", endpoints[i], err))
+		}
+	}
+	if len(unreachable) > 0 && len(unreachable) == len(endpoints) {
+		return errors.Errorf("unreachable backend endpoints: %s", strings.Join(unreachable, "; "))
+	}
+	for _, failure := range unreachable {
+		log.Warn("backend endpoint is unreachable", zap.String("failure", failure))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// FieldChange is a config item which differs between two configs.
+type FieldChange struct {
+	// Field is the full JSON name of the config item, e.g. "security.cacert-path".
+	Field    string
+	OldValue any
+	NewValue any
+}
+
+// Diff returns the changed config items from oldCfg to newCfg in the field order.
+// Only the config items with a JSON name are compared.
+func Diff(oldCfg, newCfg *Config) []FieldChange {
+	oldCfg.mu.RLock()
+	defer oldCfg.mu.RUnlock()
+	if oldCfg != newCfg {
+		newCfg.mu.RLock()
+		defer newCfg.mu.RUnlock()
+	}
+	return diffStruct("", reflect.ValueOf(oldCfg).Elem(), reflect.ValueOf(newCfg).Elem(), nil)
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+func diffStruct(prefix string, oldValues, newValues reflect.Value, changes []FieldChange) []FieldChange {
+	for i := 0; i < oldValues.NumField(); i++ {
+		field := oldValues.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			// The embedded struct is flattened into its parent.
+			changes = diffStruct(prefix, oldValues.Field(i), newValues.Field(i), changes)
+			continue
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+		oldValue, newValue := oldValues.Field(i), newValues.Field(i)
+		// The structs with their own JSON encoding such as Duration are compared as a whole.
+		if field.Type.Kind() == reflect.Struct && !reflect.PointerTo(field.Type).Implements(jsonMarshalerType) {
+			changes = diffStruct(prefix+name+".", oldValue, newValue, changes)
+			continue
+		}
+		if !reflect.DeepEqual(oldValue.Interface(), newValue.Interface()) {
+			changes = append(changes, FieldChange{
+				Field:    prefix + name,
+				OldValue: oldValue.Interface(),
+				NewValue: newValue.Interface(),
+			})
+		}
+	}
+	return changes
+}
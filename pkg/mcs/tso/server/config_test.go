@@ -15,13 +15,21 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/pd/pkg/mcs/utils"
+	"github.com/tikv/pd/pkg/utils/configutil"
 )
 
 func TestConfigBasic(t *testing.T) {
@@ -97,3 +105,192 @@ max-gap-reset-ts = "1h"
 	re.Equal(time.Duration(100)*time.Millisecond, cfg.TSOUpdatePhysicalInterval.Duration)
 	re.Equal(time.Duration(1)*time.Hour, cfg.MaxResetTSGap.Duration)
 }
+
+func TestPreflightCheck(t *testing.T) {
+	re := require.New(t)
+
+	reachable, err := net.Listen("tcp", "127.0.0.1:0")
+	re.NoError(err)
+	defer reachable.Close()
+	// Close the listener immediately to get an address nobody listens on.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	re.NoError(err)
+	unreachableAddr := closed.Addr().String()
+	closed.Close()
+
+	cfg := NewConfig()
+	cfg.BackendEndpoints = "http://" + reachable.Addr().String()
+	re.NoError(cfg.PreflightCheck(context.Background()))
+
+	// It's fine as long as any endpoint is reachable.
+	cfg.BackendEndpoints = strings.Join([]string{
+		"http://" + reachable.Addr().String(),
+		"http://" + unreachableAddr,
+		"http://%zz",
+	}, ",")
+	re.NoError(cfg.PreflightCheck(context.Background()))
+
+	// All the unreachable endpoints are reported.
+	cfg.BackendEndpoints = strings.Join([]string{
+		"http://" + unreachableAddr,
+		"http://%zz",
+	}, ",")
+	err = cfg.PreflightCheck(context.Background())
+	re.Error(err)
+	re.Contains(err.Error(), unreachableAddr)
+	re.Contains(err.Error(), "%zz")
+}
+
+func TestGetLeaderLeaseRemaining(t *testing.T) {
+	re := require.New(t)
+	cfg := NewConfig()
+	cfg.LeaderLease = 4
+	lastRenewal := time.Now()
+	re.Equal(4*time.Second, cfg.GetLeaderLeaseRemaining(lastRenewal, lastRenewal))
+	re.Equal(2*time.Second, cfg.GetLeaderLeaseRemaining(lastRenewal, lastRenewal.Add(2*time.Second)))
+	re.Zero(cfg.GetLeaderLeaseRemaining(lastRenewal, lastRenewal.Add(4*time.Second)))
+	re.Zero(cfg.GetLeaderLeaseRemaining(lastRenewal, lastRenewal.Add(time.Minute)))
+}
+
+func TestAdjustBackendEndpoints(t *testing.T) {
+	re := require.New(t)
+	cfg := NewConfig()
+	cfg.BackendEndpoints = " http://127.0.0.1:2379,\n http://127.0.0.2:2379  http://%zz,,\nhttp://127.0.0.3:2379\n"
+	re.NoError(cfg.Adjust(nil))
+	re.Equal("http://127.0.0.1:2379,http://127.0.0.2:2379,http://127.0.0.3:2379", cfg.BackendEndpoints)
+	re.Len(cfg.WarningMsgs, 1)
+	re.Contains(cfg.WarningMsgs[0], "http://%zz")
+}
+
+func TestConfigWarnings(t *testing.T) {
+	re := require.New(t)
+	cfgData := `
+tso-update-physical-interval = "100ms"
+unknown-item = "foo"
+`
+	cfg := NewConfig()
+	meta, err := toml.Decode(cfgData, &cfg)
+	re.NoError(err)
+	re.NoError(cfg.Adjust(&meta))
+
+	codes := make([]ConfigWarningCode, 0, len(cfg.Warnings))
+	for _, warning := range cfg.Warnings {
+		codes = append(codes, warning.Code)
+		re.Contains(cfg.WarningMsgs, warning.Message)
+	}
+	re.ElementsMatch([]ConfigWarningCode{WarningUndecodedItem, WarningNonDefaultUpdatePhysicalInterval}, codes)
+	re.Len(cfg.WarningMsgs, 2)
+	re.Equal("[non-default-update-physical-interval] tso update physical interval 100ms is non-default", cfg.Warnings[1].String())
+}
+
+func TestConfigClampWarning(t *testing.T) {
+	re := require.New(t)
+	cfg := NewConfig()
+	cfg.TSOUpdatePhysicalInterval.Duration = time.Minute
+	re.NoError(cfg.Adjust(nil))
+	re.Equal(maxTSOUpdatePhysicalInterval, cfg.TSOUpdatePhysicalInterval.Duration)
+	re.Equal(WarningClampedItem, cfg.Warnings[0].Code)
+	re.Equal("tso-update-physical-interval is clamped from 1m0s to 10s", cfg.Warnings[0].Message)
+
+	cfg = NewConfig()
+	cfg.TSOUpdatePhysicalInterval.Duration = time.Microsecond
+	re.NoError(cfg.Adjust(nil))
+	re.Equal(minTSOUpdatePhysicalInterval, cfg.TSOUpdatePhysicalInterval.Duration)
+	re.Contains(cfg.WarningMsgs, "tso-update-physical-interval is clamped from 1µs to 1ms")
+}
+
+func TestConfigCheck(t *testing.T) {
+	re := require.New(t)
+	parse := func(cfgData string) (*Config, error) {
+		cfgFile := filepath.Join(t.TempDir(), "tso.toml")
+		re.NoError(os.WriteFile(cfgFile, []byte(cfgData), 0o600))
+		flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flagSet.StringP("config", "", "", "config file")
+		flagSet.BoolP("config-check", "", false, "check config file validity and exit")
+		re.NoError(flagSet.Parse([]string{"--config=" + cfgFile, "--config-check"}))
+		cfg := NewConfig()
+		return cfg, cfg.Parse(flagSet)
+	}
+
+	cfg, err := parse(`
+backend-endpoints = "http://127.0.0.1:2379,http://%zz"
+tso-update-physical-interval = "1m"
+unknown-item = "foo"
+`)
+	re.NoError(err)
+	var buf bytes.Buffer
+	configutil.PrintConfigCheckMsg(&buf, cfg.WarningMsgs)
+	output := buf.String()
+	re.Contains(output, "unknown-item")
+	re.Contains(output, "http://%zz")
+	re.Contains(output, "tso-update-physical-interval is clamped")
+
+	// The log directory inside the data directory is rejected.
+	_, err = parse(`
+data-dir = "/tmp/tso"
+[log.file]
+filename = "/tmp/tso/log/tso.log"
+`)
+	re.ErrorContains(err, "log directory shouldn't be the subdirectory of data directory")
+}
+
+func TestConfigReload(t *testing.T) {
+	re := require.New(t)
+	cfg := NewConfig()
+	re.NoError(cfg.Adjust(nil))
+	newCfg := NewConfig()
+	newCfg.LeaderLease = 10
+	newCfg.TSOUpdatePhysicalInterval.Duration = 100 * time.Millisecond
+	re.NoError(newCfg.Adjust(nil))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				cfg.GetLeaderLease()
+				cfg.GetTSOUpdatePhysicalInterval()
+				cfg.GetTSOSaveInterval()
+				cfg.GetMaxResetTSGap()
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		cfg.Reload(newCfg)
+	}
+	wg.Wait()
+	re.Equal(int64(10), cfg.GetLeaderLease())
+	re.Equal(100*time.Millisecond, cfg.GetTSOUpdatePhysicalInterval())
+	re.Equal(newCfg.GetTSOSaveInterval(), cfg.GetTSOSaveInterval())
+	re.Equal(newCfg.GetMaxResetTSGap(), cfg.GetMaxResetTSGap())
+}
+
+func TestConfigDiff(t *testing.T) {
+	re := require.New(t)
+	oldCfg := NewConfig()
+	re.NoError(oldCfg.Adjust(nil))
+	re.Empty(Diff(oldCfg, oldCfg))
+
+	newCfg := NewConfig()
+	re.NoError(newCfg.Adjust(nil))
+	newCfg.Name = oldCfg.Name
+	newCfg.DataDir = oldCfg.DataDir
+	re.Empty(Diff(oldCfg, newCfg))
+
+	newCfg.LeaderLease = 10
+	newCfg.TSOSaveInterval.Duration = 5 * time.Second
+	newCfg.Metric.PushAddress = "127.0.0.1:9091"
+	newCfg.Security.CAPath = "/path/to/ca"
+	newCfg.Security.RedactInfoLog = true
+	newCfg.Log.File.Filename = "/path/to/tso.log"
+	changes := Diff(oldCfg, newCfg)
+	re.Equal([]FieldChange{
+		{Field: "lease", OldValue: oldCfg.LeaderLease, NewValue: int64(10)},
+		{Field: "tso-save-interval", OldValue: oldCfg.TSOSaveInterval, NewValue: newCfg.TSOSaveInterval},
+		{Field: "metric.address", OldValue: "", NewValue: "127.0.0.1:9091"},
+		{Field: "log.file.filename", OldValue: "", NewValue: "/path/to/tso.log"},
+		{Field: "security.cacert-path", OldValue: "", NewValue: "/path/to/ca"},
+		{Field: "security.redact-info-log", OldValue: false, NewValue: true},
+	}, changes)
+}
@@ -46,6 +46,7 @@ import (
 	"github.com/tikv/pd/pkg/systimemon"
 	"github.com/tikv/pd/pkg/tso"
 	"github.com/tikv/pd/pkg/utils/apiutil"
+	"github.com/tikv/pd/pkg/utils/configutil"
 	"github.com/tikv/pd/pkg/utils/grpcutil"
 	"github.com/tikv/pd/pkg/utils/logutil"
 	"github.com/tikv/pd/pkg/utils/metricutil"
@@ -454,6 +455,14 @@ func CreateServerWrapper(cmd *cobra.Command, args []string) {
 		utils.Exit(0)
 	}
 
+	if configCheck, err := flagSet.GetBool("config-check"); err != nil {
+		cmd.Println(err)
+		return
+	} else if configCheck {
+		configutil.PrintConfigCheckMsg(os.Stdout, cfg.WarningMsgs)
+		utils.Exit(0)
+	}
+
 	// New zap logger
 	err = logutil.SetupLogger(cfg.Log, &cfg.Logger, &cfg.LogProps, cfg.Security.RedactInfoLog)
 	if err == nil {
@@ -471,6 +480,9 @@ func CreateServerWrapper(cmd *cobra.Command, args []string) {
 	metricutil.Push(&cfg.Metric)
 
 	ctx, cancel := context.WithCancel(context.Background())
+	if err := cfg.PreflightCheck(ctx); err != nil {
+		log.Fatal("pre-flight check failed", errs.ZapError(err))
+	}
 	svr := CreateServer(ctx, cfg)
 
 	sc := make(chan os.Signal, 1)
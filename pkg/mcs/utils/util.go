@@ -22,6 +22,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/errors"
@@ -142,8 +143,16 @@ func WaitAPIServiceReady(s server) error {
 	}
 }
 
+// SplitBackendEndpoints splits the backend endpoints separated by commas, spaces or newlines.
+// The empty entries are dropped.
+func SplitBackendEndpoints(endpoints string) []string {
+	return strings.FieldsFunc(endpoints, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
 func isAPIServiceReady(s server) (bool, error) {
-	urls := strings.Split(s.GetBackendEndpoints(), ",")
+	urls := SplitBackendEndpoints(s.GetBackendEndpoints())
 	if len(urls) == 0 {
 		return false, errors.New("no backend endpoints")
 	}
@@ -174,7 +183,7 @@ func InitClient(s server) error {
 	if err != nil {
 		return err
 	}
-	backendUrls, err := types.NewURLs(strings.Split(s.GetBackendEndpoints(), ","))
+	backendUrls, err := types.NewURLs(SplitBackendEndpoints(s.GetBackendEndpoints()))
 	if err != nil {
 		return err
 	}
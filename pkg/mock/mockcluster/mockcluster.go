@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/docker/go-units"
@@ -59,6 +60,7 @@ type Cluster struct {
 	suspectRegions map[uint64]struct{}
 	*buckets.HotBucketCache
 	storage.Storage
+	notPrepared atomic.Bool
 }
 
 // NewCluster creates a new Cluster
@@ -82,6 +84,16 @@ func NewCluster(ctx context.Context, opts *config.PersistOptions) *Cluster {
 	return c
 }
 
+// IsPrepared returns whether the cluster is prepared, it's prepared by default.
+func (mc *Cluster) IsPrepared() bool {
+	return !mc.notPrepared.Load()
+}
+
+// SetPrepared sets whether the cluster is prepared.
+func (mc *Cluster) SetPrepared(prepared bool) {
+	mc.notPrepared.Store(!prepared)
+}
+
 // GetStoreConfig returns the store config.
 func (mc *Cluster) GetStoreConfig() sc.StoreConfigProvider {
 	return mc.PersistOptions.GetStoreConfig()
@@ -733,6 +745,13 @@ func (mc *Cluster) UpdateStorageWriteQuery(storeID uint64, queryWrite uint64) {
 	})
 }
 
+// UpdateStorageCPUUsage updates store cpu usage.
+func (mc *Cluster) UpdateStorageCPUUsage(storeID uint64, cpuUsage uint64) {
+	mc.updateStorageStatistics(storeID, func(newStats *pdpb.StoreStats) {
+		newStats.CpuUsages = []*pdpb.RecordPair{{Key: "cpu", Value: cpuUsage}}
+	})
+}
+
 func (mc *Cluster) updateStorageStatistics(storeID uint64, update func(*pdpb.StoreStats)) {
 	store := mc.GetStore(storeID)
 	newStats := typeutil.DeepClone(store.GetStoreStats(), core.StoreStatsFactory)
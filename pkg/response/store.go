@@ -70,6 +70,8 @@ type StoreStatus struct {
 	SendingSnapCount   uint32             `json:"sending_snap_count,omitempty"`
 	ReceivingSnapCount uint32             `json:"receiving_snap_count,omitempty"`
 	IsBusy             bool               `json:"is_busy,omitempty"`
+	LeaderPauseReason  string             `json:"leader_pause_reason,omitempty"`
+	LeaderPausers      []string           `json:"leader_pausers,omitempty"`
 	StartTS            *time.Time         `json:"start_ts,omitempty"`
 	LastHeartbeatTS    *time.Time         `json:"last_heartbeat_ts,omitempty"`
 	Uptime             *typeutil.Duration `json:"uptime,omitempty"`
@@ -120,6 +122,8 @@ func BuildStoreInfo(opt *sc.ScheduleConfig, store *core.StoreInfo) *StoreInfo {
 			ReceivingSnapCount: store.GetReceivingSnapCount(),
 			PendingPeerCount:   store.GetPendingPeerCount(),
 			IsBusy:             store.IsBusy(),
+			LeaderPauseReason:  store.GetPauseLeaderTransferReason(),
+			LeaderPausers:      store.GetLeaderTransferPausers(),
 		},
 	}
 
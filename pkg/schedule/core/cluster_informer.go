@@ -44,6 +44,9 @@ type SchedulerCluster interface {
 	GetRegionLabeler() *labeler.RegionLabeler
 	GetStoreConfig() sc.StoreConfigProvider
 	IsSchedulingHalted() bool
+	// IsPrepared returns true once the region information has caught up after
+	// the cluster starts, e.g. after the leader switch.
+	IsPrepared() bool
 }
 
 // CheckerCluster is an aggregate interface that wraps multiple interfaces
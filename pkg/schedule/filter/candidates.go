@@ -22,6 +22,7 @@ import (
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/schedule/config"
 	"github.com/tikv/pd/pkg/schedule/plan"
+	"github.com/tikv/pd/pkg/slice"
 )
 
 // StoreCandidates wraps store list and provide utilities to select source or
@@ -36,6 +37,13 @@ func NewCandidates(stores []*core.StoreInfo) *StoreCandidates {
 	return &StoreCandidates{r: rand.New(rand.NewSource(time.Now().UnixNano())), Stores: stores}
 }
 
+// WithRand replaces the random source used by Shuffle and RandomPick,
+// which makes the selection reproducible with a seeded source.
+func (c *StoreCandidates) WithRand(r *rand.Rand) *StoreCandidates {
+	c.r = r
+	return c
+}
+
 // FilterSource keeps stores that can pass all source filters.
 func (c *StoreCandidates) FilterSource(conf config.SharedConfigProvider, collector *plan.Collector, counter *Counter, filters ...Filter) *StoreCandidates {
 	c.Stores = SelectSourceStores(c.Stores, filters, conf, collector, counter)
@@ -56,7 +64,7 @@ func (c *StoreCandidates) Sort(less StoreComparer) *StoreCandidates {
 
 // Shuffle reorders all candidates randomly.
 func (c *StoreCandidates) Shuffle() *StoreCandidates {
-	c.r.Shuffle(len(c.Stores), func(i, j int) { c.Stores[i], c.Stores[j] = c.Stores[j], c.Stores[i] })
+	slice.Shuffle(c.Stores, c.r)
 	return c
 }
 
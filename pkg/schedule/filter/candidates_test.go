@@ -15,6 +15,7 @@
 package filter
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -107,6 +108,27 @@ func TestCandidates(t *testing.T) {
 	check(re, cs, 10, 15)
 }
 
+func TestCandidatesWithRand(t *testing.T) {
+	re := require.New(t)
+	pick := func(seed int64) []uint64 {
+		cs := newTestCandidates(1, 2, 3, 4, 5, 6, 7).WithRand(rand.New(rand.NewSource(seed)))
+		ids := make([]uint64, 0, 10)
+		for i := 0; i < 10; i++ {
+			ids = append(ids, cs.RandomPick().GetID())
+		}
+		cs.Shuffle()
+		for _, store := range cs.Stores {
+			ids = append(ids, store.GetID())
+		}
+		return ids
+	}
+	// The same seed should always lead to the same selection.
+	expected := pick(42)
+	for i := 0; i < 5; i++ {
+		re.Equal(expected, pick(42))
+	}
+}
+
 func newTestCandidates(ids ...uint64) *StoreCandidates {
 	stores := make([]*core.StoreInfo, 0, len(ids))
 	for _, id := range ids {
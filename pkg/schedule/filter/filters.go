@@ -958,6 +958,44 @@ func (f *isolationFilter) Target(_ config.SharedConfigProvider, store *core.Stor
 	return statusOK
 }
 
+type labelIsolationFilter struct {
+	scope    string
+	labelKey string
+	values   map[string]struct{}
+}
+
+// NewLabelIsolationFilter creates a filter that filters out the target stores
+// sharing the same value of labelKey with any of the given stores, e.g. the
+// stores in the same zone as them. The stores without the label are ignored.
+func NewLabelIsolationFilter(scope, labelKey string, stores []*core.StoreInfo) Filter {
+	values := make(map[string]struct{}, len(stores))
+	for _, store := range stores {
+		if value := store.GetLabelValue(labelKey); value != "" {
+			values[value] = struct{}{}
+		}
+	}
+	return &labelIsolationFilter{scope: scope, labelKey: labelKey, values: values}
+}
+
+func (f *labelIsolationFilter) Scope() string {
+	return f.scope
+}
+
+func (*labelIsolationFilter) Type() filterType {
+	return isolation
+}
+
+func (*labelIsolationFilter) Source(config.SharedConfigProvider, *core.StoreInfo) *plan.Status {
+	return statusOK
+}
+
+func (f *labelIsolationFilter) Target(_ config.SharedConfigProvider, store *core.StoreInfo) *plan.Status {
+	if _, ok := f.values[store.GetLabelValue(f.labelKey)]; ok {
+		return statusStoreNotMatchIsolation
+	}
+	return statusOK
+}
+
 // createRegionForRuleFit is used to create a clone region with RegionCreateOptions which is only used for
 // FitRegion in filter
 func createRegionForRuleFit(startKey, endKey []byte,
@@ -495,3 +495,31 @@ func BenchmarkCloneRegionTest(b *testing.B) {
 		_ = createRegionForRuleFit(region.GetStartKey(), region.GetEndKey(), region.GetPeers(), region.GetLeader())
 	}
 }
+
+func TestLabelIsolationFilter(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opt := mockconfig.NewTestOptions()
+	testCluster := mockcluster.NewCluster(ctx, opt)
+	testCluster.AddLabelsStore(1, 1, map[string]string{"zone": "z1", "host": "h1"})
+	testCluster.AddLabelsStore(2, 1, map[string]string{"zone": "z1", "host": "h2"})
+	testCluster.AddLabelsStore(3, 1, map[string]string{"zone": "z2", "host": "h1"})
+	testCluster.AddLabelsStore(4, 1, map[string]string{"zone": "z3", "host": "h1"})
+	testCluster.AddLabelsStore(5, 1, map[string]string{"host": "h1"})
+
+	f := NewLabelIsolationFilter("", "zone", []*core.StoreInfo{testCluster.GetStore(1), testCluster.GetStore(4), testCluster.GetStore(5)})
+	expected := []plan.StatusCode{plan.StatusStoreNotMatchIsolation, plan.StatusStoreNotMatchIsolation, plan.StatusOK, plan.StatusStoreNotMatchIsolation, plan.StatusOK}
+	for i, code := range expected {
+		store := testCluster.GetStore(uint64(i + 1))
+		re.True(f.Source(testCluster.GetSharedConfig(), store).IsOK())
+		re.Equal(code, f.Target(testCluster.GetSharedConfig(), store).StatusCode)
+	}
+
+	candidates := NewCandidates(testCluster.GetStores()).FilterTarget(testCluster.GetSharedConfig(), nil, nil, f)
+	re.Len(candidates.Stores, 2)
+	for _, store := range candidates.Stores {
+		re.NotEqual("z1", store.GetLabelValue("zone"))
+	}
+}
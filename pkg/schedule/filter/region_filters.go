@@ -45,23 +45,41 @@ func filterRegionsBy(regions []*core.RegionInfo, keepPred func(*core.RegionInfo)
 // SelectOneRegion selects one region that be selected from the list.
 func SelectOneRegion(regions []*core.RegionInfo, collector *plan.Collector, filters ...RegionFilter) *core.RegionInfo {
 	for _, r := range regions {
-		if len(filters) == 0 || slice.AllOf(filters,
-			func(i int) bool {
-				status := filters[i].Select(r)
-				if !status.IsOK() {
-					if collector != nil {
-						collector.Collect(plan.SetResource(r), plan.SetStatus(status))
-					}
-					return false
-				}
-				return true
-			}) {
+		if isRegionSelected(r, collector, filters) {
 			return r
 		}
 	}
 	return nil
 }
 
+// SelectLargestRegion selects the region with the largest approximate size that be selected from the list.
+func SelectLargestRegion(regions []*core.RegionInfo, collector *plan.Collector, filters ...RegionFilter) *core.RegionInfo {
+	var largest *core.RegionInfo
+	for _, r := range regions {
+		if largest != nil && r.GetApproximateSize() <= largest.GetApproximateSize() {
+			continue
+		}
+		if isRegionSelected(r, collector, filters) {
+			largest = r
+		}
+	}
+	return largest
+}
+
+func isRegionSelected(r *core.RegionInfo, collector *plan.Collector, filters []RegionFilter) bool {
+	return len(filters) == 0 || slice.AllOf(filters,
+		func(i int) bool {
+			status := filters[i].Select(r)
+			if !status.IsOK() {
+				if collector != nil {
+					collector.Collect(plan.SetResource(r), plan.SetStatus(status))
+				}
+				return false
+			}
+			return true
+		})
+}
+
 // RegionFilter is an interface to filter region.
 type RegionFilter interface {
 	// Return plan.Status show whether be filtered
@@ -127,3 +127,24 @@ func TestRegionWitnessFilter(t *testing.T) {
 	}}, &metapb.Peer{StoreId: 1, Id: 1})
 	re.Equal(filter.Select(region), statusOK)
 }
+
+func TestSelectLargestRegion(t *testing.T) {
+	re := require.New(t)
+	newRegion := func(id uint64, size int64) *core.RegionInfo {
+		return core.NewRegionInfo(&metapb.Region{Id: id, Peers: []*metapb.Peer{
+			{StoreId: 1, Id: id*10 + 1},
+			{StoreId: 2, Id: id*10 + 2},
+		}}, &metapb.Peer{StoreId: 1, Id: id*10 + 1}, core.SetApproximateSize(size))
+	}
+	pendingRegion := newRegion(1, 300)
+	pendingRegion = pendingRegion.Clone(core.WithPendingPeers([]*metapb.Peer{pendingRegion.GetStorePeer(2)}))
+	downRegion := newRegion(2, 200)
+	downRegion = downRegion.Clone(core.WithDownPeers([]*pdpb.PeerStats{{Peer: downRegion.GetStorePeer(2), DownSeconds: 24 * 60 * 60}}))
+	regions := []*core.RegionInfo{newRegion(3, 50), pendingRegion, newRegion(4, 100), downRegion, newRegion(5, 80)}
+
+	re.Equal(uint64(1), SelectLargestRegion(regions, nil).GetID())
+	re.Equal(uint64(4), SelectLargestRegion(regions, nil, NewRegionPendingFilter(), NewRegionDownFilter()).GetID())
+	re.Equal(uint64(2), SelectLargestRegion(regions, nil, NewRegionPendingFilter()).GetID())
+	re.Nil(SelectLargestRegion(nil, nil))
+	re.Nil(SelectLargestRegion([]*core.RegionInfo{pendingRegion}, nil, NewRegionPendingFilter()))
+}
@@ -237,6 +237,15 @@ func (h *Handler) GetRecords(from time.Time) ([]*operator.OpRecord, error) {
 	return records, nil
 }
 
+// GetRegionOperatorRecords returns the recent finished operators of the region.
+func (h *Handler) GetRegionOperatorRecords(regionID uint64) ([]*operator.RegionOpRecord, error) {
+	c, err := h.GetOperatorController()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetRegionRecords(regionID), nil
+}
+
 // HandleOperatorCreation processes the request and creates an operator based on the provided input.
 // It supports various types of operators such as transfer-leader, transfer-region, add-peer, remove-peer, merge-region, split-region, scatter-region, and scatter-regions.
 // The function validates the input, performs the corresponding operation, and returns the HTTP status code, response body, and any error encountered during the process.
@@ -803,6 +812,24 @@ func (h *Handler) GetSchedulerNames() ([]string, error) {
 	return sc.GetSchedulerNames(), nil
 }
 
+// ExportSchedulerConfigs exports the configs of all schedulers as a single bundle.
+func (h *Handler) ExportSchedulerConfigs() (schedulers.ConfigBundle, error) {
+	sc, err := h.GetSchedulersController()
+	if err != nil {
+		return nil, err
+	}
+	return sc.ExportSchedulerConfigs()
+}
+
+// ImportSchedulerConfigs imports the scheduler configs in the bundle.
+func (h *Handler) ImportSchedulerConfigs(bundle schedulers.ConfigBundle) error {
+	sc, err := h.GetSchedulersController()
+	if err != nil {
+		return err
+	}
+	return sc.ImportSchedulerConfigs(bundle)
+}
+
 type schedulerPausedPeriod struct {
 	Name     string    `json:"name"`
 	PausedAt time.Time `json:"paused_at"`
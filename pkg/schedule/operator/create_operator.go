@@ -85,6 +85,19 @@ func CreateTransferLeaderOperator(desc string, ci sche.SharedCluster, region *co
 		Build(kind)
 }
 
+// CreateHealthCheckedTransferLeaderOperator creates an operator like CreateTransferLeaderOperator,
+// but it re-checks the health of the target peer before transferring the leader, see WaitPeerHealthy.
+// Only the peer of targetStoreID is re-checked. The other targets are kept, and TiKV
+// already refuses to transfer the leader to a peer whose log lags too far behind.
+func CreateHealthCheckedTransferLeaderOperator(desc string, ci sche.SharedCluster, region *core.RegionInfo, targetStoreID uint64, targetStoreIDs []uint64, kind OpKind) (*Operator, error) {
+	op, err := CreateTransferLeaderOperator(desc, ci, region, targetStoreID, targetStoreIDs, kind)
+	if err != nil {
+		return nil, err
+	}
+	steps := append([]OpStep{WaitPeerHealthy{ToStore: targetStoreID}}, op.steps...)
+	return NewOperator(op.Desc(), op.Brief(), op.RegionID(), op.RegionEpoch(), op.Kind(), op.ApproximateSize, steps...), nil
+}
+
 // CreateForceTransferLeaderOperator creates an operator that transfers the leader from a source store to a target store forcible.
 func CreateForceTransferLeaderOperator(desc string, ci sche.SharedCluster, region *core.RegionInfo, targetStoreID uint64, kind OpKind) (*Operator, error) {
 	return NewBuilder(desc, ci, region, SkipOriginJointStateCheck, SkipPlacementRulesCheck).
@@ -443,6 +443,33 @@ func (suite *createOperatorTestSuite) TestCreateTransferLeaderOperator() {
 	}
 }
 
+func (suite *createOperatorTestSuite) TestCreateHealthCheckedTransferLeaderOperator() {
+	re := suite.Require()
+	peers := []*metapb.Peer{
+		{Id: 1, StoreId: 1, Role: metapb.PeerRole_Voter},
+		{Id: 2, StoreId: 2, Role: metapb.PeerRole_Voter},
+		{Id: 3, StoreId: 3, Role: metapb.PeerRole_Voter},
+	}
+	region := core.NewRegionInfo(&metapb.Region{Id: 1, Peers: peers}, peers[0])
+	op, err := CreateHealthCheckedTransferLeaderOperator("test", suite.cluster, region, 3, []uint64{2, 3}, OpLeader)
+	re.NoError(err)
+	re.Equal(2, op.Len())
+	re.Equal(WaitPeerHealthy{ToStore: 3}, op.Step(0))
+	// The multi-target set is kept.
+	re.Equal(TransferLeader{FromStore: 1, ToStore: 3, ToStores: []uint64{2, 3}}, op.Step(1))
+	re.Equal(OpLeader, op.Kind())
+
+	// The transfer waits for the target peer which becomes pending after the creation.
+	op.Start()
+	pending := region.Clone(core.WithPendingPeers([]*metapb.Peer{peers[2]}))
+	re.Equal(WaitPeerHealthy{ToStore: 3}, op.Check(pending))
+	re.IsType(TransferLeader{}, op.Check(region))
+
+	// The target store which rejects leader is still not allowed.
+	_, err = CreateHealthCheckedTransferLeaderOperator("test", suite.cluster, region, 10, nil, OpLeader)
+	re.Error(err)
+}
+
 func (suite *createOperatorTestSuite) TestCreateLeaveJointStateOperator() {
 	re := suite.Require()
 	type testCase struct {
@@ -61,6 +61,14 @@ var (
 			Help:      "Counter of schedule operators.",
 		}, []string{"type", "event"})
 
+	operatorSourceCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "operators_source_count",
+			Help:      "Counter of schedule operators with a source.",
+		}, []string{"type", "source", "event"})
+
 	operatorDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "pd",
@@ -85,6 +93,7 @@ func init() {
 	prometheus.MustRegister(OperatorLimitCounter)
 	prometheus.MustRegister(OperatorExceededStoreLimitCounter)
 	prometheus.MustRegister(operatorCounter)
+	prometheus.MustRegister(operatorSourceCounter)
 	prometheus.MustRegister(operatorDuration)
 	prometheus.MustRegister(operatorSizeHist)
 	prometheus.MustRegister(storeLimitCostCounter)
@@ -86,6 +86,11 @@ type Operator struct {
 	ApproximateSize  int64
 	timeout          time.Duration
 	influence        *OpInfluence
+	// source identifies who creates the operator, e.g. a drain campaign.
+	source string
+	// rateLimiter limits the operators toward the rateLimitedStore, see SetStoreRateLimiter.
+	rateLimiter      *StoreRateLimiter
+	rateLimitedStore uint64
 }
 
 // NewOperator creates a new operator.
@@ -116,6 +121,37 @@ func NewOperator(desc, brief string, regionID uint64, regionEpoch *metapb.Region
 	}
 }
 
+// SetSource sets the source of the operator, which is used to attribute the
+// operator in the metrics, e.g. to the drain campaign that creates it.
+func (o *Operator) SetSource(source string) {
+	o.source = source
+}
+
+// GetSource returns the source of the operator.
+func (o *Operator) GetSource() string {
+	return o.source
+}
+
+// SetStoreRateLimiter limits the operator by the rate of the operators toward the
+// target store. The controller rejects the operator if the limit is exceeded, and
+// takes the token once the operator is added.
+func (o *Operator) SetStoreRateLimiter(limiter *StoreRateLimiter, storeID uint64) {
+	o.rateLimiter = limiter
+	o.rateLimitedStore = storeID
+}
+
+// exceedStoreRateLimit returns whether the operator exceeds its store rate limit if any.
+func (o *Operator) exceedStoreRateLimit() bool {
+	return o.rateLimiter != nil && !o.rateLimiter.Available(o.rateLimitedStore)
+}
+
+// takeStoreRateLimit takes the token of the store rate limit if any.
+func (o *Operator) takeStoreRateLimit() {
+	if o.rateLimiter != nil {
+		o.rateLimiter.Take(o.rateLimitedStore)
+	}
+}
+
 // Sync some attribute with the given timeout.
 func (o *Operator) Sync(other *Operator) {
 	o.timeout = other.timeout
@@ -510,6 +546,45 @@ func (o *Operator) Record(finishTime time.Time) *OpRecord {
 	return record
 }
 
+// RegionOpRecord is used to show a finished operator of a region.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RegionOpRecord struct {
+	RegionID   uint64         `json:"region_id"`
+	Desc       string         `json:"desc"`
+	Brief      string         `json:"brief"`
+	Kind       string         `json:"kind"`
+	Status     string         `json:"status"`
+	Moves      []RegionOpMove `json:"moves"`
+	CreateTime time.Time      `json:"create_time"`
+	FinishTime time.Time      `json:"finish_time"`
+}
+
+// RegionOpMove is the source and target store of a leader or peer move of an operator.
+type RegionOpMove struct {
+	Kind string `json:"kind"`
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// NewRegionOpRecord creates a RegionOpRecord from the finished operator.
+func NewRegionOpRecord(op *OpWithStatus) *RegionOpRecord {
+	histories := op.History()
+	moves := make([]RegionOpMove, 0, len(histories))
+	for _, h := range histories {
+		moves = append(moves, RegionOpMove{Kind: h.Kind.String(), From: h.From, To: h.To})
+	}
+	return &RegionOpRecord{
+		RegionID:   op.RegionID(),
+		Desc:       op.Desc(),
+		Brief:      op.Brief(),
+		Kind:       op.Kind().String(),
+		Status:     op.Status.String(),
+		Moves:      moves,
+		CreateTime: op.GetCreateTime(),
+		FinishTime: op.FinishTime,
+	}
+}
+
 // IsLeaveJointStateOperator returns true if the desc is OpDescLeaveJointState.
 func (o *Operator) IsLeaveJointStateOperator() bool {
 	return strings.EqualFold(o.desc, OpDescLeaveJointState)
@@ -145,7 +145,7 @@ func (oc *Controller) Dispatch(region *core.RegionInfo, source string, recordOpS
 		step := op.Check(region)
 		switch op.Status() {
 		case STARTED:
-			operatorCounter.WithLabelValues(op.Desc(), "check").Inc()
+			incOperatorCounter(op, "check")
 			if source == DispatchFromHeartBeat && oc.checkStaleOperator(op, step, region) {
 				return
 			}
@@ -155,7 +155,7 @@ func (oc *Controller) Dispatch(region *core.RegionInfo, source string, recordOpS
 				recordOpStepWithTTL(op.RegionID())
 			}
 			if oc.RemoveOperator(op) {
-				operatorCounter.WithLabelValues(op.Desc(), "promote-success").Inc()
+				incOperatorCounter(op, "promote-success")
 				oc.PromoteWaitingOperator()
 			}
 			if time.Since(op.GetStartTime()) < FastOperatorFinishTime {
@@ -164,7 +164,7 @@ func (oc *Controller) Dispatch(region *core.RegionInfo, source string, recordOpS
 			}
 		case TIMEOUT:
 			if oc.RemoveOperator(op, Timeout) {
-				operatorCounter.WithLabelValues(op.Desc(), "promote-timeout").Inc()
+				incOperatorCounter(op, "promote-timeout")
 				oc.PromoteWaitingOperator()
 			}
 		default:
@@ -180,7 +180,7 @@ func (oc *Controller) Dispatch(region *core.RegionInfo, source string, recordOpS
 				})
 				_ = op.Cancel(NotInRunningState)
 				oc.buryOperator(op)
-				operatorCounter.WithLabelValues(op.Desc(), "promote-unexpected").Inc()
+				incOperatorCounter(op, "promote-unexpected")
 				oc.PromoteWaitingOperator()
 			}
 		}
@@ -192,7 +192,7 @@ func (oc *Controller) checkStaleOperator(op *Operator, step OpStep, region *core
 	if err != nil {
 		log.Info("operator is stale", zap.Uint64("region-id", op.RegionID()), errs.ZapError(err))
 		if oc.RemoveOperator(op, StaleStatus) {
-			operatorCounter.WithLabelValues(op.Desc(), "promote-stale").Inc()
+			incOperatorCounter(op, "promote-stale")
 			oc.PromoteWaitingOperator()
 			return true
 		}
@@ -213,7 +213,7 @@ func (oc *Controller) checkStaleOperator(op *Operator, step OpStep, region *core
 			op,
 			EpochNotMatch,
 		) {
-			operatorCounter.WithLabelValues(op.Desc(), "promote-stale").Inc()
+			incOperatorCounter(op, "promote-stale")
 			oc.PromoteWaitingOperator()
 			return true
 		}
@@ -254,7 +254,7 @@ func (oc *Controller) pollNeedDispatchRegion() (r *core.RegionInfo, next bool) {
 			log.Warn("remove operator because region disappeared",
 				zap.Uint64("region-id", op.RegionID()),
 				zap.Stringer("operator", op))
-			operatorCounter.WithLabelValues(op.Desc(), "disappear").Inc()
+			incOperatorCounter(op, "disappear")
 		}
 		oc.buryOperator(op)
 		return nil, true
@@ -335,7 +335,8 @@ func (oc *Controller) AddWaitingOperator(ops ...*Operator) int {
 		} else {
 			oc.wop.PutOperator(op)
 		}
-		operatorCounter.WithLabelValues(desc, "put").Inc()
+		incOperatorCounter(op, "put")
+		incOperatorSourceCounter(op, "promote-add")
 		oc.wopStatus.incCount(desc)
 		added++
 		needPromoted++
@@ -354,7 +355,7 @@ func (oc *Controller) AddOperator(ops ...*Operator) bool {
 	// but maybe user want to add operator when waiting queue is busy
 	if oc.ExceedStoreLimit(ops...) {
 		for _, op := range ops {
-			operatorCounter.WithLabelValues(op.Desc(), "exceed-limit").Inc()
+			incOperatorCounter(op, "exceed-limit")
 			_ = op.Cancel(ExceedStoreLimit)
 			oc.buryOperator(op)
 		}
@@ -385,10 +386,10 @@ func (oc *Controller) PromoteWaitingOperator() {
 		if ops == nil {
 			return
 		}
-		operatorCounter.WithLabelValues(ops[0].Desc(), "get").Inc()
+		incOperatorCounter(ops[0], "get")
 		if oc.ExceedStoreLimit(ops...) {
 			for _, op := range ops {
-				operatorCounter.WithLabelValues(op.Desc(), "exceed-limit").Inc()
+				incOperatorCounter(op, "exceed-limit")
 				_ = op.Cancel(ExceedStoreLimit)
 				oc.buryOperator(op)
 			}
@@ -398,7 +399,7 @@ func (oc *Controller) PromoteWaitingOperator() {
 
 		if pass, reason := oc.checkAddOperator(true, ops...); !pass {
 			for _, op := range ops {
-				operatorCounter.WithLabelValues(op.Desc(), "check-failed").Inc()
+				incOperatorCounter(op, "check-failed")
 				_ = op.Cancel(reason)
 				oc.buryOperator(op)
 			}
@@ -429,7 +430,7 @@ func (oc *Controller) checkAddOperator(isPromoting bool, ops ...*Operator) (bool
 		if region == nil {
 			log.Debug("region not found, cancel add operator",
 				zap.Uint64("region-id", op.RegionID()))
-			operatorCounter.WithLabelValues(op.Desc(), "not-found").Inc()
+			incOperatorCounter(op, "not-found")
 			return false, RegionNotFound
 		}
 		if region.GetRegionEpoch().GetVersion() != op.RegionEpoch().GetVersion() ||
@@ -438,7 +439,7 @@ func (oc *Controller) checkAddOperator(isPromoting bool, ops ...*Operator) (bool
 				zap.Uint64("region-id", op.RegionID()),
 				zap.Reflect("old", region.GetRegionEpoch()),
 				zap.Reflect("new", op.RegionEpoch()))
-			operatorCounter.WithLabelValues(op.Desc(), "epoch-not-match").Inc()
+			incOperatorCounter(op, "epoch-not-match")
 			return false, EpochNotMatch
 		}
 		if oldi, ok := oc.operators.Load(op.RegionID()); ok && oldi.(*Operator) != nil && !isHigherPriorityOperator(op, oldi.(*Operator)) {
@@ -446,7 +447,7 @@ func (oc *Controller) checkAddOperator(isPromoting bool, ops ...*Operator) (bool
 			log.Debug("already have operator, cancel add operator",
 				zap.Uint64("region-id", op.RegionID()),
 				zap.Reflect("old", old))
-			operatorCounter.WithLabelValues(op.Desc(), "already-have").Inc()
+			incOperatorCounter(op, "already-have")
 			return false, AlreadyExist
 		}
 		if op.Status() != CREATED {
@@ -457,12 +458,12 @@ func (oc *Controller) checkAddOperator(isPromoting bool, ops ...*Operator) (bool
 			failpoint.Inject("unexpectedOperator", func() {
 				panic(op)
 			})
-			operatorCounter.WithLabelValues(op.Desc(), "unexpected-status").Inc()
+			incOperatorCounter(op, "unexpected-status")
 			return false, NotInCreateStatus
 		}
 		if !isPromoting && oc.wopStatus.getCount(op.Desc()) >= oc.config.GetSchedulerMaxWaitingOperator() {
 			log.Debug("exceed max return false", zap.Uint64("waiting", oc.wopStatus.ops[op.Desc()]), zap.String("desc", op.Desc()), zap.Uint64("max", oc.config.GetSchedulerMaxWaitingOperator()))
-			operatorCounter.WithLabelValues(op.Desc(), "exceed-max-waiting").Inc()
+			incOperatorCounter(op, "exceed-max-waiting")
 			return false, ExceedWaitLimit
 		}
 
@@ -474,7 +475,7 @@ func (oc *Controller) checkAddOperator(isPromoting bool, ops ...*Operator) (bool
 	for _, op := range ops {
 		if op.CheckExpired() {
 			reason = Expired
-			operatorCounter.WithLabelValues(op.Desc(), "expired").Inc()
+			incOperatorCounter(op, "expired")
 		}
 	}
 	return reason != Expired, reason
@@ -486,7 +487,7 @@ func (oc *Controller) checkAddOperator(isPromoting bool, ops ...*Operator) (bool
 func (oc *Controller) checkOperatorLightly(op *Operator) (*core.RegionInfo, CancelReasonType) {
 	region := oc.cluster.GetRegion(op.RegionID())
 	if region == nil {
-		operatorCounter.WithLabelValues(op.Desc(), "not-found").Inc()
+		incOperatorCounter(op, "not-found")
 		return nil, RegionNotFound
 	}
 
@@ -495,7 +496,7 @@ func (oc *Controller) checkOperatorLightly(op *Operator) (*core.RegionInfo, Canc
 	// If the version of epoch is changed, the region has been splitted or merged, and the key range has been changed.
 	// The changing for conf_version of epoch doesn't modify the region key range, skip it.
 	if (op.Kind()&OpMerge != 0) && region.GetRegionEpoch().GetVersion() > op.RegionEpoch().GetVersion() {
-		operatorCounter.WithLabelValues(op.Desc(), "epoch-not-match").Inc()
+		incOperatorCounter(op, "epoch-not-match")
 		return nil, EpochNotMatch
 	}
 	return region, ""
@@ -529,12 +530,13 @@ func (oc *Controller) addOperatorInner(op *Operator) bool {
 		failpoint.Inject("unexpectedOperator", func() {
 			panic(op)
 		})
-		operatorCounter.WithLabelValues(op.Desc(), "unexpected").Inc()
+		incOperatorCounter(op, "unexpected")
 		return false
 	}
 	oc.operators.Store(regionID, op)
 	oc.counts.inc(op.SchedulerKind())
-	operatorCounter.WithLabelValues(op.Desc(), "start").Inc()
+	op.takeStoreRateLimit()
+	incOperatorCounter(op, "start")
 	operatorSizeHist.WithLabelValues(op.Desc()).Observe(float64(op.ApproximateSize))
 	opInfluence := NewTotalOpInfluence([]*Operator{op}, oc.cluster)
 	for storeID := range opInfluence.StoresInfluence {
@@ -562,13 +564,27 @@ func (oc *Controller) addOperatorInner(op *Operator) bool {
 	}
 
 	oc.opNotifierQueue.Push(&operatorWithTime{op: op, time: getNextPushOperatorTime(step, time.Now())})
-	operatorCounter.WithLabelValues(op.Desc(), "create").Inc()
+	incOperatorCounter(op, "create")
 	for _, counter := range op.Counters {
 		counter.Inc()
 	}
 	return true
 }
 
+// incOperatorCounter counts the event of the operator, and also by its source
+// if any, so every event can be broken down by the source.
+func incOperatorCounter(op *Operator, event string) {
+	operatorCounter.WithLabelValues(op.Desc(), event).Inc()
+	incOperatorSourceCounter(op, event)
+}
+
+// incOperatorSourceCounter counts the event of the operator by its source if any.
+func incOperatorSourceCounter(op *Operator, event string) {
+	if source := op.GetSource(); source != "" {
+		operatorSourceCounter.WithLabelValues(op.Desc(), source, event).Inc()
+	}
+}
+
 func (oc *Controller) ack(op *Operator) {
 	opInfluence := NewTotalOpInfluence([]*Operator{op}, oc.cluster)
 	for storeID := range opInfluence.StoresInfluence {
@@ -607,7 +623,7 @@ func (oc *Controller) removeOperatorsInner() []*Operator {
 		op := value.(*Operator)
 		oc.operators.Delete(regionID)
 		oc.counts.dec(op.SchedulerKind())
-		operatorCounter.WithLabelValues(op.Desc(), "remove").Inc()
+		incOperatorCounter(op, "remove")
 		oc.ack(op)
 		if op.Kind()&OpMerge != 0 {
 			oc.removeRelatedMergeOperator(op)
@@ -646,7 +662,7 @@ func (oc *Controller) removeOperatorInner(op *Operator) bool {
 	if cur, ok := oc.operators.Load(regionID); ok && cur.(*Operator) == op {
 		oc.operators.Delete(regionID)
 		oc.counts.dec(op.SchedulerKind())
-		operatorCounter.WithLabelValues(op.Desc(), "remove").Inc()
+		incOperatorCounter(op, "remove")
 		oc.ack(op)
 		if op.Kind()&OpMerge != 0 {
 			oc.removeRelatedMergeOperator(op)
@@ -685,7 +701,7 @@ func (oc *Controller) buryOperator(op *Operator) {
 		failpoint.Inject("unexpectedOperator", func() {
 			panic(op)
 		})
-		operatorCounter.WithLabelValues(op.Desc(), "unexpected").Inc()
+		incOperatorCounter(op, "unexpected")
 		_ = op.Cancel(Unknown)
 	}
 
@@ -696,7 +712,7 @@ func (oc *Controller) buryOperator(op *Operator) {
 			zap.Duration("takes", op.RunningTime()),
 			zap.Reflect("operator", op),
 			zap.String("additional-info", op.LogAdditionalInfo()))
-		operatorCounter.WithLabelValues(op.Desc(), "finish").Inc()
+		incOperatorCounter(op, "finish")
 		operatorDuration.WithLabelValues(op.Desc()).Observe(op.RunningTime().Seconds())
 		for _, counter := range op.FinishedCounters {
 			counter.Inc()
@@ -707,20 +723,20 @@ func (oc *Controller) buryOperator(op *Operator) {
 			zap.Duration("takes", op.RunningTime()),
 			zap.Reflect("operator", op),
 			zap.String("additional-info", op.LogAdditionalInfo()))
-		operatorCounter.WithLabelValues(op.Desc(), "replace").Inc()
+		incOperatorCounter(op, "replace")
 	case EXPIRED:
 		log.Info("operator expired",
 			zap.Uint64("region-id", op.RegionID()),
 			zap.Duration("lives", op.ElapsedTime()),
 			zap.Reflect("operator", op))
-		operatorCounter.WithLabelValues(op.Desc(), "expire").Inc()
+		incOperatorCounter(op, "expire")
 	case TIMEOUT:
 		log.Info("operator timeout",
 			zap.Uint64("region-id", op.RegionID()),
 			zap.Duration("takes", op.RunningTime()),
 			zap.Reflect("operator", op),
 			zap.String("additional-info", op.LogAdditionalInfo()))
-		operatorCounter.WithLabelValues(op.Desc(), "timeout").Inc()
+		incOperatorCounter(op, "timeout")
 	case CANCELED:
 		log.Info("operator canceled",
 			zap.Uint64("region-id", op.RegionID()),
@@ -728,7 +744,7 @@ func (oc *Controller) buryOperator(op *Operator) {
 			zap.Reflect("operator", op),
 			zap.String("additional-info", op.LogAdditionalInfo()),
 		)
-		operatorCounter.WithLabelValues(op.Desc(), "cancel").Inc()
+		incOperatorCounter(op, "cancel")
 	}
 
 	oc.records.Put(op)
@@ -826,6 +842,17 @@ func (oc *Controller) GetHistory(start time.Time) []OpHistory {
 	return history
 }
 
+// GetRegionRecords gets the recent finished operators of the region, it
+// returns an empty slice if there is no such operator.
+func (oc *Controller) GetRegionRecords(regionID uint64) []*RegionOpRecord {
+	history := oc.records.GetHistory(regionID)
+	records := make([]*RegionOpRecord, 0, len(history))
+	for _, op := range history {
+		records = append(records, NewRegionOpRecord(op))
+	}
+	return records
+}
+
 // OperatorCount gets the count of operators filtered by kind.
 // kind only has one OpKind.
 func (oc *Controller) OperatorCount(kind OpKind) uint64 {
@@ -870,6 +897,7 @@ func (oc *Controller) GetFastOpInfluence(cluster *core.BasicCluster, influence O
 // It is used in tests only.
 func (oc *Controller) CleanAllOpRecords() {
 	oc.records.ttl.Clear()
+	oc.records.history.Clear()
 }
 
 // AddOpInfluence add operator influence for cluster
@@ -919,14 +947,22 @@ func (o *OpWithStatus) MarshalJSON() ([]byte, error) {
 // records remains the operator and its status for a while.
 type records struct {
 	ttl *cache.TTLUint64
+	// history remains the recent operators of each region, which is bounded by
+	// maxRegionOpHistory. mu is used to protect the read-modify-write of it.
+	mu      syncutil.Mutex
+	history *cache.TTLUint64
 }
 
-const operatorStatusRemainTime = 10 * time.Minute
+const (
+	operatorStatusRemainTime = 10 * time.Minute
+	maxRegionOpHistory       = 16
+)
 
 // newRecords returns a records.
 func newRecords(ctx context.Context) *records {
 	return &records{
-		ttl: cache.NewIDTTL(ctx, time.Minute, operatorStatusRemainTime),
+		ttl:     cache.NewIDTTL(ctx, time.Minute, operatorStatusRemainTime),
+		history: cache.NewIDTTL(ctx, time.Minute, operatorStatusRemainTime),
 	}
 }
 
@@ -944,10 +980,40 @@ func (o *records) Put(op *Operator) {
 	id := op.RegionID()
 	record := NewOpWithStatus(op)
 	o.ttl.Put(id, record)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var history []*OpWithStatus
+	if v, exist := o.history.Get(id); exist {
+		history = v.([]*OpWithStatus)
+	}
+	if len(history) >= maxRegionOpHistory {
+		history = history[len(history)-maxRegionOpHistory+1:]
+	}
+	// Always allocate a new slice since the old one may be being read.
+	newHistory := make([]*OpWithStatus, 0, len(history)+1)
+	newHistory = append(newHistory, history...)
+	o.history.Put(id, append(newHistory, record))
+}
+
+// GetHistory gets the recent operators of the region in the finishing order.
+func (o *records) GetHistory(regionID uint64) []*OpWithStatus {
+	v, exist := o.history.Get(regionID)
+	if !exist {
+		return nil
+	}
+	return v.([]*OpWithStatus)
 }
 
 // ExceedStoreLimit returns true if the store exceeds the cost limit after adding the  Otherwise, returns false.
 func (oc *Controller) ExceedStoreLimit(ops ...*Operator) bool {
+	// The store rate limit is set by the scheduler explicitly, so it's checked regardless of the priority.
+	for _, op := range ops {
+		if op.exceedStoreRateLimit() {
+			OperatorExceededStoreLimitCounter.WithLabelValues(op.Desc()).Inc()
+			return true
+		}
+	}
 	// The operator with Urgent priority, like admin operators, should ignore the store limit check.
 	var desc string
 	if len(ops) != 0 {
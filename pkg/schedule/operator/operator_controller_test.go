@@ -25,9 +25,11 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/core/constant"
 	"github.com/tikv/pd/pkg/core/storelimit"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/pkg/mock/mockconfig"
@@ -955,3 +957,116 @@ func (suite *operatorControllerTestSuite) TestInvalidStoreId() {
 	// Although store 3 does not exist in PD, PD can also send op to TiKV.
 	re.Equal(pdpb.OperatorStatus_RUNNING, oc.GetOperatorStatus(1).Status)
 }
+
+func (suite *operatorControllerTestSuite) TestStoreRateLimit() {
+	re := suite.Require()
+	opt := mockconfig.NewTestOptions()
+	tc := mockcluster.NewCluster(suite.ctx, opt)
+	stream := hbstream.NewTestHeartbeatStreams(suite.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewController(suite.ctx, tc.GetBasicCluster(), tc.GetSharedConfig(), stream)
+	tc.AddLeaderStore(1, 2)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	tc.AddLeaderRegion(2, 1, 2)
+
+	// The burst is 1 and it takes 100s to get a new token.
+	limiter := NewStoreRateLimiter(0.01)
+	newOp := func(regionID uint64, epoch *metapb.RegionEpoch) *Operator {
+		op := NewTestOperator(regionID, epoch, OpLeader, TransferLeader{FromStore: 1, ToStore: 2})
+		// The store rate limit is checked even for the urgent operators.
+		op.SetPriorityLevel(constant.Urgent)
+		op.SetStoreRateLimiter(limiter, 2)
+		return op
+	}
+	// The rejected operator doesn't take the token.
+	re.False(oc.AddOperator(newOp(1, &metapb.RegionEpoch{Version: 100})))
+	re.True(limiter.Available(2))
+	re.True(oc.AddOperator(newOp(1, tc.GetRegion(1).GetRegionEpoch())))
+	re.False(limiter.Available(2))
+	op := newOp(2, tc.GetRegion(2).GetRegionEpoch())
+	re.False(oc.AddOperator(op))
+	re.Equal(CANCELED, op.Status())
+	re.Equal(string(ExceedStoreLimit), op.GetAdditionalInfo(cancelReason))
+}
+
+func (suite *operatorControllerTestSuite) TestOperatorSourceCounter() {
+	re := suite.Require()
+	opt := mockconfig.NewTestOptions()
+	tc := mockcluster.NewCluster(suite.ctx, opt)
+	stream := hbstream.NewTestHeartbeatStreams(suite.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewController(suite.ctx, tc.GetBasicCluster(), tc.GetSharedConfig(), stream)
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+
+	op := NewTestOperator(1, tc.GetRegion(1).GetRegionEpoch(), OpLeader, TransferLeader{FromStore: 1, ToStore: 2})
+	op.SetSource("drain-campaign-1")
+	re.Equal("drain-campaign-1", op.GetSource())
+	re.True(oc.AddOperator(op))
+	re.Equal(1.0, testutil.ToFloat64(operatorSourceCounter.WithLabelValues(op.Desc(), "drain-campaign-1", "create")))
+
+	ApplyOperator(tc, op)
+	oc.Dispatch(tc.GetRegion(1), DispatchFromHeartBeat, nil)
+	re.Equal(SUCCESS, op.Status())
+	re.Equal(1.0, testutil.ToFloat64(operatorSourceCounter.WithLabelValues(op.Desc(), "drain-campaign-1", "finish")))
+
+	// The operators without a source are not counted.
+	op = NewTestOperator(1, tc.GetRegion(1).GetRegionEpoch(), OpLeader, TransferLeader{FromStore: 2, ToStore: 1})
+	re.True(oc.AddOperator(op))
+	re.Equal(1.0, testutil.ToFloat64(operatorSourceCounter.WithLabelValues(op.Desc(), "drain-campaign-1", "create")))
+	re.True(oc.RemoveOperator(op))
+
+	// Every event of the operator is counted by its source, not only the creation and the finish.
+	op = NewTestOperator(1, tc.GetRegion(1).GetRegionEpoch(), OpLeader, TransferLeader{FromStore: 2, ToStore: 1})
+	op.SetSource("drain-campaign-2")
+	re.True(oc.AddOperator(op))
+	re.True(oc.RemoveOperator(op))
+	re.Equal(CANCELED, op.Status())
+	for _, event := range []string{"create", "start", "remove", "cancel"} {
+		re.Equal(1.0, testutil.ToFloat64(operatorSourceCounter.WithLabelValues(op.Desc(), "drain-campaign-2", event)), event)
+	}
+	re.Zero(testutil.ToFloat64(operatorSourceCounter.WithLabelValues(op.Desc(), "drain-campaign-2", "finish")))
+}
+
+func (suite *operatorControllerTestSuite) TestGetRegionRecords() {
+	re := suite.Require()
+	opt := mockconfig.NewTestOptions()
+	tc := mockcluster.NewCluster(suite.ctx, opt)
+	stream := hbstream.NewTestHeartbeatStreams(suite.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewController(suite.ctx, tc.GetBasicCluster(), tc.GetSharedConfig(), stream)
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	re.Empty(oc.GetRegionRecords(1))
+	re.NotNil(oc.GetRegionRecords(1))
+
+	// transfer the leader from store 1 to store 2.
+	op := NewTestOperator(1, &metapb.RegionEpoch{}, OpLeader, TransferLeader{FromStore: 1, ToStore: 2})
+	re.True(op.Start())
+	oc.SetOperator(op)
+	ApplyOperator(tc, op)
+	oc.Dispatch(tc.GetRegion(1), "test", nil)
+	re.Equal(pdpb.OperatorStatus_SUCCESS, oc.GetOperatorStatus(1).Status)
+	records := oc.GetRegionRecords(1)
+	re.Len(records, 1)
+	re.Equal(uint64(1), records[0].RegionID)
+	re.Equal(OpLeader.String(), records[0].Kind)
+	re.Equal(pdpb.OperatorStatus_SUCCESS.String(), records[0].Status)
+	re.Equal([]RegionOpMove{{Kind: constant.LeaderKind.String(), From: 1, To: 2}}, records[0].Moves)
+	re.Empty(oc.GetRegionRecords(2))
+
+	// the history is bounded.
+	for i := 0; i < maxRegionOpHistory; i++ {
+		op := NewTestOperator(1, &metapb.RegionEpoch{}, OpLeader, TransferLeader{FromStore: 2, ToStore: 1})
+		re.True(op.Start())
+		oc.SetOperator(op)
+		checkRemoveOperatorSuccess(re, oc, op)
+	}
+	records = oc.GetRegionRecords(1)
+	re.Len(records, maxRegionOpHistory)
+	for _, record := range records {
+		re.Equal(pdpb.OperatorStatus_CANCEL.String(), record.Status)
+	}
+	oc.CleanAllOpRecords()
+	re.Empty(oc.GetRegionRecords(1))
+}
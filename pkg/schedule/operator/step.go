@@ -140,6 +140,54 @@ func (tl TransferLeader) GetCmd(region *core.RegionInfo, _ bool) *hbstream.Opera
 	}
 }
 
+// WaitPeerHealthy is an OpStep that waits for the peer on the store to be neither
+// pending nor down. The builder already rejects such a leader target, so put before
+// TransferLeader, it re-checks the target in case the peer becomes unhealthy after
+// the operator is created. It doesn't measure the log lag of the peer, PD doesn't
+// know the applied index of each peer.
+type WaitPeerHealthy struct {
+	ToStore uint64
+}
+
+// ConfVerChanged returns the delta value for version increased by this step.
+func (WaitPeerHealthy) ConfVerChanged(_ *core.RegionInfo) uint64 {
+	return 0 // waiting never changes the conf version
+}
+
+func (wc WaitPeerHealthy) String() string {
+	return fmt.Sprintf("wait peer on store %v to be healthy", wc.ToStore)
+}
+
+// IsFinish checks if current step is finished.
+func (wc WaitPeerHealthy) IsFinish(region *core.RegionInfo) bool {
+	peer := region.GetStorePeer(wc.ToStore)
+	if peer == nil {
+		return false
+	}
+	return region.GetPendingPeer(peer.GetId()) == nil && region.GetDownPeer(peer.GetId()) == nil
+}
+
+// CheckInProgress checks if the step is in the progress of advancing.
+func (wc WaitPeerHealthy) CheckInProgress(ci *core.BasicCluster, config config.SharedConfigProvider, region *core.RegionInfo) error {
+	if region.GetStorePeer(wc.ToStore) == nil {
+		return errors.New("peer does not existed")
+	}
+	return validateStore(ci, config, wc.ToStore)
+}
+
+// Influence calculates the store difference that current step makes.
+func (WaitPeerHealthy) Influence(_ OpInfluence, _ *core.RegionInfo) {}
+
+// Timeout returns duration that current step may take.
+func (WaitPeerHealthy) Timeout(regionSize int64) time.Duration {
+	return fastStepWaitDuration(regionSize)
+}
+
+// GetCmd returns the schedule command for heartbeat response.
+func (WaitPeerHealthy) GetCmd(_ *core.RegionInfo, _ bool) *hbstream.Operation {
+	return nil
+}
+
 // AddPeer is an OpStep that adds a region peer.
 type AddPeer struct {
 	ToStore, PeerID uint64
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/tikv/pd/pkg/core"
@@ -106,6 +107,38 @@ func (suite *operatorStepTestSuite) TestTransferLeader() {
 	suite.check(re, step, "transfer leader from store 1 to store 9", testCases)
 }
 
+func (suite *operatorStepTestSuite) TestWaitPeerHealthy() {
+	re := suite.Require()
+	step := WaitPeerHealthy{ToStore: 2}
+	re.Equal("wait peer on store 2 to be healthy", step.String())
+	peers := []*metapb.Peer{
+		{Id: 1, StoreId: 1, Role: metapb.PeerRole_Voter},
+		{Id: 2, StoreId: 2, Role: metapb.PeerRole_Voter},
+		{Id: 3, StoreId: 3, Role: metapb.PeerRole_Voter},
+	}
+	region := core.NewRegionInfo(&metapb.Region{Id: 1, Peers: peers}, peers[0])
+	re.True(step.IsFinish(region))
+	re.NoError(step.CheckInProgress(suite.cluster.GetBasicCluster(), suite.cluster.GetSharedConfig(), region))
+	re.Nil(step.GetCmd(region, true))
+
+	// The pending follower delays the step.
+	pending := region.Clone(core.WithPendingPeers([]*metapb.Peer{peers[1]}))
+	re.False(step.IsFinish(pending))
+	re.NoError(step.CheckInProgress(suite.cluster.GetBasicCluster(), suite.cluster.GetSharedConfig(), pending))
+	down := region.Clone(core.WithDownPeers([]*pdpb.PeerStats{{Peer: peers[1], DownSeconds: 10}}))
+	re.False(step.IsFinish(down))
+	// The step finishes once the follower is healthy again.
+	re.True(step.IsFinish(pending.Clone(core.WithPendingPeers(nil))))
+
+	// The step can't advance if the peer doesn't exist or the store is down.
+	step = WaitPeerHealthy{ToStore: 4}
+	re.False(step.IsFinish(region))
+	re.Error(step.CheckInProgress(suite.cluster.GetBasicCluster(), suite.cluster.GetSharedConfig(), region))
+	peers[2].StoreId = 9
+	step = WaitPeerHealthy{ToStore: 9}
+	re.Error(step.CheckInProgress(suite.cluster.GetBasicCluster(), suite.cluster.GetSharedConfig(), region))
+}
+
 func (suite *operatorStepTestSuite) TestAddPeer() {
 	re := suite.Require()
 	step := AddPeer{ToStore: 2, PeerID: 2}
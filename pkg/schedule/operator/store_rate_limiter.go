@@ -0,0 +1,110 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"math"
+	"time"
+
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"golang.org/x/time/rate"
+)
+
+// StoreRateLimiter limits the rate of the operators generated toward each target
+// store, so that no single store is overwhelmed, e.g. by the leader transfers
+// during a drain. Schedulers should check Available after creating an operator and
+// attach the limiter to it by SetStoreRateLimiter, then the token is taken when the
+// operator is added to the controller.
+type StoreRateLimiter struct {
+	mu syncutil.Mutex
+	// ratePerSec is the max number of operators per second toward a store,
+	// a non-positive value means no limit.
+	ratePerSec float64
+	limiters   map[uint64]*rate.Limiter
+}
+
+// NewStoreRateLimiter creates a StoreRateLimiter with the given operators per second.
+func NewStoreRateLimiter(ratePerSec float64) *StoreRateLimiter {
+	return &StoreRateLimiter{
+		ratePerSec: ratePerSec,
+		limiters:   make(map[uint64]*rate.Limiter),
+	}
+}
+
+// Available returns whether an operator toward the store can be added now,
+// it doesn't take the token.
+func (l *StoreRateLimiter) Available(storeID uint64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.ratePerSec <= 0 {
+		return true
+	}
+	limiter, ok := l.limiters[storeID]
+	return !ok || limiter.Tokens() >= 1
+}
+
+// Take takes the token of an operator toward the store. The limiters which are
+// refilled are dropped since they are the same as the new ones, so the limiters
+// of the idle or deleted stores are not kept.
+func (l *StoreRateLimiter) Take(storeID uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.ratePerSec <= 0 {
+		return
+	}
+	now := time.Now()
+	for id, limiter := range l.limiters {
+		if id != storeID && limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(l.limiters, id)
+		}
+	}
+	limiter, ok := l.limiters[storeID]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(l.ratePerSec), burstOf(l.ratePerSec))
+		l.limiters[storeID] = limiter
+	}
+	// The operator is added anyway, so the token is owed if it's not available.
+	limiter.ReserveN(now, 1)
+}
+
+// SetRate updates the operators per second toward each store.
+func (l *StoreRateLimiter) SetRate(ratePerSec float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ratePerSec = ratePerSec
+	for _, limiter := range l.limiters {
+		limiter.SetLimit(rate.Limit(ratePerSec))
+		limiter.SetBurst(burstOf(ratePerSec))
+	}
+}
+
+// GetRate returns the operators per second toward each store.
+func (l *StoreRateLimiter) GetRate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ratePerSec
+}
+
+// limiterCount returns the number of the stores with a limiter.
+func (l *StoreRateLimiter) limiterCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.limiters)
+}
+
+// burstOf allows at most one second of operators at once.
+func burstOf(ratePerSec float64) int {
+	return int(math.Max(1, math.Ceil(ratePerSec)))
+}
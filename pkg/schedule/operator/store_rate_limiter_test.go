@@ -0,0 +1,69 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreRateLimiter(t *testing.T) {
+	re := require.New(t)
+	limiter := NewStoreRateLimiter(10)
+	countTaken := func(storeID uint64, n int) int {
+		taken := 0
+		for i := 0; i < n; i++ {
+			if limiter.Available(storeID) {
+				limiter.Take(storeID)
+				taken++
+			}
+		}
+		return taken
+	}
+	// Checking the limit doesn't take the token.
+	for i := 0; i < 100; i++ {
+		re.True(limiter.Available(1))
+	}
+	// Only one second of operators can be generated toward a store at once.
+	re.Equal(10, countTaken(1, 100))
+	re.Zero(countTaken(1, 100))
+	// The other stores are not affected.
+	re.Equal(10, countTaken(2, 100))
+	// The tokens are refilled with the rate.
+	time.Sleep(200 * time.Millisecond)
+	re.InDelta(2, countTaken(1, 100), 1)
+
+	limiter.SetRate(0)
+	re.Zero(limiter.GetRate())
+	re.Equal(100, countTaken(1, 100))
+	limiter.SetRate(0.5)
+	re.Equal(1, countTaken(3, 100))
+}
+
+func TestStoreRateLimiterDropRefilled(t *testing.T) {
+	re := require.New(t)
+	limiter := NewStoreRateLimiter(100)
+	limiter.Take(1)
+	limiter.Take(2)
+	re.Equal(2, limiter.limiterCount())
+	// The limiters of the stores without operators, e.g. the deleted ones, are
+	// dropped once they are refilled.
+	time.Sleep(50 * time.Millisecond)
+	limiter.Take(3)
+	re.Equal(1, limiter.limiterCount())
+	re.True(limiter.Available(1))
+}
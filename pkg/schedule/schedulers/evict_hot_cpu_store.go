@@ -0,0 +1,337 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/errs"
+	sche "github.com/tikv/pd/pkg/schedule/core"
+	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/schedule/plan"
+	"github.com/tikv/pd/pkg/statistics/utils"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/utils/apiutil"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"github.com/unrolled/render"
+	"go.uber.org/zap"
+)
+
+const (
+	// EvictHotCPUStoreName is evict hot cpu store scheduler name.
+	EvictHotCPUStoreName = "evict-hot-cpu-store-scheduler"
+	// EvictHotCPUStoreType is evict hot cpu store scheduler type.
+	EvictHotCPUStoreType = "evict-hot-cpu-store"
+
+	// defaultHotCPUThreshold means 8 cores are fully used.
+	defaultHotCPUThreshold = 800.0
+	// defaultHotCPUTargetMargin means 2 cores.
+	defaultHotCPUTargetMargin = 200.0
+)
+
+// WithLabelValues is a heavy operation, define variable to avoid call it every time.
+var evictHotCPUStoreCounter = schedulerCounter.WithLabelValues(EvictHotCPUStoreName, "schedule")
+
+type evictHotCPUStoreSchedulerConfig struct {
+	syncutil.RWMutex
+	storage endpoint.ConfigStorage
+	// CPUThreshold is the CPU usage above which the leaders are evicted from the store.
+	// It's the sum of the CPU usages of all the threads in percentage, the same as the
+	// CPU usage reported by the store heartbeat.
+	CPUThreshold float64 `json:"cpu-threshold"`
+	// TargetCPUMargin is how far below the threshold the CPU usage of a target store
+	// must be. The evicted store stays paused for leader transfer until its CPU usage
+	// drops below the same line, so the stores near the threshold don't transfer the
+	// leaders back and forth.
+	TargetCPUMargin float64 `json:"target-cpu-margin"`
+}
+
+func initEvictHotCPUStoreSchedulerConfig(storage endpoint.ConfigStorage) *evictHotCPUStoreSchedulerConfig {
+	return &evictHotCPUStoreSchedulerConfig{
+		storage:         storage,
+		CPUThreshold:    defaultHotCPUThreshold,
+		TargetCPUMargin: defaultHotCPUTargetMargin,
+	}
+}
+
+func (conf *evictHotCPUStoreSchedulerConfig) Clone() *evictHotCPUStoreSchedulerConfig {
+	conf.RLock()
+	defer conf.RUnlock()
+	return &evictHotCPUStoreSchedulerConfig{
+		CPUThreshold:    conf.CPUThreshold,
+		TargetCPUMargin: conf.TargetCPUMargin,
+	}
+}
+
+func (conf *evictHotCPUStoreSchedulerConfig) persistLocked() error {
+	data, err := EncodeConfig(conf)
+	if err != nil {
+		return err
+	}
+	return conf.storage.SaveSchedulerConfig(EvictHotCPUStoreName, data)
+}
+
+func (conf *evictHotCPUStoreSchedulerConfig) getCPUThreshold() float64 {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.CPUThreshold
+}
+
+// getThresholds returns the CPU threshold of the hot stores and the one of the targets.
+func (conf *evictHotCPUStoreSchedulerConfig) getThresholds() (threshold, targetThreshold float64) {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.CPUThreshold, conf.CPUThreshold - conf.TargetCPUMargin
+}
+
+// hotCPUStores is the stores to evict the leaders from in one scheduling, it picks
+// the least CPU loaded store under the target threshold as the target.
+type hotCPUStores struct {
+	threshold       float64
+	targetThreshold float64
+	storeIDs        []uint64
+	cpuUsages       map[uint64]float64
+}
+
+func newHotCPUStores(cluster sche.SchedulerCluster, threshold, targetThreshold float64) *hotCPUStores {
+	s := &hotCPUStores{
+		threshold:       threshold,
+		targetThreshold: targetThreshold,
+		cpuUsages:       make(map[uint64]float64),
+	}
+	storesLoads := cluster.GetStoresLoads()
+	for _, store := range cluster.GetStores() {
+		if store.IsRemoved() {
+			continue
+		}
+		loads := storesLoads[store.GetID()]
+		if len(loads) <= int(utils.StoreCPUUsage) {
+			continue
+		}
+		cpuUsage := loads[utils.StoreCPUUsage]
+		s.cpuUsages[store.GetID()] = cpuUsage
+		if cpuUsage > threshold && (store.IsPreparing() || store.IsServing()) {
+			s.storeIDs = append(s.storeIDs, store.GetID())
+		}
+	}
+	// Evict the leaders from the hottest store first.
+	sort.Slice(s.storeIDs, func(i, j int) bool {
+		return s.cpuUsages[s.storeIDs[i]] > s.cpuUsages[s.storeIDs[j]]
+	})
+	return s
+}
+
+func (s *hotCPUStores) getStores() []uint64 {
+	return s.storeIDs
+}
+
+func (*hotCPUStores) getKeyRangesByID(uint64) []core.KeyRange {
+	return []core.KeyRange{core.NewKeyRange("", "")}
+}
+
+func (s *hotCPUStores) pickTarget(candidates []*core.StoreInfo) *core.StoreInfo {
+	var target *core.StoreInfo
+	for _, store := range candidates {
+		cpuUsage, ok := s.cpuUsages[store.GetID()]
+		// The store without the CPU usage or with the high CPU usage can't share the load.
+		if !ok || cpuUsage >= s.targetThreshold {
+			continue
+		}
+		if target == nil || cpuUsage < s.cpuUsages[target.GetID()] {
+			target = store
+		}
+	}
+	return target
+}
+
+type evictHotCPUStoreHandler struct {
+	rd     *render.Render
+	config *evictHotCPUStoreSchedulerConfig
+}
+
+func newEvictHotCPUStoreHandler(config *evictHotCPUStoreSchedulerConfig) http.Handler {
+	h := &evictHotCPUStoreHandler{
+		config: config,
+		rd:     render.New(render.Options{IndentJSON: true}),
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/config", h.UpdateConfig).Methods(http.MethodPost)
+	router.HandleFunc("/list", h.ListConfig).Methods(http.MethodGet)
+	return router
+}
+
+func (handler *evictHotCPUStoreHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
+	var input map[string]any
+	if err := apiutil.ReadJSONRespondError(handler.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	handler.config.Lock()
+	defer handler.config.Unlock()
+	cpuThreshold, targetCPUMargin := handler.config.CPUThreshold, handler.config.TargetCPUMargin
+	v, hasThreshold := input["cpu-threshold"]
+	if hasThreshold {
+		var ok bool
+		if cpuThreshold, ok = v.(float64); !ok || cpuThreshold <= 0 {
+			handler.rd.JSON(w, http.StatusBadRequest, errors.New("invalid argument for 'cpu-threshold'").Error())
+			return
+		}
+	}
+	v, hasMargin := input["target-cpu-margin"]
+	if hasMargin {
+		var ok bool
+		if targetCPUMargin, ok = v.(float64); !ok || targetCPUMargin < 0 {
+			handler.rd.JSON(w, http.StatusBadRequest, errors.New("invalid argument for 'target-cpu-margin'").Error())
+			return
+		}
+	}
+	if !hasThreshold && !hasMargin {
+		handler.rd.JSON(w, http.StatusBadRequest, errors.New("invalid argument for 'cpu-threshold'").Error())
+		return
+	}
+	if targetCPUMargin >= cpuThreshold {
+		handler.rd.JSON(w, http.StatusBadRequest, errors.New("'target-cpu-margin' should be less than 'cpu-threshold'").Error())
+		return
+	}
+	prevCPUThreshold, prevTargetCPUMargin := handler.config.CPUThreshold, handler.config.TargetCPUMargin
+	handler.config.CPUThreshold, handler.config.TargetCPUMargin = cpuThreshold, targetCPUMargin
+	if err := handler.config.persistLocked(); err != nil {
+		handler.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		handler.config.CPUThreshold, handler.config.TargetCPUMargin = prevCPUThreshold, prevTargetCPUMargin
+		return
+	}
+	log.Info("evict-hot-cpu-store-scheduler update config",
+		zap.Float64("prev-cpu-threshold", prevCPUThreshold), zap.Float64("cur-cpu-threshold", cpuThreshold),
+		zap.Float64("prev-target-cpu-margin", prevTargetCPUMargin), zap.Float64("cur-target-cpu-margin", targetCPUMargin))
+	handler.rd.JSON(w, http.StatusOK, "Config updated.")
+}
+
+func (handler *evictHotCPUStoreHandler) ListConfig(w http.ResponseWriter, _ *http.Request) {
+	conf := handler.config.Clone()
+	handler.rd.JSON(w, http.StatusOK, conf)
+}
+
+type evictHotCPUStoreScheduler struct {
+	*BaseScheduler
+	conf    *evictHotCPUStoreSchedulerConfig
+	handler http.Handler
+
+	mu syncutil.Mutex
+	// pausedStores are the stores whose leader transfer is paused by the scheduler,
+	// so that the other schedulers like balance-leader don't move the leaders back.
+	pausedStores map[uint64]struct{}
+}
+
+// newEvictHotCPUStoreScheduler creates a scheduler that evicts the leaders from the stores whose CPU usage is too high.
+func newEvictHotCPUStoreScheduler(opController *operator.Controller, conf *evictHotCPUStoreSchedulerConfig) Scheduler {
+	return &evictHotCPUStoreScheduler{
+		BaseScheduler: NewBaseScheduler(opController),
+		conf:          conf,
+		handler:       newEvictHotCPUStoreHandler(conf),
+		pausedStores:  make(map[uint64]struct{}),
+	}
+}
+
+func (s *evictHotCPUStoreScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+func (*evictHotCPUStoreScheduler) GetName() string {
+	return EvictHotCPUStoreName
+}
+
+func (*evictHotCPUStoreScheduler) GetType() string {
+	return EvictHotCPUStoreType
+}
+
+func (s *evictHotCPUStoreScheduler) EncodeConfig() ([]byte, error) {
+	return EncodeConfig(s.conf)
+}
+
+func (s *evictHotCPUStoreScheduler) ReloadConfig() error {
+	s.conf.Lock()
+	defer s.conf.Unlock()
+	cfgData, err := s.conf.storage.LoadSchedulerConfig(s.GetName())
+	if err != nil {
+		return err
+	}
+	if len(cfgData) == 0 {
+		return nil
+	}
+	newCfg := &evictHotCPUStoreSchedulerConfig{TargetCPUMargin: defaultHotCPUTargetMargin}
+	if err = DecodeConfig([]byte(cfgData), newCfg); err != nil {
+		return err
+	}
+	s.conf.CPUThreshold = newCfg.CPUThreshold
+	s.conf.TargetCPUMargin = newCfg.TargetCPUMargin
+	return nil
+}
+
+func (s *evictHotCPUStoreScheduler) CleanConfig(cluster sche.SchedulerCluster) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for storeID := range s.pausedStores {
+		cluster.RemoveLeaderTransferPauser(storeID, EvictHotCPUStoreName)
+		delete(s.pausedStores, storeID)
+	}
+}
+
+// updatePausedStores pauses the leader transfer of the hot stores, and resumes the
+// stores whose CPU usage drops below the target threshold. The pause is shared with
+// the other sources, e.g. an evict-leader scheduler on the same store.
+func (s *evictHotCPUStoreScheduler) updatePausedStores(cluster sche.SchedulerCluster, stores *hotCPUStores) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for storeID := range s.pausedStores {
+		if cpuUsage, ok := stores.cpuUsages[storeID]; ok && cpuUsage >= stores.targetThreshold {
+			continue
+		}
+		cluster.RemoveLeaderTransferPauser(storeID, EvictHotCPUStoreName)
+		delete(s.pausedStores, storeID)
+	}
+	for _, storeID := range stores.getStores() {
+		if _, ok := s.pausedStores[storeID]; ok {
+			continue
+		}
+		if err := cluster.AddLeaderTransferPauser(storeID, EvictHotCPUStoreName); err != nil {
+			log.Warn("failed to pause the leader transfer of the hot cpu store", zap.Uint64("store-id", storeID), errs.ZapError(err))
+			continue
+		}
+		s.pausedStores[storeID] = struct{}{}
+	}
+}
+
+func (s *evictHotCPUStoreScheduler) IsScheduleAllowed(cluster sche.SchedulerCluster) bool {
+	allowed := s.OpController.OperatorCount(operator.OpLeader) < cluster.GetSchedulerConfig().GetLeaderScheduleLimit()
+	if !allowed {
+		operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpLeader.String()).Inc()
+	}
+	return allowed
+}
+
+func (s *evictHotCPUStoreScheduler) Schedule(cluster sche.SchedulerCluster, _ bool) ([]*operator.Operator, []plan.Plan) {
+	evictHotCPUStoreCounter.Inc()
+	threshold, targetThreshold := s.conf.getThresholds()
+	stores := newHotCPUStores(cluster, threshold, targetThreshold)
+	s.updatePausedStores(cluster, stores)
+	if len(stores.getStores()) == 0 {
+		return nil, nil
+	}
+	return scheduleEvictLeaderBatch(s.GetName(), s.GetType(), cluster, stores, EvictLeaderBatchSize, nil, nil), nil
+}
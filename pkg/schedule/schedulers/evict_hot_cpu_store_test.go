@@ -0,0 +1,131 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/storage"
+	"github.com/tikv/pd/pkg/utils/operatorutil"
+)
+
+func TestEvictHotCPUStore(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	for id, cpuUsage := range map[uint64]uint64{1: 900, 2: 500, 3: 100, 4: 850} {
+		tc.AddLeaderStore(id, 0)
+		tc.UpdateStorageCPUUsage(id, cpuUsage)
+	}
+	tc.AddLeaderRegion(1, 1, 2, 3)
+
+	sl, err := CreateScheduler(EvictHotCPUStoreType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(EvictHotCPUStoreType, []string{}), func(string) error { return nil })
+	re.NoError(err)
+	re.True(sl.IsScheduleAllowed(tc))
+	// The least CPU loaded follower is picked.
+	ops, _ := sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	operatorutil.CheckMultiTargetTransferLeader(re, ops[0], operator.OpLeader, 1, []uint64{3})
+	// The follower above the threshold is skipped.
+	tc.AddLeaderRegion(1, 1, 2, 4)
+	ops, _ = sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	operatorutil.CheckMultiTargetTransferLeader(re, ops[0], operator.OpLeader, 1, []uint64{2})
+	// No target is cool enough.
+	tc.AddLeaderRegion(1, 1, 4)
+	ops, _ = sl.Schedule(tc, false)
+	re.Empty(ops)
+
+	// No store is above the threshold.
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	req := httptest.NewRequest(http.MethodPost, "/config", bytes.NewBufferString(`{"cpu-threshold": 1000}`))
+	resp := httptest.NewRecorder()
+	sl.(*evictHotCPUStoreScheduler).ServeHTTP(resp, req)
+	re.Equal(http.StatusOK, resp.Code)
+	ops, _ = sl.Schedule(tc, false)
+	re.Empty(ops)
+
+	req = httptest.NewRequest(http.MethodPost, "/config", bytes.NewBufferString(`{"cpu-threshold": -1}`))
+	resp = httptest.NewRecorder()
+	sl.(*evictHotCPUStoreScheduler).ServeHTTP(resp, req)
+	re.Equal(http.StatusBadRequest, resp.Code)
+	re.Equal(1000.0, sl.(*evictHotCPUStoreScheduler).conf.getCPUThreshold())
+}
+
+func TestEvictHotCPUStorePauseAndMargin(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	for id, cpuUsage := range map[uint64]uint64{1: 900, 2: 700, 3: 100} {
+		tc.AddLeaderStore(id, 0)
+		tc.UpdateStorageCPUUsage(id, cpuUsage)
+	}
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	tc.AddLeaderRegion(2, 2, 1)
+
+	sl, err := CreateScheduler(EvictHotCPUStoreType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(EvictHotCPUStoreType, []string{}), func(string) error { return nil })
+	re.NoError(err)
+	// The store 2 is under the threshold, but not by the margin.
+	ops, _ := sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	operatorutil.CheckMultiTargetTransferLeader(re, ops[0], operator.OpLeader, 1, []uint64{3})
+	// The hot store is paused, so balance-leader doesn't move the leaders back.
+	re.False(tc.GetStore(1).AllowLeaderTransfer())
+	re.Equal([]string{EvictHotCPUStoreName}, tc.GetLeaderTransferPausers(1))
+	// The pause is shared with evict-leader.
+	re.NoError(tc.PauseLeaderTransferWithReason(1, EvictLeaderName))
+
+	// The store 2 gets hot but doesn't transfer the leaders back to the store 1.
+	tc.UpdateStorageCPUUsage(1, 750)
+	tc.UpdateStorageCPUUsage(2, 850)
+	tc.AddLeaderRegion(1, 1, 2)
+	ops, _ = sl.Schedule(tc, false)
+	re.Empty(ops)
+	re.False(tc.GetStore(2).AllowLeaderTransfer())
+	// The store 1 stays paused until its CPU usage drops below the margin,
+	// even if evict-leader resumes it.
+	re.Equal([]string{EvictLeaderName, EvictHotCPUStoreName}, tc.GetLeaderTransferPausers(1))
+	tc.ResumeLeaderTransfer(1)
+	re.False(tc.GetStore(1).AllowLeaderTransfer())
+	tc.UpdateStorageCPUUsage(1, 500)
+	ops, _ = sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	operatorutil.CheckMultiTargetTransferLeader(re, ops[0], operator.OpLeader, 2, []uint64{1})
+	re.True(tc.GetStore(1).AllowLeaderTransfer())
+
+	// The pauses are released when the scheduler is removed.
+	sl.CleanConfig(tc)
+	re.True(tc.GetStore(2).AllowLeaderTransfer())
+
+	serve := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/config", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		sl.(*evictHotCPUStoreScheduler).ServeHTTP(resp, req)
+		return resp.Code
+	}
+	re.Equal(http.StatusBadRequest, serve(`{"target-cpu-margin": -1}`))
+	re.Equal(http.StatusBadRequest, serve(`{"target-cpu-margin": 800}`))
+	re.Equal(http.StatusOK, serve(`{"target-cpu-margin": 100}`))
+	threshold, targetThreshold := sl.(*evictHotCPUStoreScheduler).conf.getThresholds()
+	re.Equal(800.0, threshold)
+	re.Equal(700.0, targetThreshold)
+}
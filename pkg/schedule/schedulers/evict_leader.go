@@ -15,13 +15,15 @@
 package schedulers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
-	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/core/constant"
 	"github.com/tikv/pd/pkg/errs"
@@ -29,8 +31,10 @@ import (
 	"github.com/tikv/pd/pkg/schedule/filter"
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/schedule/plan"
+	"github.com/tikv/pd/pkg/slice"
 	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/utils/apiutil"
+	"github.com/tikv/pd/pkg/utils/logutil"
 	"github.com/tikv/pd/pkg/utils/syncutil"
 	"github.com/unrolled/render"
 )
@@ -46,6 +50,10 @@ const (
 	lastStoreDeleteInfo  = "The last store has been deleted"
 )
 
+// evictLeaderLogger collapses the repeated logs of the failed scheduling, which may
+// flood the log when the cluster is degraded.
+var evictLeaderLogger = logutil.NewRateLimitedLogger(10 * time.Second)
+
 var (
 	// WithLabelValues is a heavy operation, define variable to avoid call it every time.
 	evictLeaderCounter              = schedulerCounter.WithLabelValues(EvictLeaderName, "schedule")
@@ -59,8 +67,25 @@ type evictLeaderSchedulerConfig struct {
 	syncutil.RWMutex
 	storage           endpoint.ConfigStorage
 	StoreIDWithRanges map[uint64][]core.KeyRange `json:"store-id-ranges"`
+	// Version is increased on every change of the config, it's used to reject
+	// the stale updates of the concurrent read-modify-write.
+	Version uint64 `json:"version"`
+	// Paused stops the eviction of all the stores without removing them.
+	Paused bool `json:"paused,omitempty"`
+	// RecheckTargetHealth makes the leader transferred only after the target peer
+	// is re-checked to be neither pending nor down, see operator.WaitPeerHealthy.
+	RecheckTargetHealth bool `json:"recheck-target-health,omitempty"`
+	// PreferIsolatedTarget prefers the targets outside the top level location domain,
+	// e.g. the zone, of the evicted store.
+	PreferIsolatedTarget bool `json:"prefer-isolated-target,omitempty"`
+	// StoreRateLimit is the max number of operators per second toward each target
+	// store, a non-positive value means no limit.
+	StoreRateLimit    float64 `json:"store-rate-limit,omitempty"`
+	limiter           *operator.StoreRateLimiter
 	cluster           *core.BasicCluster
 	removeSchedulerCb func(string) error
+	// diagnoses records the results of the last scheduling.
+	diagnoses evictLeaderDiagnoses
 }
 
 func (conf *evictLeaderSchedulerConfig) getStores() []uint64 {
@@ -95,19 +120,28 @@ func (conf *evictLeaderSchedulerConfig) BuildWithArgs(args []string) error {
 func (conf *evictLeaderSchedulerConfig) Clone() *evictLeaderSchedulerConfig {
 	conf.RLock()
 	defer conf.RUnlock()
-	storeIDWithRanges := make(map[uint64][]core.KeyRange)
+	storeIDWithRanges := make(map[uint64][]core.KeyRange, len(conf.StoreIDWithRanges))
 	for id, ranges := range conf.StoreIDWithRanges {
-		storeIDWithRanges[id] = append(storeIDWithRanges[id], ranges...)
+		storeIDWithRanges[id] = slice.Clone(ranges)
 	}
 	return &evictLeaderSchedulerConfig{
-		StoreIDWithRanges: storeIDWithRanges,
+		StoreIDWithRanges:    storeIDWithRanges,
+		Version:              conf.Version,
+		Paused:               conf.Paused,
+		RecheckTargetHealth:  conf.RecheckTargetHealth,
+		PreferIsolatedTarget: conf.PreferIsolatedTarget,
+		StoreRateLimit:       conf.StoreRateLimit,
 	}
 }
 
 func (conf *evictLeaderSchedulerConfig) Persist() error {
-	name := conf.getSchedulerName()
 	conf.RLock()
 	defer conf.RUnlock()
+	return conf.persistLocked()
+}
+
+func (conf *evictLeaderSchedulerConfig) persistLocked() error {
+	name := conf.getSchedulerName()
 	data, err := EncodeConfig(conf)
 	failpoint.Inject("persistFail", func() {
 		err = errors.New("fail to persist")
@@ -133,6 +167,118 @@ func (conf *evictLeaderSchedulerConfig) getRanges(id uint64) []string {
 	return res
 }
 
+func (conf *evictLeaderSchedulerConfig) isPaused() bool {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.Paused
+}
+
+// setPaused pauses or resumes the eviction of all the stores and persists it.
+func (conf *evictLeaderSchedulerConfig) setPaused(paused bool) error {
+	conf.Lock()
+	defer conf.Unlock()
+	if conf.Paused == paused {
+		return nil
+	}
+	conf.Paused = paused
+	conf.Version++
+	if err := conf.persistLocked(); err != nil {
+		conf.Paused = !paused
+		conf.Version--
+		return err
+	}
+	return nil
+}
+
+// recheckTargetHealth implements evictLeaderTargetHealthRechecker.
+func (conf *evictLeaderSchedulerConfig) recheckTargetHealth() bool {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.RecheckTargetHealth
+}
+
+// setRecheckTargetHealth sets whether to re-check the health of the target peer
+// before transferring the leader, and persists it.
+func (conf *evictLeaderSchedulerConfig) setRecheckTargetHealth(recheck bool) error {
+	conf.Lock()
+	defer conf.Unlock()
+	if conf.RecheckTargetHealth == recheck {
+		return nil
+	}
+	conf.RecheckTargetHealth = recheck
+	conf.Version++
+	if err := conf.persistLocked(); err != nil {
+		conf.RecheckTargetHealth = !recheck
+		conf.Version--
+		return err
+	}
+	return nil
+}
+
+// preferIsolatedTarget implements evictLeaderTargetIsolator.
+func (conf *evictLeaderSchedulerConfig) preferIsolatedTarget() bool {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.PreferIsolatedTarget
+}
+
+// setPreferIsolatedTarget sets whether to prefer the targets outside the location
+// domain of the evicted store, and persists it.
+func (conf *evictLeaderSchedulerConfig) setPreferIsolatedTarget(prefer bool) error {
+	conf.Lock()
+	defer conf.Unlock()
+	if conf.PreferIsolatedTarget == prefer {
+		return nil
+	}
+	conf.PreferIsolatedTarget = prefer
+	conf.Version++
+	if err := conf.persistLocked(); err != nil {
+		conf.PreferIsolatedTarget = !prefer
+		conf.Version--
+		return err
+	}
+	return nil
+}
+
+// hasTargetLimit implements evictLeaderTargetLimiter.
+func (conf *evictLeaderSchedulerConfig) hasTargetLimit() bool {
+	return conf.limiter != nil && conf.limiter.GetRate() > 0
+}
+
+// limitTarget implements evictLeaderTargetLimiter.
+func (conf *evictLeaderSchedulerConfig) limitTarget(op *operator.Operator, storeID uint64) bool {
+	if conf.limiter == nil {
+		return true
+	}
+	if !conf.limiter.Available(storeID) {
+		return false
+	}
+	op.SetStoreRateLimiter(conf.limiter, storeID)
+	return true
+}
+
+// setStoreRateLimit sets the max number of operators per second toward each
+// target store, and persists it.
+func (conf *evictLeaderSchedulerConfig) setStoreRateLimit(ratePerSec float64) error {
+	conf.Lock()
+	defer conf.Unlock()
+	if conf.StoreRateLimit == ratePerSec {
+		return nil
+	}
+	old := conf.StoreRateLimit
+	conf.StoreRateLimit = ratePerSec
+	conf.Version++
+	if err := conf.persistLocked(); err != nil {
+		conf.StoreRateLimit = old
+		conf.Version--
+		return err
+	}
+	if conf.limiter != nil {
+		conf.limiter.SetRate(ratePerSec)
+	}
+	return nil
+}
+
 func (conf *evictLeaderSchedulerConfig) removeStore(id uint64) (succ bool, last bool) {
 	conf.Lock()
 	defer conf.Unlock()
@@ -140,6 +286,7 @@ func (conf *evictLeaderSchedulerConfig) removeStore(id uint64) (succ bool, last
 	succ, last = false, false
 	if exists {
 		delete(conf.StoreIDWithRanges, id)
+		conf.Version++
 		conf.cluster.ResumeLeaderTransfer(id)
 		succ = true
 		last = len(conf.StoreIDWithRanges) == 0
@@ -150,19 +297,107 @@ func (conf *evictLeaderSchedulerConfig) removeStore(id uint64) (succ bool, last
 func (conf *evictLeaderSchedulerConfig) resetStore(id uint64, keyRange []core.KeyRange) {
 	conf.Lock()
 	defer conf.Unlock()
-	conf.cluster.PauseLeaderTransfer(id)
+	conf.cluster.PauseLeaderTransferWithReason(id, EvictLeaderName)
 	conf.StoreIDWithRanges[id] = keyRange
 }
 
+// batchUpdate sets the ranges of the stores and persists the config atomically,
+// either all the stores are applied or the config is left unchanged.
+func (conf *evictLeaderSchedulerConfig) batchUpdate(storeIDWithRanges map[uint64][]core.KeyRange, expectedVersion *uint64) error {
+	conf.Lock()
+	defer conf.Unlock()
+	if expectedVersion != nil && *expectedVersion != conf.Version {
+		return errs.ErrSchedulerConfigVersionConflict.FastGenByArgs(*expectedVersion)
+	}
+	var paused []uint64
+	rollback := func() {
+		for _, id := range paused {
+			conf.cluster.ResumeLeaderTransfer(id)
+		}
+	}
+	for id := range storeIDWithRanges {
+		if _, exists := conf.StoreIDWithRanges[id]; exists {
+			continue
+		}
+		if err := conf.cluster.PauseLeaderTransferWithReason(id, EvictLeaderName); err != nil {
+			rollback()
+			return err
+		}
+		paused = append(paused, id)
+	}
+	oldStoreIDWithRanges := make(map[uint64][]core.KeyRange, len(conf.StoreIDWithRanges))
+	for id, ranges := range conf.StoreIDWithRanges {
+		oldStoreIDWithRanges[id] = ranges
+	}
+	oldVersion := conf.Version
+	for id, ranges := range storeIDWithRanges {
+		conf.StoreIDWithRanges[id] = ranges
+	}
+	conf.Version++
+	if err := conf.persistLocked(); err != nil {
+		conf.StoreIDWithRanges, conf.Version = oldStoreIDWithRanges, oldVersion
+		rollback()
+		return err
+	}
+	return nil
+}
+
+// updateStore sets the ranges of the store and persists the config atomically, the
+// existing ranges are kept if the ranges are nil. The config is left unchanged on failure.
+func (conf *evictLeaderSchedulerConfig) updateStore(id uint64, ranges []core.KeyRange, expectedVersion *uint64) error {
+	conf.Lock()
+	defer conf.Unlock()
+	if expectedVersion != nil && *expectedVersion != conf.Version {
+		return errs.ErrSchedulerConfigVersionConflict.FastGenByArgs(*expectedVersion)
+	}
+	oldRanges, exists := conf.StoreIDWithRanges[id]
+	if !exists {
+		if err := conf.cluster.PauseLeaderTransferWithReason(id, EvictLeaderName); err != nil {
+			return err
+		}
+	}
+	switch {
+	case ranges != nil:
+		conf.StoreIDWithRanges[id] = ranges
+	case !exists:
+		conf.StoreIDWithRanges[id] = []core.KeyRange{core.NewKeyRange("", "")}
+	}
+	conf.Version++
+	if err := conf.persistLocked(); err != nil {
+		conf.Version--
+		if exists {
+			conf.StoreIDWithRanges[id] = oldRanges
+		} else {
+			delete(conf.StoreIDWithRanges, id)
+			conf.cluster.ResumeLeaderTransfer(id)
+		}
+		return err
+	}
+	return nil
+}
+
+// getKeyRangesByID returns the merged key ranges of the store, which are the
+// effective key ranges to evict the leaders in.
 func (conf *evictLeaderSchedulerConfig) getKeyRangesByID(id uint64) []core.KeyRange {
 	conf.RLock()
 	defer conf.RUnlock()
 	if ranges, exist := conf.StoreIDWithRanges[id]; exist {
-		return ranges
+		return mergeKeyRanges(ranges)
 	}
 	return nil
 }
 
+// getEffectiveKeyRanges returns the effective key ranges of each store.
+func (conf *evictLeaderSchedulerConfig) getEffectiveKeyRanges() map[uint64][]core.KeyRange {
+	conf.RLock()
+	defer conf.RUnlock()
+	storeIDWithRanges := make(map[uint64][]core.KeyRange, len(conf.StoreIDWithRanges))
+	for id, ranges := range conf.StoreIDWithRanges {
+		storeIDWithRanges[id] = slice.Clone(mergeKeyRanges(ranges))
+	}
+	return storeIDWithRanges
+}
+
 type evictLeaderScheduler struct {
 	*BaseScheduler
 	conf    *evictLeaderSchedulerConfig
@@ -218,42 +453,138 @@ func (s *evictLeaderScheduler) ReloadConfig() error {
 	if err = DecodeConfig([]byte(cfgData), newCfg); err != nil {
 		return err
 	}
-	pauseAndResumeLeaderTransfer(s.conf.cluster, s.conf.StoreIDWithRanges, newCfg.StoreIDWithRanges)
+	pauseAndResumeLeaderTransfer(s.conf.cluster, EvictLeaderName, s.conf.StoreIDWithRanges, newCfg.StoreIDWithRanges)
 	s.conf.StoreIDWithRanges = newCfg.StoreIDWithRanges
+	s.conf.Version = newCfg.Version
+	s.conf.Paused = newCfg.Paused
+	s.conf.RecheckTargetHealth = newCfg.RecheckTargetHealth
+	s.conf.PreferIsolatedTarget = newCfg.PreferIsolatedTarget
+	s.conf.StoreRateLimit = newCfg.StoreRateLimit
+	if s.conf.limiter != nil {
+		s.conf.limiter.SetRate(newCfg.StoreRateLimit)
+	}
 	return nil
 }
 
 func (s *evictLeaderScheduler) PrepareConfig(cluster sche.SchedulerCluster) error {
-	s.conf.RLock()
-	defer s.conf.RUnlock()
-	var res error
-	for id := range s.conf.StoreIDWithRanges {
-		if err := cluster.PauseLeaderTransfer(id); err != nil {
-			res = err
-		}
-	}
-	return res
+	return cluster.PauseLeaderTransferBatch(s.conf.getStores(), EvictLeaderName)
 }
 
 func (s *evictLeaderScheduler) CleanConfig(cluster sche.SchedulerCluster) {
-	s.conf.RLock()
-	defer s.conf.RUnlock()
-	for id := range s.conf.StoreIDWithRanges {
-		cluster.ResumeLeaderTransfer(id)
-	}
+	cluster.ResumeLeaderTransferBatch(s.conf.getStores())
 }
 
 func (s *evictLeaderScheduler) IsScheduleAllowed(cluster sche.SchedulerCluster) bool {
+	if s.conf.isPaused() {
+		s.setDiagnosesReason("the eviction of all the stores is paused")
+		return false
+	}
+	// The leaders may be evicted to the wrong stores with a partially loaded
+	// region view, so wait for the region information to catch up first.
+	if !cluster.IsPrepared() {
+		s.setDiagnosesReason("the region information is not prepared yet")
+		return false
+	}
 	allowed := s.OpController.OperatorCount(operator.OpLeader) < cluster.GetSchedulerConfig().GetLeaderScheduleLimit()
 	if !allowed {
 		operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpLeader.String()).Inc()
+		s.setDiagnosesReason("the leader schedule limit is exceeded")
 	}
 	return allowed
 }
 
-func (s *evictLeaderScheduler) Schedule(cluster sche.SchedulerCluster, _ bool) ([]*operator.Operator, []plan.Plan) {
+// setDiagnosesReason sets the same failure reason for all the evicted stores.
+func (s *evictLeaderScheduler) setDiagnosesReason(reason string) {
+	diagnoses := make(evictLeaderDiagnoses)
+	for _, storeID := range s.conf.getStores() {
+		diagnoses.get(storeID).Reason = reason
+	}
+	s.conf.setDiagnoses(diagnoses)
+}
+
+func (s *evictLeaderScheduler) Schedule(cluster sche.SchedulerCluster, dryRun bool) ([]*operator.Operator, []plan.Plan) {
 	evictLeaderCounter.Inc()
-	return scheduleEvictLeaderBatch(s.GetName(), s.GetType(), cluster, s.conf, EvictLeaderBatchSize), nil
+	var collector *plan.Collector
+	if dryRun {
+		collector = plan.NewCollector(plan.NewBalanceSchedulerPlan())
+	}
+	diagnoses := make(evictLeaderDiagnoses)
+	ops := scheduleEvictLeaderBatch(s.GetName(), s.GetType(), cluster, s.conf, EvictLeaderBatchSize, diagnoses, collector)
+	s.conf.setDiagnoses(diagnoses)
+	return ops, collector.GetPlans()
+}
+
+// evictLeaderDiagnosis explains the result of the last scheduling of an evicted store.
+type evictLeaderDiagnosis struct {
+	// CandidateRegions is the number of the sampled leader regions in the key ranges.
+	CandidateRegions int `json:"candidate-regions"`
+	// FilteredRegions is the number of the candidate regions filtered out for being unhealthy.
+	FilteredRegions int `json:"filtered-regions"`
+	// Operators is the number of the created operators.
+	Operators int `json:"operators"`
+	// Reason is why no operator is created, it's empty if any operator is created.
+	Reason string `json:"reason,omitempty"`
+}
+
+func (d *evictLeaderDiagnosis) fail(reason string) {
+	if d != nil && d.Operators == 0 {
+		d.Reason = reason
+	}
+}
+
+func (d *evictLeaderDiagnosis) addCandidateRegions(n int) {
+	if d != nil {
+		d.CandidateRegions += n
+	}
+}
+
+func (d *evictLeaderDiagnosis) filterRegion() {
+	if d != nil {
+		d.FilteredRegions++
+	}
+}
+
+func (d *evictLeaderDiagnosis) addOperator() {
+	if d != nil {
+		d.Operators++
+		d.Reason = ""
+	}
+}
+
+// evictLeaderDiagnoses records the diagnosis of each store, nil means no recording.
+type evictLeaderDiagnoses map[uint64]*evictLeaderDiagnosis
+
+func (d evictLeaderDiagnoses) get(storeID uint64) *evictLeaderDiagnosis {
+	if d == nil {
+		return nil
+	}
+	diagnosis, ok := d[storeID]
+	if !ok {
+		diagnosis = &evictLeaderDiagnosis{}
+		d[storeID] = diagnosis
+	}
+	return diagnosis
+}
+
+func (conf *evictLeaderSchedulerConfig) setDiagnoses(diagnoses evictLeaderDiagnoses) {
+	conf.Lock()
+	defer conf.Unlock()
+	conf.diagnoses = diagnoses
+}
+
+// getDiagnoses returns the diagnosis of each configured store.
+func (conf *evictLeaderSchedulerConfig) getDiagnoses() map[uint64]evictLeaderDiagnosis {
+	conf.RLock()
+	defer conf.RUnlock()
+	diagnoses := make(map[uint64]evictLeaderDiagnosis, len(conf.StoreIDWithRanges))
+	for storeID := range conf.StoreIDWithRanges {
+		if diagnosis, ok := conf.diagnoses[storeID]; ok {
+			diagnoses[storeID] = *diagnosis
+		} else {
+			diagnoses[storeID] = evictLeaderDiagnosis{Reason: "the store has not been scheduled yet"}
+		}
+	}
+	return diagnoses
 }
 
 func uniqueAppendOperator(dst []*operator.Operator, src ...*operator.Operator) []*operator.Operator {
@@ -276,10 +607,45 @@ type evictLeaderStoresConf interface {
 	getKeyRangesByID(id uint64) []core.KeyRange
 }
 
-func scheduleEvictLeaderBatch(name, typ string, cluster sche.SchedulerCluster, conf evictLeaderStoresConf, batchSize int) []*operator.Operator {
+// evictLeaderTargetPicker can be implemented by the evictLeaderStoresConf to pick
+// the target store from the candidates instead of picking it randomly.
+type evictLeaderTargetPicker interface {
+	pickTarget(candidates []*core.StoreInfo) *core.StoreInfo
+}
+
+// evictLeaderTargetHealthRechecker can be implemented by the evictLeaderStoresConf to
+// re-check the health of the target peer before transferring the leader.
+type evictLeaderTargetHealthRechecker interface {
+	recheckTargetHealth() bool
+}
+
+// evictLeaderTargetIsolator can be implemented by the evictLeaderStoresConf to prefer
+// the targets outside the failure domain of the evicted store.
+type evictLeaderTargetIsolator interface {
+	preferIsolatedTarget() bool
+}
+
+// evictLeaderTargetLimiter can be implemented by the evictLeaderStoresConf to limit
+// the rate of the operators toward each target store.
+type evictLeaderTargetLimiter interface {
+	hasTargetLimit() bool
+	// limitTarget returns false if the operator toward the target store exceeds the
+	// limit, otherwise the operator takes the token once it's added to the controller.
+	limitTarget(op *operator.Operator, storeID uint64) bool
+}
+
+func scheduleEvictLeaderBatch(name, typ string, cluster sche.SchedulerCluster, conf evictLeaderStoresConf, batchSize int,
+	diagnoses evictLeaderDiagnoses, collector *plan.Collector) []*operator.Operator {
 	var ops []*operator.Operator
 	for i := 0; i < batchSize; i++ {
-		once := scheduleEvictLeaderOnce(name, typ, cluster, conf)
+		var once []*operator.Operator
+		if i == 0 {
+			once = scheduleEvictLeaderOnce(name, typ, cluster, conf, diagnoses, collector)
+		} else {
+			// The later rounds sample the regions of the same stores again, so they are
+			// not recorded to avoid counting the candidates and collecting the plans repeatedly.
+			once = scheduleEvictLeaderOnce(name, typ, cluster, conf, nil, nil)
+		}
 		// no more regions
 		if len(once) == 0 {
 			break
@@ -290,26 +656,54 @@ func scheduleEvictLeaderBatch(name, typ string, cluster sche.SchedulerCluster, c
 			break
 		}
 	}
+	if diagnoses != nil {
+		// The operators of all the rounds are counted after being deduplicated.
+		for _, op := range ops {
+			if region := cluster.GetRegion(op.RegionID()); region != nil {
+				diagnoses.get(region.GetLeader().GetStoreId()).addOperator()
+			}
+		}
+	}
 	return ops
 }
 
-func scheduleEvictLeaderOnce(name, typ string, cluster sche.SchedulerCluster, conf evictLeaderStoresConf) []*operator.Operator {
+func scheduleEvictLeaderOnce(name, typ string, cluster sche.SchedulerCluster, conf evictLeaderStoresConf,
+	diagnoses evictLeaderDiagnoses, collector *plan.Collector) []*operator.Operator {
 	stores := conf.getStores()
 	ops := make([]*operator.Operator, 0, len(stores))
 	for _, storeID := range stores {
+		diagnosis := diagnoses.get(storeID)
+		source := cluster.GetStore(storeID)
 		ranges := conf.getKeyRangesByID(storeID)
 		if len(ranges) == 0 {
+			diagnosis.fail("the store has no key range")
+			collectEvictLeaderPlan(collector, plan.NewStatus(plan.StatusStoreScoreDisallowed, "the store has no key range"), source, nil, nil)
 			continue
 		}
 		var filters []filter.Filter
 		pendingFilter := filter.NewRegionPendingFilter()
 		downFilter := filter.NewRegionDownFilter()
-		region := filter.SelectOneRegion(cluster.RandLeaderRegions(storeID, ranges), nil, pendingFilter, downFilter)
+		candidateRegions := cluster.RandLeaderRegions(storeID, ranges)
+		if diagnosis != nil || collector != nil {
+			for _, region := range candidateRegions {
+				for _, f := range []filter.RegionFilter{pendingFilter, downFilter} {
+					if status := f.Select(region); !status.IsOK() {
+						diagnosis.filterRegion()
+						collectEvictLeaderPlan(collector, status, source, region, nil)
+						break
+					}
+				}
+			}
+			diagnosis.addCandidateRegions(len(candidateRegions))
+		}
+		region := filter.SelectOneRegion(candidateRegions, nil, pendingFilter, downFilter)
 		if region == nil {
 			// try to pick unhealthy region
 			region = filter.SelectOneRegion(cluster.RandLeaderRegions(storeID, ranges), nil)
 			if region == nil {
 				evictLeaderNoLeaderCounter.Inc()
+				diagnosis.fail("no leader region is found in the key ranges")
+				collectEvictLeaderPlan(collector, plan.NewStatus(plan.StatusStoreScoreDisallowed, "no leader region is found in the key ranges"), source, nil, nil)
 				continue
 			}
 			evictLeaderPickUnhealthyCounter.Inc()
@@ -326,30 +720,143 @@ func scheduleEvictLeaderOnce(name, typ string, cluster sche.SchedulerCluster, co
 		filters = append(filters, &filter.StoreStateFilter{ActionScope: name, TransferLeader: true, OperatorLevel: constant.Urgent})
 		candidates := filter.NewCandidates(cluster.GetFollowerStores(region)).
 			FilterTarget(cluster.GetSchedulerConfig(), nil, nil, filters...)
-		// Compatible with old TiKV transfer leader logic.
-		target := candidates.RandomPick()
+		// Prefer the targets outside the failure domain of the evicted store,
+		// but still evict the leader if there is no such target.
+		if isolator, ok := conf.(evictLeaderTargetIsolator); ok && isolator.preferIsolatedTarget() {
+			if isolationFilter := newEvictLeaderIsolationFilter(name, cluster, storeID); isolationFilter != nil {
+				isolatedCandidates := filter.NewCandidates(candidates.PickAll()).
+					FilterTarget(cluster.GetSchedulerConfig(), nil, nil, isolationFilter)
+				if len(isolatedCandidates.Stores) > 0 {
+					candidates = isolatedCandidates
+				}
+			}
+		}
+		var target *core.StoreInfo
 		targets := candidates.PickAll()
+		if picker, ok := conf.(evictLeaderTargetPicker); ok {
+			// Only transfer the leader to the picked target.
+			if target = picker.pickTarget(targets); target != nil {
+				targets = []*core.StoreInfo{target}
+			}
+		} else {
+			// Compatible with old TiKV transfer leader logic.
+			target = candidates.RandomPick()
+		}
 		// `targets` MUST contains `target`, so only needs to check if `target` is nil here.
 		if target == nil {
 			evictLeaderNoTargetStoreCounter.Inc()
+			diagnosis.fail(explainNoTargetStore(cluster, region, filters))
+			collectNoTargetStorePlans(collector, cluster, source, region, filters)
 			continue
 		}
+		limiter, limited := conf.(evictLeaderTargetLimiter)
+		if limited && limiter.hasTargetLimit() {
+			// Only the picked target is limited, so the leader is only transferred to it.
+			targets = []*core.StoreInfo{target}
+		} else {
+			limited = false
+		}
 		targetIDs := make([]uint64, 0, len(targets))
 		for _, t := range targets {
 			targetIDs = append(targetIDs, t.GetID())
 		}
-		op, err := operator.CreateTransferLeaderOperator(typ, cluster, region, target.GetID(), targetIDs, operator.OpLeader)
+		var op *operator.Operator
+		var err error
+		if rechecker, ok := conf.(evictLeaderTargetHealthRechecker); ok && rechecker.recheckTargetHealth() {
+			op, err = operator.CreateHealthCheckedTransferLeaderOperator(typ, cluster, region, target.GetID(), targetIDs, operator.OpLeader)
+		} else {
+			op, err = operator.CreateTransferLeaderOperator(typ, cluster, region, target.GetID(), targetIDs, operator.OpLeader)
+		}
 		if err != nil {
-			log.Debug("fail to create evict leader operator", errs.ZapError(err))
+			evictLeaderLogger.Debug("fail to create evict leader operator", errs.ZapError(err))
+			diagnosis.fail(fmt.Sprintf("%s: %v", plan.NewStatus(plan.StatusCreateOperatorFailed), err))
+			collectEvictLeaderPlan(collector, plan.NewStatus(plan.StatusCreateOperatorFailed, err.Error()), source, region, target)
+			continue
+		}
+		if limited && !limiter.limitTarget(op, target.GetID()) {
+			reason := fmt.Sprintf("the operators toward the target store %d are rate limited", target.GetID())
+			diagnosis.fail(reason)
+			collectEvictLeaderPlan(collector, plan.NewStatus(plan.StatusStoreAddLimitThrottled, reason), source, region, target)
 			continue
 		}
 		op.SetPriorityLevel(constant.Urgent)
 		op.Counters = append(op.Counters, evictLeaderNewOperatorCounter)
 		ops = append(ops, op)
+		collectEvictLeaderPlan(collector, plan.NewStatus(plan.StatusOK), source, region, target)
 	}
 	return ops
 }
 
+// newEvictLeaderIsolationFilter returns a filter to avoid the targets in the same
+// top level location domain, e.g. the zone, as the evicted store. It returns nil
+// if the location labels are not configured.
+func newEvictLeaderIsolationFilter(name string, cluster sche.SchedulerCluster, storeID uint64) filter.Filter {
+	locationLabels := cluster.GetSharedConfig().GetLocationLabels()
+	store := cluster.GetStore(storeID)
+	if len(locationLabels) == 0 || store == nil {
+		return nil
+	}
+	return filter.NewLabelIsolationFilter(name, locationLabels[0], []*core.StoreInfo{store})
+}
+
+// explainNoTargetStore returns why each follower of the region cannot be the target store.
+func explainNoTargetStore(cluster sche.SchedulerCluster, region *core.RegionInfo, filters []filter.Filter) string {
+	followers := cluster.GetFollowerStores(region)
+	if len(followers) == 0 {
+		return "no eligible target store: the region has no follower"
+	}
+	reasons := make([]string, 0, len(followers))
+	for _, store := range followers {
+		for _, f := range filters {
+			if status := f.Target(cluster.GetSchedulerConfig(), store); !status.IsOK() {
+				reasons = append(reasons, fmt.Sprintf("store %d is rejected by %s for %s", store.GetID(), f.Type(), status))
+				break
+			}
+		}
+	}
+	return "no eligible target store: " + strings.Join(reasons, ", ")
+}
+
+// collectEvictLeaderPlan collects the plan of the evicted store. The region and the
+// target store are set in order if given, and the plan is dropped if the store is
+// not found since the plan cannot refer to it.
+func collectEvictLeaderPlan(collector *plan.Collector, status *plan.Status, source *core.StoreInfo, region *core.RegionInfo, target *core.StoreInfo) {
+	if collector == nil || source == nil {
+		return
+	}
+	opts := []plan.Option{plan.SetResource(source)}
+	step := 0
+	if region != nil {
+		step++
+		opts = append(opts, plan.SetResourceWithStep(region, step))
+		if target != nil {
+			step++
+			opts = append(opts, plan.SetResourceWithStep(target, step))
+		}
+	}
+	collector.Collect(append(opts, plan.SetStatus(status))...)
+}
+
+// collectNoTargetStorePlans collects the plan of each follower filtered out as the target store.
+func collectNoTargetStorePlans(collector *plan.Collector, cluster sche.SchedulerCluster, source *core.StoreInfo, region *core.RegionInfo, filters []filter.Filter) {
+	if collector == nil {
+		return
+	}
+	followers := cluster.GetFollowerStores(region)
+	if len(followers) == 0 {
+		collectEvictLeaderPlan(collector, plan.NewStatus(plan.StatusRegionNotReplicated, "the region has no follower"), source, region, nil)
+		return
+	}
+	for _, store := range followers {
+		for _, f := range filters {
+			if status := f.Target(cluster.GetSchedulerConfig(), store); !status.IsOK() {
+				collectEvictLeaderPlan(collector, status, source, region, store)
+				break
+			}
+		}
+	}
+}
+
 type evictLeaderHandler struct {
 	rd     *render.Render
 	config *evictLeaderSchedulerConfig
@@ -360,46 +867,169 @@ func (handler *evictLeaderHandler) UpdateConfig(w http.ResponseWriter, r *http.R
 	if err := apiutil.ReadJSONRespondError(handler.rd, w, r.Body, &input); err != nil {
 		return
 	}
-	var args []string
-	var exists bool
-	var id uint64
+	// The version is optional for the compatibility, the update is rejected
+	// if the config has been changed since the given version was listed.
+	var expectedVersion *uint64
+	if versionFloat, ok := input["version"].(float64); ok {
+		version := uint64(versionFloat)
+		expectedVersion = &version
+	}
 	idFloat, ok := input["store_id"].(float64)
-	if ok {
-		id = (uint64)(idFloat)
-		handler.config.RLock()
-		if _, exists = handler.config.StoreIDWithRanges[id]; !exists {
-			if err := handler.config.cluster.PauseLeaderTransfer(id); err != nil {
-				handler.config.RUnlock()
-				handler.rd.JSON(w, http.StatusInternalServerError, err.Error())
-				return
-			}
+	if !ok {
+		handler.rd.JSON(w, http.StatusBadRequest, errs.ErrSchedulerConfig.FastGenByArgs("id").Error())
+		return
+	}
+	var ranges []core.KeyRange
+	if args, ok := (input["ranges"]).([]string); ok {
+		var err error
+		if ranges, err = getKeyRanges(args); err != nil {
+			handler.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
 		}
-		handler.config.RUnlock()
-		args = append(args, strconv.FormatUint(id, 10))
 	}
-
-	ranges, ok := (input["ranges"]).([]string)
-	if ok {
-		args = append(args, ranges...)
-	} else if exists {
-		args = append(args, handler.config.getRanges(id)...)
+	err := handler.config.updateStore(uint64(idFloat), ranges, expectedVersion)
+	if errs.ErrSchedulerConfigVersionConflict.Equal(err) {
+		handler.rd.JSON(w, http.StatusConflict, err.Error())
+		return
 	}
-
-	handler.config.BuildWithArgs(args)
-	err := handler.config.Persist()
 	if err != nil {
-		handler.config.removeStore(id)
 		handler.rd.JSON(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	handler.rd.JSON(w, http.StatusOK, "The scheduler has been applied to the store.")
 }
 
+// BatchUpdateConfig applies the evict-leader to the stores in the input, which maps the
+// store IDs to their ranges, e.g. {"stores": {"1": [], "2": ["a", "b"]}, "version": 1}.
+// The stores are applied atomically, none of them is changed if any one fails.
+func (handler *evictLeaderHandler) BatchUpdateConfig(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Stores  map[uint64][]string `json:"stores"`
+		Version *uint64             `json:"version"`
+	}
+	if err := apiutil.ReadJSONRespondError(handler.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	if len(input.Stores) == 0 {
+		handler.rd.JSON(w, http.StatusBadRequest, errs.ErrSchedulerConfig.FastGenByArgs("stores").Error())
+		return
+	}
+	storeIDWithRanges := make(map[uint64][]core.KeyRange, len(input.Stores))
+	for id, args := range input.Stores {
+		ranges, err := getKeyRanges(args)
+		if err != nil {
+			handler.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		storeIDWithRanges[id] = ranges
+	}
+	err := handler.config.batchUpdate(storeIDWithRanges, input.Version)
+	switch {
+	case err == nil:
+		handler.rd.JSON(w, http.StatusOK, "The scheduler has been applied to the stores.")
+	case errs.ErrSchedulerConfigVersionConflict.Equal(err):
+		handler.rd.JSON(w, http.StatusConflict, err.Error())
+	case errs.ErrStoreNotFound.Equal(err), errs.ErrPauseLeaderTransfer.Equal(err):
+		handler.rd.JSON(w, http.StatusBadRequest, err.Error())
+	default:
+		handler.rd.JSON(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// PauseAll pauses the eviction of all the stores until ResumeAll is called, the
+// stores and their key ranges are kept.
+func (handler *evictLeaderHandler) PauseAll(w http.ResponseWriter, _ *http.Request) {
+	if err := handler.config.setPaused(true); err != nil {
+		handler.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	handler.rd.JSON(w, http.StatusOK, "The eviction of all the stores is paused.")
+}
+
+// SetRecheckTargetHealth sets whether to re-check the health of the target peers before
+// transferring the leaders, e.g. {"recheck-target-health": true}.
+func (handler *evictLeaderHandler) SetRecheckTargetHealth(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		RecheckTargetHealth *bool `json:"recheck-target-health"`
+	}
+	if err := apiutil.ReadJSONRespondError(handler.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	if input.RecheckTargetHealth == nil {
+		handler.rd.JSON(w, http.StatusBadRequest, errs.ErrSchedulerConfig.FastGenByArgs("recheck-target-health").Error())
+		return
+	}
+	if err := handler.config.setRecheckTargetHealth(*input.RecheckTargetHealth); err != nil {
+		handler.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	handler.rd.JSON(w, http.StatusOK, "The config is updated.")
+}
+
+// SetPreferIsolatedTarget sets whether to prefer the targets outside the location domain
+// of the evicted stores, e.g. {"prefer-isolated-target": true}.
+func (handler *evictLeaderHandler) SetPreferIsolatedTarget(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		PreferIsolatedTarget *bool `json:"prefer-isolated-target"`
+	}
+	if err := apiutil.ReadJSONRespondError(handler.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	if input.PreferIsolatedTarget == nil {
+		handler.rd.JSON(w, http.StatusBadRequest, errs.ErrSchedulerConfig.FastGenByArgs("prefer-isolated-target").Error())
+		return
+	}
+	if err := handler.config.setPreferIsolatedTarget(*input.PreferIsolatedTarget); err != nil {
+		handler.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	handler.rd.JSON(w, http.StatusOK, "The config is updated.")
+}
+
+// SetStoreRateLimit sets the max number of operators per second toward each target
+// store, e.g. {"store-rate-limit": 10}, 0 means no limit.
+func (handler *evictLeaderHandler) SetStoreRateLimit(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		StoreRateLimit *float64 `json:"store-rate-limit"`
+	}
+	if err := apiutil.ReadJSONRespondError(handler.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	if input.StoreRateLimit == nil || *input.StoreRateLimit < 0 {
+		handler.rd.JSON(w, http.StatusBadRequest, errs.ErrSchedulerConfig.FastGenByArgs("store-rate-limit").Error())
+		return
+	}
+	if err := handler.config.setStoreRateLimit(*input.StoreRateLimit); err != nil {
+		handler.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	handler.rd.JSON(w, http.StatusOK, "The config is updated.")
+}
+
+// ResumeAll resumes the eviction of all the stores paused by PauseAll.
+func (handler *evictLeaderHandler) ResumeAll(w http.ResponseWriter, _ *http.Request) {
+	if err := handler.config.setPaused(false); err != nil {
+		handler.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	handler.rd.JSON(w, http.StatusOK, "The eviction of all the stores is resumed.")
+}
+
 func (handler *evictLeaderHandler) ListConfig(w http.ResponseWriter, _ *http.Request) {
 	conf := handler.config.Clone()
 	handler.rd.JSON(w, http.StatusOK, conf)
 }
 
+// ListEffectiveConfig lists the key ranges of each store used by the scheduling,
+// the overlapping and adjacent ranges in the config are merged.
+func (handler *evictLeaderHandler) ListEffectiveConfig(w http.ResponseWriter, _ *http.Request) {
+	handler.rd.JSON(w, http.StatusOK, handler.config.getEffectiveKeyRanges())
+}
+
+func (handler *evictLeaderHandler) Diagnose(w http.ResponseWriter, _ *http.Request) {
+	handler.rd.JSON(w, http.StatusOK, handler.config.getDiagnoses())
+}
+
 func (handler *evictLeaderHandler) DeleteConfig(w http.ResponseWriter, r *http.Request) {
 	idStr := mux.Vars(r)["store_id"]
 	id, err := strconv.ParseUint(idStr, 10, 64)
@@ -444,7 +1074,15 @@ func newEvictLeaderHandler(config *evictLeaderSchedulerConfig) http.Handler {
 	}
 	router := mux.NewRouter()
 	router.HandleFunc("/config", h.UpdateConfig).Methods(http.MethodPost)
+	router.HandleFunc("/config/batch", h.BatchUpdateConfig).Methods(http.MethodPost)
+	router.HandleFunc("/config/pause-all", h.PauseAll).Methods(http.MethodPost)
+	router.HandleFunc("/config/resume-all", h.ResumeAll).Methods(http.MethodPost)
+	router.HandleFunc("/config/recheck-target-health", h.SetRecheckTargetHealth).Methods(http.MethodPost)
+	router.HandleFunc("/config/prefer-isolated-target", h.SetPreferIsolatedTarget).Methods(http.MethodPost)
+	router.HandleFunc("/config/store-rate-limit", h.SetStoreRateLimit).Methods(http.MethodPost)
 	router.HandleFunc("/list", h.ListConfig).Methods(http.MethodGet)
+	router.HandleFunc("/config/diagnose", h.Diagnose).Methods(http.MethodGet)
+	router.HandleFunc("/config/effective", h.ListEffectiveConfig).Methods(http.MethodGet)
 	router.HandleFunc("/delete/{store_id}", h.DeleteConfig).Methods(http.MethodDelete)
 	return router
 }
@@ -16,15 +16,26 @@ package schedulers
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/schedule/plan"
 	"github.com/tikv/pd/pkg/storage"
+	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/utils/operatorutil"
+	"github.com/tikv/pd/pkg/utils/testutil"
 )
 
 func TestEvictLeader(t *testing.T) {
@@ -43,6 +54,8 @@ func TestEvictLeader(t *testing.T) {
 
 	sl, err := CreateScheduler(EvictLeaderType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
 	re.NoError(err)
+	re.NoError(sl.PrepareConfig(tc))
+	re.Equal(EvictLeaderName, tc.GetStore(1).GetPauseLeaderTransferReason())
 	re.True(sl.IsScheduleAllowed(tc))
 	ops, _ := sl.Schedule(tc, false)
 	operatorutil.CheckMultiTargetTransferLeader(re, ops[0], operator.OpLeader, 1, []uint64{2, 3})
@@ -103,4 +116,601 @@ func TestConfigClone(t *testing.T) {
 	re.True(bytes.Equal(con4.StoreIDWithRanges[1][0].StartKey, con3.StoreIDWithRanges[1][0].StartKey))
 	con4.StoreIDWithRanges[1][0].StartKey = []byte("aaa")
 	re.False(bytes.Equal(con4.StoreIDWithRanges[1][0].StartKey, con3.StoreIDWithRanges[1][0].StartKey))
+
+	// Mutating the ranges of the clone doesn't affect the original.
+	con5 := con4.Clone()
+	con5.StoreIDWithRanges[1][0] = core.NewKeyRange("x", "y")
+	con5.StoreIDWithRanges[1] = append(con5.StoreIDWithRanges[1][:1], core.NewKeyRange("z", ""))
+	re.Equal([]byte("aaa"), con4.StoreIDWithRanges[1][0].StartKey)
+	re.Equal(con3.getRanges(1)[2:], con4.getRanges(1)[2:])
+}
+
+func TestEvictLeaderDiagnose(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	tc.SetStoreDown(2)
+	sl, err := CreateScheduler(EvictLeaderType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+
+	diagnose := func() map[uint64]evictLeaderDiagnosis {
+		req := httptest.NewRequest(http.MethodGet, "/config/diagnose", http.NoBody)
+		resp := httptest.NewRecorder()
+		sl.(*evictLeaderScheduler).ServeHTTP(resp, req)
+		re.Equal(http.StatusOK, resp.Code)
+		diagnoses := make(map[uint64]evictLeaderDiagnosis)
+		re.NoError(json.Unmarshal(resp.Body.Bytes(), &diagnoses))
+		return diagnoses
+	}
+	re.Equal("the store has not been scheduled yet", diagnose()[1].Reason)
+
+	ops, _ := sl.Schedule(tc, false)
+	re.Empty(ops)
+	diagnosis := diagnose()[1]
+	re.Positive(diagnosis.CandidateRegions)
+	candidateRegions := diagnosis.CandidateRegions
+	re.Zero(diagnosis.FilteredRegions)
+	re.Zero(diagnosis.Operators)
+	re.Contains(diagnosis.Reason, "no eligible target store")
+	re.Contains(diagnosis.Reason, "store 2 is rejected by store-state-down-filter for StoreDown")
+
+	// The diagnosis is cleared once an operator is created.
+	tc.AddLeaderStore(2, 0)
+	ops, _ = sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	diagnosis = diagnose()[1]
+	// The repeated rounds of the batch are not counted.
+	re.Equal(candidateRegions, diagnosis.CandidateRegions)
+	re.Equal(1, diagnosis.Operators)
+	re.Empty(diagnosis.Reason)
+}
+
+func TestEvictLeaderPlans(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	tc.SetStoreDown(2)
+	tc.SetStoreDown(3)
+	sl, err := CreateScheduler(EvictLeaderType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+
+	// No plan is collected without the dry run.
+	ops, plans := sl.Schedule(tc, false)
+	re.Empty(ops)
+	re.Empty(plans)
+	// All the targets are filtered out.
+	ops, plans = sl.Schedule(tc, true)
+	re.Empty(ops)
+	re.Len(plans, 2)
+	targets := make([]uint64, 0, len(plans))
+	for _, p := range plans {
+		re.Equal(plan.StatusStoreDown, int(p.GetStatus().StatusCode))
+		balancePlan := p.(*plan.BalanceSchedulerPlan)
+		re.Equal(uint64(1), balancePlan.Source.GetID())
+		re.Equal(uint64(1), balancePlan.Region.GetID())
+		targets = append(targets, balancePlan.Target.GetID())
+	}
+	re.ElementsMatch([]uint64{2, 3}, targets)
+
+	// The selected target is collected as the schedulable plan.
+	tc.AddLeaderStore(3, 0)
+	ops, plans = sl.Schedule(tc, true)
+	re.Len(ops, 1)
+	// The plan is collected once even if the batch schedules the store repeatedly.
+	re.Len(plans, 1)
+	re.True(plans[0].GetStatus().IsOK())
+	re.Equal(uint64(3), plans[0].(*plan.BalanceSchedulerPlan).Target.GetID())
+}
+
+func TestEvictLeaderWithLocationLabels(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	tc.SetLocationLabels([]string{"zone", "host"})
+	tc.AddLabelsStore(1, 0, map[string]string{"zone": "z1", "host": "h1"})
+	tc.AddLabelsStore(2, 0, map[string]string{"zone": "z1", "host": "h2"})
+	tc.AddLabelsStore(3, 0, map[string]string{"zone": "z2", "host": "h1"})
+	tc.AddLeaderRegion(1, 1, 2, 3)
+
+	sl, err := CreateScheduler(EvictLeaderType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+	re.NoError(sl.PrepareConfig(tc))
+	// The location is ignored by default.
+	ops, _ := sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	operatorutil.CheckMultiTargetTransferLeader(re, ops[0], operator.OpLeader, 1, []uint64{2, 3})
+
+	resp := httptest.NewRecorder()
+	sl.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/config/prefer-isolated-target", bytes.NewBufferString(`{"prefer-isolated-target": true}`)))
+	re.Equal(http.StatusOK, resp.Code)
+	re.True(sl.(*evictLeaderScheduler).conf.preferIsolatedTarget())
+	// The follower in the same zone as the evicted store is avoided.
+	ops, _ = sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	operatorutil.CheckMultiTargetTransferLeader(re, ops[0], operator.OpLeader, 1, []uint64{3})
+
+	// Fall back to the same zone if there is no other choice.
+	tc.AddLeaderRegion(1, 1, 2)
+	ops, _ = sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	operatorutil.CheckMultiTargetTransferLeader(re, ops[0], operator.OpLeader, 1, []uint64{2})
+}
+
+func TestEvictLeaderConfigVersion(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderStore(4, 0)
+	st := storage.NewStorageWithMemoryBackend()
+	sl, err := CreateScheduler(EvictLeaderType, oc, st, ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+	serve := func(method, path string, body any) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		re.NoError(err)
+		req := httptest.NewRequest(method, path, bytes.NewReader(data))
+		resp := httptest.NewRecorder()
+		sl.(*evictLeaderScheduler).ServeHTTP(resp, req)
+		return resp
+	}
+	listVersion := func() uint64 {
+		resp := serve(http.MethodGet, "/list", nil)
+		re.Equal(http.StatusOK, resp.Code)
+		conf := &evictLeaderSchedulerConfig{}
+		re.NoError(json.Unmarshal(resp.Body.Bytes(), conf))
+		return conf.Version
+	}
+
+	// Both writers read the same version, only the first write is accepted.
+	version := listVersion()
+	resp := serve(http.MethodPost, "/config", map[string]any{"store_id": 2, "version": version})
+	re.Equal(http.StatusOK, resp.Code)
+	resp = serve(http.MethodPost, "/config", map[string]any{"store_id": 3, "version": version})
+	re.Equal(http.StatusConflict, resp.Code)
+	re.ElementsMatch([]uint64{1, 2}, sl.(*evictLeaderScheduler).EvictStoreIDs())
+
+	// The version is persisted with the config.
+	re.Equal(version+1, listVersion())
+	cfgData, err := st.LoadSchedulerConfig(EvictLeaderName)
+	re.NoError(err)
+	persisted := &evictLeaderSchedulerConfig{}
+	re.NoError(DecodeConfig([]byte(cfgData), persisted))
+	re.Equal(version+1, persisted.Version)
+
+	// The retry with the latest version succeeds, and the write without the
+	// version is still accepted for the compatibility.
+	resp = serve(http.MethodPost, "/config", map[string]any{"store_id": 3, "version": version + 1})
+	re.Equal(http.StatusOK, resp.Code)
+	resp = serve(http.MethodDelete, "/delete/3", nil)
+	re.Equal(http.StatusOK, resp.Code)
+	re.Equal(version+3, listVersion())
+	resp = serve(http.MethodPost, "/config", map[string]any{"store_id": 3})
+	re.Equal(http.StatusOK, resp.Code)
+	re.Equal(version+4, listVersion())
+
+	// The version isn't increased if the update fails to persist.
+	conf := sl.(*evictLeaderScheduler).conf
+	conf.storage = &failedConfigStorage{ConfigStorage: st}
+	resp = serve(http.MethodPost, "/config", map[string]any{"store_id": 4, "version": version + 4})
+	re.Equal(http.StatusInternalServerError, resp.Code)
+	resp = serve(http.MethodPost, "/config", map[string]any{"store_id": 3, "version": version + 4})
+	re.Equal(http.StatusInternalServerError, resp.Code)
+	re.Equal(version+4, listVersion())
+	re.ElementsMatch([]uint64{1, 2, 3}, sl.(*evictLeaderScheduler).EvictStoreIDs())
+	re.True(tc.GetStore(4).AllowLeaderTransfer())
+	re.False(tc.GetStore(3).AllowLeaderTransfer())
+	conf.storage = st
+
+	// Only one of the concurrent writers with the same version is accepted.
+	var wg sync.WaitGroup
+	codes := make([]int, 4)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = serve(http.MethodPost, "/config", map[string]any{"store_id": 4, "version": version + 4}).Code
+		}(i)
+	}
+	wg.Wait()
+	re.ElementsMatch([]int{http.StatusOK, http.StatusConflict, http.StatusConflict, http.StatusConflict}, codes)
+	re.Equal(version+5, listVersion())
+}
+
+type failedConfigStorage struct {
+	endpoint.ConfigStorage
+}
+
+func (*failedConfigStorage) SaveSchedulerConfig(string, []byte) error {
+	return errors.New("fail to persist")
+}
+
+func TestExportImportSchedulerConfigs(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	st := storage.NewStorageWithMemoryBackend()
+	sc := NewController(context.Background(), tc, st, oc)
+	defer sc.Wait()
+	removeCb := func(string) error { return nil }
+	sl, err := CreateScheduler(EvictLeaderType, oc, st, ConfigSliceDecoder(EvictLeaderType, []string{"1"}), removeCb)
+	re.NoError(err)
+	re.NoError(sc.AddScheduler(sl, "1"))
+	data, err := json.Marshal(map[string]any{"store_id": 2})
+	re.NoError(err)
+	resp := httptest.NewRecorder()
+	sl.(*evictLeaderScheduler).ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/config", bytes.NewReader(data)))
+	re.Equal(http.StatusOK, resp.Code)
+
+	// The exported bundle is portable as JSON.
+	bundle, err := sc.ExportSchedulerConfigs()
+	re.NoError(err)
+	re.Contains(bundle, EvictLeaderName)
+	data, err = json.Marshal(bundle)
+	re.NoError(err)
+	imported := make(ConfigBundle)
+	re.NoError(json.Unmarshal(data, &imported))
+
+	re.NoError(sc.RemoveScheduler(EvictLeaderName))
+	// Wait for the scheduler to exit and resume the leader transfer.
+	sc.Wait()
+	cfgData, err := st.LoadSchedulerConfig(EvictLeaderName)
+	re.NoError(err)
+	re.Empty(cfgData)
+
+	// Nothing is applied if any config in the bundle is invalid.
+	invalid := ConfigBundle{
+		EvictLeaderName:   imported[EvictLeaderName],
+		BalanceRegionName: json.RawMessage(`{}`),
+		GrantLeaderName:   json.RawMessage(`{"store-id-ranges":"invalid"}`),
+	}
+	re.Error(sc.ImportSchedulerConfigs(invalid))
+	re.Error(sc.ImportSchedulerConfigs(ConfigBundle{"unknown-scheduler": json.RawMessage(`{}`)}))
+	_, configs, err := st.LoadAllSchedulerConfigs()
+	re.NoError(err)
+	re.Empty(configs)
+
+	re.NoError(sc.ImportSchedulerConfigs(imported))
+	cfgData, err = st.LoadSchedulerConfig(EvictLeaderName)
+	re.NoError(err)
+	sl, err = CreateScheduler(EvictLeaderType, oc, st, ConfigJSONDecoder([]byte(cfgData)), removeCb)
+	re.NoError(err)
+	re.ElementsMatch([]uint64{1, 2}, sl.(*evictLeaderScheduler).EvictStoreIDs())
+
+	// The running scheduler reloads the imported config.
+	re.NoError(sc.AddScheduler(sl, "1"))
+	resp = httptest.NewRecorder()
+	sl.(*evictLeaderScheduler).ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/delete/2", http.NoBody))
+	re.Equal(http.StatusOK, resp.Code)
+	re.ElementsMatch([]uint64{1}, sl.(*evictLeaderScheduler).EvictStoreIDs())
+	re.NoError(sc.ImportSchedulerConfigs(imported))
+	re.ElementsMatch([]uint64{1, 2}, sl.(*evictLeaderScheduler).EvictStoreIDs())
+	re.NoError(sc.RemoveScheduler(EvictLeaderName))
+}
+
+// failedSchedulerConfigStorage fails to save the config of the given scheduler.
+type failedSchedulerConfigStorage struct {
+	endpoint.ConfigStorage
+	name string
+}
+
+func (s *failedSchedulerConfigStorage) SaveSchedulerConfig(name string, data []byte) error {
+	if name == s.name {
+		return errors.New("fail to persist")
+	}
+	return s.ConfigStorage.SaveSchedulerConfig(name, data)
+}
+
+func TestImportSchedulerConfigsRollback(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	st := storage.NewStorageWithMemoryBackend()
+	sc := NewController(context.Background(), tc, &failedSchedulerConfigStorage{ConfigStorage: st, name: GrantLeaderName}, oc)
+	defer sc.Wait()
+	removeCb := func(string) error { return nil }
+	sl, err := CreateScheduler(EvictLeaderType, oc, st, ConfigSliceDecoder(EvictLeaderType, []string{"1"}), removeCb)
+	re.NoError(err)
+	re.NoError(sc.AddScheduler(sl, "1"))
+	oldData, err := st.LoadSchedulerConfig(EvictLeaderName)
+	re.NoError(err)
+
+	bundle := make(ConfigBundle)
+	for typ, name := range map[string]string{EvictLeaderType: EvictLeaderName, GrantLeaderType: GrantLeaderName} {
+		s, err := CreateScheduler(typ, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(typ, []string{"2"}), removeCb)
+		re.NoError(err)
+		data, err := s.EncodeConfig()
+		re.NoError(err)
+		bundle[name] = data
+	}
+	// The saved evict-leader config is rolled back if the grant-leader one fails to persist.
+	re.Error(sc.ImportSchedulerConfigs(bundle))
+	data, err := st.LoadSchedulerConfig(EvictLeaderName)
+	re.NoError(err)
+	re.Equal(oldData, data)
+	data, err = st.LoadSchedulerConfig(GrantLeaderName)
+	re.NoError(err)
+	re.Empty(data)
+	re.ElementsMatch([]uint64{1}, sl.(*evictLeaderScheduler).EvictStoreIDs())
+	re.NoError(sc.RemoveScheduler(EvictLeaderName))
+}
+
+func TestEvictLeaderWaitForPrepared(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest(false)
+	defer cancel()
+
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	tc.SetPrepared(false)
+	sc := NewController(context.Background(), tc, storage.NewStorageWithMemoryBackend(), oc)
+	defer sc.Wait()
+	sl, err := CreateScheduler(EvictLeaderType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+	re.NoError(sc.AddScheduler(sl, "1"))
+	defer func() {
+		re.NoError(sc.RemoveScheduler(EvictLeaderName))
+	}()
+
+	// No operator is created until the region information is prepared.
+	re.False(sc.GetScheduler(EvictLeaderName).AllowSchedule(false))
+	re.Equal("the region information is not prepared yet", sl.(*evictLeaderScheduler).conf.getDiagnoses()[1].Reason)
+	time.Sleep(100 * time.Millisecond)
+	re.Zero(oc.OperatorCount(operator.OpLeader))
+
+	tc.SetPrepared(true)
+	testutil.Eventually(re, func() bool {
+		return oc.OperatorCount(operator.OpLeader) > 0
+	})
+}
+
+func TestEvictLeaderBatchUpdateConfig(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	for id := uint64(1); id <= 4; id++ {
+		tc.AddLeaderStore(id, 0)
+	}
+	st := storage.NewStorageWithMemoryBackend()
+	sl, err := CreateScheduler(EvictLeaderType, oc, st, ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+	serve := func(body any) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		re.NoError(err)
+		req := httptest.NewRequest(http.MethodPost, "/config/batch", bytes.NewReader(data))
+		resp := httptest.NewRecorder()
+		sl.(*evictLeaderScheduler).ServeHTTP(resp, req)
+		return resp
+	}
+	checkUnchanged := func() {
+		re.ElementsMatch([]uint64{1}, sl.(*evictLeaderScheduler).EvictStoreIDs())
+		for id := uint64(2); id <= 4; id++ {
+			re.True(tc.GetStore(id).AllowLeaderTransfer())
+		}
+		cfgData, err := st.LoadSchedulerConfig(EvictLeaderName)
+		re.NoError(err)
+		persisted := &evictLeaderSchedulerConfig{}
+		re.NoError(DecodeConfig([]byte(cfgData), persisted))
+		re.Len(persisted.StoreIDWithRanges, 1)
+	}
+	re.NoError(sl.(*evictLeaderScheduler).conf.Persist())
+
+	// Store 5 doesn't exist, so none of the stores is applied.
+	resp := serve(map[string]any{"stores": map[string][]string{"2": {}, "3": {"a", "b"}, "5": {}}})
+	re.Equal(http.StatusBadRequest, resp.Code)
+	checkUnchanged()
+	// The invalid range fails the batch too.
+	resp = serve(map[string]any{"stores": map[string][]string{"2": {}, "3": {"%zz", "b"}}})
+	re.Equal(http.StatusBadRequest, resp.Code)
+	checkUnchanged()
+	// The stale version is rejected.
+	resp = serve(map[string]any{"stores": map[string][]string{"2": {}}, "version": 100})
+	re.Equal(http.StatusConflict, resp.Code)
+	checkUnchanged()
+
+	resp = serve(map[string]any{"stores": map[string][]string{"2": {}, "3": {"a", "b"}}})
+	re.Equal(http.StatusOK, resp.Code)
+	re.ElementsMatch([]uint64{1, 2, 3}, sl.(*evictLeaderScheduler).EvictStoreIDs())
+	re.False(tc.GetStore(2).AllowLeaderTransfer())
+	re.False(tc.GetStore(3).AllowLeaderTransfer())
+	re.True(tc.GetStore(4).AllowLeaderTransfer())
+	re.Equal([]core.KeyRange{core.NewKeyRange("a", "b")}, sl.(*evictLeaderScheduler).conf.getKeyRangesByID(3))
+	cfgData, err := st.LoadSchedulerConfig(EvictLeaderName)
+	re.NoError(err)
+	persisted := &evictLeaderSchedulerConfig{}
+	re.NoError(DecodeConfig([]byte(cfgData), persisted))
+	re.Len(persisted.StoreIDWithRanges, 3)
+}
+
+func TestEvictLeaderPauseAll(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	st := storage.NewStorageWithMemoryBackend()
+	sl, err := CreateScheduler(EvictLeaderType, oc, st, ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+	re.NoError(sl.PrepareConfig(tc))
+	serve := func(path string) {
+		req := httptest.NewRequest(http.MethodPost, path, http.NoBody)
+		resp := httptest.NewRecorder()
+		sl.(*evictLeaderScheduler).ServeHTTP(resp, req)
+		re.Equal(http.StatusOK, resp.Code)
+	}
+
+	serve("/config/pause-all")
+	re.False(sl.IsScheduleAllowed(tc))
+	// The store and its key ranges are kept.
+	re.ElementsMatch([]uint64{1}, sl.(*evictLeaderScheduler).EvictStoreIDs())
+	re.False(tc.GetStore(1).AllowLeaderTransfer())
+	// The paused state is persisted.
+	re.NoError(sl.ReloadConfig())
+	re.False(sl.IsScheduleAllowed(tc))
+	cfgData, err := st.LoadSchedulerConfig(EvictLeaderName)
+	re.NoError(err)
+	persisted := &evictLeaderSchedulerConfig{}
+	re.NoError(DecodeConfig([]byte(cfgData), persisted))
+	re.True(persisted.Paused)
+	re.Len(persisted.StoreIDWithRanges, 1)
+
+	serve("/config/resume-all")
+	re.True(sl.IsScheduleAllowed(tc))
+	ops, _ := sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	operatorutil.CheckMultiTargetTransferLeader(re, ops[0], operator.OpLeader, 1, []uint64{2})
+}
+
+func TestEvictLeaderRecheckTargetHealth(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	st := storage.NewStorageWithMemoryBackend()
+	sl, err := CreateScheduler(EvictLeaderType, oc, st, ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+	re.NoError(sl.PrepareConfig(tc))
+	serve := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/config/recheck-target-health", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		sl.(*evictLeaderScheduler).ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	ops, _ := sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	operatorutil.CheckMultiTargetTransferLeader(re, ops[0], operator.OpLeader, 1, []uint64{2, 3})
+
+	re.Equal(http.StatusBadRequest, serve(`{}`))
+	re.Equal(http.StatusOK, serve(`{"recheck-target-health": true}`))
+	// The picked target is re-checked, and the multi-target set is kept.
+	ops, _ = sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	re.Equal(2, ops[0].Len())
+	target := ops[0].Step(0).(operator.WaitPeerHealthy).ToStore
+	transfer := ops[0].Step(1).(operator.TransferLeader)
+	re.Equal(target, transfer.ToStore)
+	re.ElementsMatch([]uint64{2, 3}, transfer.ToStores)
+	// The switch is persisted.
+	re.NoError(sl.ReloadConfig())
+	re.True(sl.(*evictLeaderScheduler).conf.recheckTargetHealth())
+
+	re.Equal(http.StatusOK, serve(`{"recheck-target-health": false}`))
+	ops, _ = sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	operatorutil.CheckMultiTargetTransferLeader(re, ops[0], operator.OpLeader, 1, []uint64{2, 3})
+}
+
+func TestEvictLeaderStoreRateLimit(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest(false)
+	defer cancel()
+
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	for id := uint64(1); id <= 4; id++ {
+		tc.AddLeaderRegionWithRange(id, fmt.Sprintf("%d", id), fmt.Sprintf("%d", id+1), 1, 2)
+	}
+	st := storage.NewStorageWithMemoryBackend()
+	sl, err := CreateScheduler(EvictLeaderType, oc, st, ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+	re.NoError(sl.PrepareConfig(tc))
+	serve := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/config/store-rate-limit", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		sl.(*evictLeaderScheduler).ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	ops, _ := sl.Schedule(tc, false)
+	re.NotEmpty(ops)
+
+	re.Equal(http.StatusBadRequest, serve(`{}`))
+	re.Equal(http.StatusBadRequest, serve(`{"store-rate-limit": -1}`))
+	// The burst is 1 and it takes 100s to get a new token.
+	re.Equal(http.StatusOK, serve(`{"store-rate-limit": 0.01}`))
+	// The token is only taken once the operator is added, so the operators are
+	// created until then and the extra ones are rejected by the controller.
+	ops, _ = sl.Schedule(tc, false)
+	re.NotEmpty(ops)
+	for _, op := range ops {
+		operatorutil.CheckMultiTargetTransferLeader(re, op, operator.OpLeader, 1, []uint64{2})
+	}
+	oc.AddWaitingOperator(ops...)
+	re.Len(oc.GetOperators(), 1)
+	for i := 0; i < 10; i++ {
+		ops, _ = sl.Schedule(tc, false)
+		re.Empty(ops)
+	}
+	conf := sl.(*evictLeaderScheduler).conf
+	re.Contains(conf.getDiagnoses()[1].Reason, "rate limited")
+	// The limit is persisted.
+	re.NoError(sl.ReloadConfig())
+	re.Equal(0.01, conf.limiter.GetRate())
+
+	re.Equal(http.StatusOK, serve(`{"store-rate-limit": 0}`))
+	ops, _ = sl.Schedule(tc, false)
+	re.NotEmpty(ops)
+}
+
+func TestEvictLeaderEffectiveConfig(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	sl, err := CreateScheduler(EvictLeaderType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+	serve := func(method, path string, body any) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		re.NoError(err)
+		req := httptest.NewRequest(method, path, bytes.NewReader(data))
+		resp := httptest.NewRecorder()
+		sl.(*evictLeaderScheduler).ServeHTTP(resp, req)
+		re.Equal(http.StatusOK, resp.Code)
+		return resp
+	}
+	serve(http.MethodPost, "/config/batch", map[string]any{"stores": map[string][]string{"2": {"a", "c", "f", "g", "b", "d", "d", "e", "h", ""}}})
+
+	// The raw ranges are kept in the config.
+	listed := &evictLeaderSchedulerConfig{}
+	re.NoError(json.Unmarshal(serve(http.MethodGet, "/list", nil).Body.Bytes(), listed))
+	re.Len(listed.StoreIDWithRanges[2], 5)
+	// The overlapping and adjacent ranges are merged.
+	effective := make(map[uint64][]core.KeyRange)
+	re.NoError(json.Unmarshal(serve(http.MethodGet, "/config/effective", nil).Body.Bytes(), &effective))
+	re.Equal([]core.KeyRange{core.NewKeyRange("", "")}, effective[1])
+	re.Equal([]core.KeyRange{
+		core.NewKeyRange("a", "e"),
+		core.NewKeyRange("f", "g"),
+		core.NewKeyRange("h", ""),
+	}, effective[2])
+	re.Equal(effective[2], sl.(*evictLeaderScheduler).conf.getKeyRangesByID(2))
 }
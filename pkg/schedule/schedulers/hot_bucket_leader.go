@@ -0,0 +1,345 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/core/constant"
+	"github.com/tikv/pd/pkg/errs"
+	sche "github.com/tikv/pd/pkg/schedule/core"
+	"github.com/tikv/pd/pkg/schedule/filter"
+	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/schedule/plan"
+	"github.com/tikv/pd/pkg/statistics/buckets"
+	"github.com/tikv/pd/pkg/statistics/utils"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/utils/reflectutil"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"github.com/unrolled/render"
+)
+
+const (
+	// HotBucketLeaderName is the hot bucket leader scheduler name.
+	HotBucketLeaderName = "hot-bucket-leader-scheduler"
+	// HotBucketLeaderType is the hot bucket leader scheduler type.
+	HotBucketLeaderType = "hot-bucket-leader"
+	// defaultMaxHotRegionsPerStore is the default number of the hot bucket regions
+	// a store can lead before it is considered overloaded.
+	defaultMaxHotRegionsPerStore = 3
+)
+
+var (
+	// WithLabelValues is a heavy operation, define variable to avoid call it every time.
+	hotBucketLeaderDisableCounter        = schedulerCounter.WithLabelValues(HotBucketLeaderName, "bucket-disable")
+	hotBucketLeaderScheduleCounter       = schedulerCounter.WithLabelValues(HotBucketLeaderName, "schedule")
+	hotBucketLeaderNoOverloadedCounter   = schedulerCounter.WithLabelValues(HotBucketLeaderName, "no-overloaded-store")
+	hotBucketLeaderOperatorExistCounter  = schedulerCounter.WithLabelValues(HotBucketLeaderName, "operator-exist")
+	hotBucketLeaderUnhealthyCounter      = schedulerCounter.WithLabelValues(HotBucketLeaderName, "unhealthy-region")
+	hotBucketLeaderNoTargetStoreCounter  = schedulerCounter.WithLabelValues(HotBucketLeaderName, "no-target-store")
+	hotBucketLeaderCreateOperatorFailure = schedulerCounter.WithLabelValues(HotBucketLeaderName, "create-operator-fail")
+	hotBucketLeaderNewOperatorCounter    = schedulerCounter.WithLabelValues(HotBucketLeaderName, "new-operator")
+)
+
+func initHotBucketLeaderConfig() *hotBucketLeaderSchedulerConfig {
+	return &hotBucketLeaderSchedulerConfig{
+		Degree:                defaultHotDegree,
+		MaxHotRegionsPerStore: defaultMaxHotRegionsPerStore,
+	}
+}
+
+type hotBucketLeaderSchedulerConfig struct {
+	syncutil.RWMutex
+	storage endpoint.ConfigStorage
+	// Degree is the min hot degree of a bucket to be considered as hot.
+	Degree int `json:"degree"`
+	// MaxHotRegionsPerStore is the number of the hot bucket regions a store
+	// can lead, the store leading more of them is considered overloaded.
+	MaxHotRegionsPerStore int `json:"max-hot-regions-per-store"`
+}
+
+func (conf *hotBucketLeaderSchedulerConfig) Clone() *hotBucketLeaderSchedulerConfig {
+	conf.RLock()
+	defer conf.RUnlock()
+	return &hotBucketLeaderSchedulerConfig{
+		Degree:                conf.Degree,
+		MaxHotRegionsPerStore: conf.MaxHotRegionsPerStore,
+	}
+}
+
+func (conf *hotBucketLeaderSchedulerConfig) persistLocked() error {
+	data, err := EncodeConfig(conf)
+	if err != nil {
+		return err
+	}
+	return conf.storage.SaveSchedulerConfig(HotBucketLeaderName, data)
+}
+
+type hotBucketLeaderHandler struct {
+	conf *hotBucketLeaderSchedulerConfig
+	rd   *render.Render
+}
+
+func (h *hotBucketLeaderHandler) ListConfig(w http.ResponseWriter, _ *http.Request) {
+	conf := h.conf.Clone()
+	_ = h.rd.JSON(w, http.StatusOK, conf)
+}
+
+func (h *hotBucketLeaderHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
+	h.conf.Lock()
+	defer h.conf.Unlock()
+	oldc, _ := json.Marshal(h.conf)
+	data, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Decode into a copy, which replaces the current config only if it's valid.
+	newConf := &hotBucketLeaderSchedulerConfig{
+		Degree:                h.conf.Degree,
+		MaxHotRegionsPerStore: h.conf.MaxHotRegionsPerStore,
+	}
+	if err := json.Unmarshal(data, newConf); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if newConf.MaxHotRegionsPerStore <= 0 {
+		h.rd.JSON(w, http.StatusBadRequest, errs.ErrSchedulerConfig.FastGenByArgs("max-hot-regions-per-store").Error())
+		return
+	}
+	newc, _ := json.Marshal(newConf)
+	if !bytes.Equal(oldc, newc) {
+		oldDegree, oldMaxHotRegionsPerStore := h.conf.Degree, h.conf.MaxHotRegionsPerStore
+		h.conf.Degree, h.conf.MaxHotRegionsPerStore = newConf.Degree, newConf.MaxHotRegionsPerStore
+		if err := h.conf.persistLocked(); err != nil {
+			h.conf.Degree, h.conf.MaxHotRegionsPerStore = oldDegree, oldMaxHotRegionsPerStore
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.rd.Text(w, http.StatusOK, "Config is updated.")
+		return
+	}
+
+	m := make(map[string]any)
+	if err := json.Unmarshal(data, &m); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if reflectutil.FindSameFieldByJSON(h.conf, m) {
+		h.rd.Text(w, http.StatusOK, "Config is the same with origin, so do nothing.")
+		return
+	}
+
+	h.rd.Text(w, http.StatusBadRequest, "Config item is not found.")
+}
+
+func newHotBucketLeaderHandler(conf *hotBucketLeaderSchedulerConfig) http.Handler {
+	h := &hotBucketLeaderHandler{
+		conf: conf,
+		rd:   render.New(render.Options{IndentJSON: true}),
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/list", h.ListConfig).Methods(http.MethodGet)
+	router.HandleFunc("/config", h.UpdateConfig).Methods(http.MethodPost)
+	return router
+}
+
+// hotBucketLeaderScheduler transfers the leaders of the regions with hot read
+// buckets away from the stores leading too many of them, which is more
+// granular than balancing the hot regions.
+type hotBucketLeaderScheduler struct {
+	*BaseScheduler
+	conf    *hotBucketLeaderSchedulerConfig
+	handler http.Handler
+}
+
+func newHotBucketLeaderScheduler(opController *operator.Controller, conf *hotBucketLeaderSchedulerConfig) *hotBucketLeaderScheduler {
+	return &hotBucketLeaderScheduler{
+		BaseScheduler: NewBaseScheduler(opController),
+		conf:          conf,
+		handler:       newHotBucketLeaderHandler(conf),
+	}
+}
+
+// GetName returns the name of the hot bucket leader scheduler.
+func (*hotBucketLeaderScheduler) GetName() string {
+	return HotBucketLeaderName
+}
+
+// GetType returns the type of the hot bucket leader scheduler.
+func (*hotBucketLeaderScheduler) GetType() string {
+	return HotBucketLeaderType
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *hotBucketLeaderScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+func (s *hotBucketLeaderScheduler) ReloadConfig() error {
+	s.conf.Lock()
+	defer s.conf.Unlock()
+	cfgData, err := s.conf.storage.LoadSchedulerConfig(s.GetName())
+	if err != nil {
+		return err
+	}
+	if len(cfgData) == 0 {
+		return nil
+	}
+	newCfg := &hotBucketLeaderSchedulerConfig{}
+	if err := DecodeConfig([]byte(cfgData), newCfg); err != nil {
+		return err
+	}
+	s.conf.Degree = newCfg.Degree
+	s.conf.MaxHotRegionsPerStore = newCfg.MaxHotRegionsPerStore
+	return nil
+}
+
+func (s *hotBucketLeaderScheduler) IsScheduleAllowed(cluster sche.SchedulerCluster) bool {
+	if !cluster.GetStoreConfig().IsEnableRegionBucket() {
+		hotBucketLeaderDisableCounter.Inc()
+		return false
+	}
+	allowed := s.OpController.OperatorCount(operator.OpLeader) < cluster.GetSchedulerConfig().GetLeaderScheduleLimit()
+	if !allowed {
+		operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpLeader.String()).Inc()
+	}
+	return allowed
+}
+
+// hotBucketRegion is a region with hot read buckets.
+type hotBucketRegion struct {
+	region *core.RegionInfo
+	// readBytes is the sum of the read bytes of the hot buckets.
+	readBytes uint64
+}
+
+// Schedule returns an operator to transfer the leader of the hottest region
+// away from the most overloaded store.
+func (s *hotBucketLeaderScheduler) Schedule(cluster sche.SchedulerCluster, _ bool) ([]*operator.Operator, []plan.Plan) {
+	hotBucketLeaderScheduleCounter.Inc()
+	conf := s.conf.Clone()
+	return s.transferHotBucketLeader(cluster, conf, cluster.BucketsStats(conf.Degree)), nil
+}
+
+func (s *hotBucketLeaderScheduler) transferHotBucketLeader(
+	cluster sche.SchedulerCluster,
+	conf *hotBucketLeaderSchedulerConfig,
+	hotBuckets map[uint64][]*buckets.BucketStat,
+) []*operator.Operator {
+	storeHotRegions := make(map[uint64][]*hotBucketRegion)
+	for regionID, stats := range hotBuckets {
+		region := cluster.GetRegion(regionID)
+		if region == nil || region.GetLeader() == nil {
+			continue
+		}
+		var readBytes uint64
+		for _, stat := range stats {
+			if len(stat.Loads) > int(utils.RegionReadBytes) {
+				readBytes += stat.Loads[utils.RegionReadBytes]
+			}
+		}
+		// Only the read hot buckets can be balanced by transferring the leader.
+		if readBytes == 0 {
+			continue
+		}
+		storeID := region.GetLeader().GetStoreId()
+		storeHotRegions[storeID] = append(storeHotRegions[storeID], &hotBucketRegion{region: region, readBytes: readBytes})
+	}
+
+	// Try the most overloaded store first.
+	sources := make([]uint64, 0, len(storeHotRegions))
+	for storeID, regions := range storeHotRegions {
+		if len(regions) > conf.MaxHotRegionsPerStore {
+			sources = append(sources, storeID)
+		}
+	}
+	if len(sources) == 0 {
+		hotBucketLeaderNoOverloadedCounter.Inc()
+		return nil
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if len(storeHotRegions[sources[i]]) != len(storeHotRegions[sources[j]]) {
+			return len(storeHotRegions[sources[i]]) > len(storeHotRegions[sources[j]])
+		}
+		return sources[i] < sources[j]
+	})
+
+	regionFilters := []filter.RegionFilter{filter.NewRegionPendingFilter(), filter.NewRegionDownFilter()}
+	for _, sourceID := range sources {
+		source := cluster.GetStore(sourceID)
+		if source == nil {
+			continue
+		}
+		regions := storeHotRegions[sourceID]
+		// Try the hottest region first.
+		sort.Slice(regions, func(i, j int) bool {
+			if regions[i].readBytes != regions[j].readBytes {
+				return regions[i].readBytes > regions[j].readBytes
+			}
+			return regions[i].region.GetID() < regions[j].region.GetID()
+		})
+		for _, hotRegion := range regions {
+			region := hotRegion.region
+			if s.OpController.GetOperator(region.GetID()) != nil {
+				hotBucketLeaderOperatorExistCounter.Inc()
+				continue
+			}
+			if filter.SelectOneRegion([]*core.RegionInfo{region}, nil, regionFilters...) == nil {
+				hotBucketLeaderUnhealthyCounter.Inc()
+				continue
+			}
+			// Only the stores leading at least two hot bucket regions less than
+			// the source store can be the target, so the transfer won't ping-pong.
+			followers := make([]*core.StoreInfo, 0)
+			for _, store := range cluster.GetFollowerStores(region) {
+				if len(storeHotRegions[store.GetID()])+1 < len(regions) {
+					followers = append(followers, store)
+				}
+			}
+			filters := []filter.Filter{&filter.StoreStateFilter{ActionScope: s.GetName(), TransferLeader: true, OperatorLevel: constant.High}}
+			if leaderFilter := filter.NewPlacementLeaderSafeguard(s.GetName(), cluster.GetSchedulerConfig(), cluster.GetBasicCluster(), cluster.GetRuleManager(), region, source, false /*allowMoveLeader*/); leaderFilter != nil {
+				filters = append(filters, leaderFilter)
+			}
+			target := filter.NewCandidates(followers).
+				FilterTarget(cluster.GetSchedulerConfig(), nil, nil, filters...).
+				PickTheTopStore(func(a, b *core.StoreInfo) int {
+					return len(storeHotRegions[a.GetID()]) - len(storeHotRegions[b.GetID()])
+				}, true)
+			if target == nil {
+				hotBucketLeaderNoTargetStoreCounter.Inc()
+				continue
+			}
+			op, err := operator.CreateTransferLeaderOperator(HotBucketLeaderType, cluster, region, target.GetID(), []uint64{}, operator.OpLeader)
+			if err != nil {
+				hotBucketLeaderCreateOperatorFailure.Inc()
+				continue
+			}
+			hotBucketLeaderNewOperatorCounter.Inc()
+			op.SetAdditionalInfo("read-bytes", strconv.FormatUint(hotRegion.readBytes, 10))
+			return []*operator.Operator{op}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/schedule/placement"
+	"github.com/tikv/pd/pkg/statistics/buckets"
+	"github.com/tikv/pd/pkg/storage"
+	"github.com/tikv/pd/pkg/utils/operatorutil"
+)
+
+func TestHotBucketLeader(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest(false)
+	defer cancel()
+	for storeID := uint64(1); storeID <= 3; storeID++ {
+		tc.AddLeaderStore(storeID, 0)
+	}
+	// Store 1 leads 4 regions with hot read buckets and store 2 leads one.
+	for regionID := uint64(1); regionID <= 4; regionID++ {
+		tc.AddLeaderRegion(regionID, 1, 2, 3)
+	}
+	tc.AddLeaderRegion(5, 2, 1, 3)
+	readBytes := map[uint64]uint64{1: 100, 2: 200, 3: 300, 4: 100, 5: 100}
+	hotBuckets := make(map[uint64][]*buckets.BucketStat)
+	for regionID, bytes := range readBytes {
+		hotBuckets[regionID] = []*buckets.BucketStat{
+			{RegionID: regionID, HotDegree: 3, Loads: []uint64{bytes, 0, 0, 0, 0, 0}},
+			{RegionID: regionID, HotDegree: 3, Loads: []uint64{bytes, 0, 0, 0, 0, 0}},
+		}
+	}
+
+	sche, err := CreateScheduler(HotBucketLeaderType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(HotBucketLeaderType, nil))
+	re.NoError(err)
+	scheduler := sche.(*hotBucketLeaderScheduler)
+	tc.SetRegionBucketEnabled(false)
+	re.False(scheduler.IsScheduleAllowed(tc))
+	tc.SetRegionBucketEnabled(true)
+	re.True(scheduler.IsScheduleAllowed(tc))
+
+	// Store 1 is not overloaded if it can lead 4 hot bucket regions.
+	conf := scheduler.conf.Clone()
+	conf.MaxHotRegionsPerStore = 4
+	re.Empty(scheduler.transferHotBucketLeader(tc, conf, hotBuckets))
+
+	// The leader of the hottest region should be transferred to the coolest follower.
+	conf.MaxHotRegionsPerStore = 2
+	ops := scheduler.transferHotBucketLeader(tc, conf, hotBuckets)
+	re.Len(ops, 1)
+	re.Equal(uint64(3), ops[0].RegionID())
+	operatorutil.CheckTransferLeader(re, ops[0], operator.OpLeader, 1, 3)
+
+	// The next hottest region is picked if the hottest one has an operator.
+	oc.AddWaitingOperator(ops[0])
+	ops = scheduler.transferHotBucketLeader(tc, conf, hotBuckets)
+	re.Len(ops, 1)
+	re.Equal(uint64(2), ops[0].RegionID())
+	operatorutil.CheckTransferLeader(re, ops[0], operator.OpLeader, 1, 3)
+
+	// The regions with pending or down peers are skipped.
+	region := tc.GetRegion(2)
+	tc.PutRegion(region.Clone(core.WithPendingPeers([]*metapb.Peer{region.GetStorePeer(2)})))
+	region = tc.GetRegion(1)
+	tc.PutRegion(region.Clone(core.WithDownPeers([]*pdpb.PeerStats{{Peer: region.GetStorePeer(3), DownSeconds: 3600}})))
+	ops = scheduler.transferHotBucketLeader(tc, conf, hotBuckets)
+	re.Len(ops, 1)
+	re.Equal(uint64(4), ops[0].RegionID())
+	operatorutil.CheckTransferLeader(re, ops[0], operator.OpLeader, 1, 3)
+
+	// The target must be allowed to be the leader by the placement rules.
+	tc.SetEnablePlacementRules(true)
+	for storeID, zone := range map[uint64]string{1: "z1", 2: "z1", 3: "z2"} {
+		tc.SetStoreLabel(storeID, map[string]string{"zone": zone})
+	}
+	re.NoError(tc.SetRule(&placement.Rule{
+		GroupID: placement.DefaultGroupID,
+		ID:      placement.DefaultRuleID,
+		Role:    placement.Leader,
+		Count:   1,
+		LabelConstraints: []placement.LabelConstraint{
+			{Key: "zone", Op: placement.In, Values: []string{"z1"}},
+		},
+	}))
+	re.NoError(tc.SetRule(&placement.Rule{
+		GroupID: placement.DefaultGroupID,
+		ID:      "voter",
+		Index:   1,
+		Role:    placement.Voter,
+		Count:   2,
+	}))
+	ops = scheduler.transferHotBucketLeader(tc, conf, hotBuckets)
+	re.Len(ops, 1)
+	re.Equal(uint64(4), ops[0].RegionID())
+	operatorutil.CheckTransferLeader(re, ops[0], operator.OpLeader, 1, 2)
+
+	// The write hot buckets can't be balanced by transferring the leader.
+	for _, stats := range hotBuckets {
+		for _, stat := range stats {
+			stat.Loads = []uint64{0, 0, 0, 100, 0, 0}
+		}
+	}
+	re.Empty(scheduler.transferHotBucketLeader(tc, conf, hotBuckets))
+}
+
+func TestHotBucketLeaderUpdateConfig(t *testing.T) {
+	re := require.New(t)
+	cancel, _, _, oc := prepareSchedulersTest()
+	defer cancel()
+	sche, err := CreateScheduler(HotBucketLeaderType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(HotBucketLeaderType, nil))
+	re.NoError(err)
+	scheduler := sche.(*hotBucketLeaderScheduler)
+	maxHotRegionsPerStore := scheduler.conf.MaxHotRegionsPerStore
+
+	for _, body := range []string{`{"max-hot-regions-per-store":0}`, `{"max-hot-regions-per-store":-1}`} {
+		req := httptest.NewRequest(http.MethodPost, "/config", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		scheduler.ServeHTTP(resp, req)
+		re.Equal(http.StatusBadRequest, resp.Code)
+		re.Equal(maxHotRegionsPerStore, scheduler.conf.MaxHotRegionsPerStore)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/config", bytes.NewBufferString(`{"max-hot-regions-per-store":5}`))
+	resp := httptest.NewRecorder()
+	scheduler.ServeHTTP(resp, req)
+	re.Equal(http.StatusOK, resp.Code)
+	re.Equal(5, scheduler.conf.MaxHotRegionsPerStore)
+}
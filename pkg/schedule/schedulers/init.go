@@ -148,6 +148,7 @@ func schedulersRegister() {
 		}
 		conf.cluster = opController.GetCluster()
 		conf.removeSchedulerCb = removeSchedulerCb[0]
+		conf.limiter = operator.NewStoreRateLimiter(conf.StoreRateLimit)
 		return newEvictLeaderScheduler(opController, conf), nil
 	})
 
@@ -167,6 +168,21 @@ func schedulersRegister() {
 		return newEvictSlowStoreScheduler(opController, conf), nil
 	})
 
+	// evict hot cpu store
+	RegisterSliceDecoderBuilder(EvictHotCPUStoreType, func([]string) ConfigDecoder {
+		return func(any) error {
+			return nil
+		}
+	})
+
+	RegisterScheduler(EvictHotCPUStoreType, func(opController *operator.Controller, storage endpoint.ConfigStorage, decoder ConfigDecoder, _ ...func(string) error) (Scheduler, error) {
+		conf := initEvictHotCPUStoreSchedulerConfig(storage)
+		if err := decoder(conf); err != nil {
+			return nil, err
+		}
+		return newEvictHotCPUStoreScheduler(opController, conf), nil
+	})
+
 	// grant hot region
 	RegisterSliceDecoderBuilder(GrantHotRegionType, func(args []string) ConfigDecoder {
 		return func(v any) error {
@@ -449,6 +465,22 @@ func schedulersRegister() {
 		return newSplitBucketScheduler(opController, conf), nil
 	})
 
+	// hot bucket leader
+	RegisterSliceDecoderBuilder(HotBucketLeaderType, func([]string) ConfigDecoder {
+		return func(any) error {
+			return nil
+		}
+	})
+
+	RegisterScheduler(HotBucketLeaderType, func(opController *operator.Controller, storage endpoint.ConfigStorage, decoder ConfigDecoder, _ ...func(string) error) (Scheduler, error) {
+		conf := initHotBucketLeaderConfig()
+		if err := decoder(conf); err != nil {
+			return nil, err
+		}
+		conf.storage = storage
+		return newHotBucketLeaderScheduler(opController, conf), nil
+	})
+
 	// transfer witness leader
 	RegisterSliceDecoderBuilder(TransferWitnessLeaderType, func([]string) ConfigDecoder {
 		return func(any) error {
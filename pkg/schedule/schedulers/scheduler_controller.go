@@ -16,6 +16,7 @@ package schedulers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
@@ -29,6 +30,7 @@ import (
 	"github.com/tikv/pd/pkg/schedule/labeler"
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/schedule/plan"
+	"github.com/tikv/pd/pkg/storage"
 	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/utils/logutil"
 	"github.com/tikv/pd/pkg/utils/syncutil"
@@ -417,6 +419,107 @@ func (c *Controller) GetAllSchedulerConfigs() ([]string, []string, error) {
 	return c.storage.LoadAllSchedulerConfigs()
 }
 
+// ConfigBundle is the portable bundle of the scheduler configs, which maps
+// the scheduler name to its encoded config.
+type ConfigBundle map[string]json.RawMessage
+
+// ExportSchedulerConfigs exports the persisted configs of all the schedulers,
+// including the plugin schedulers, as a single bundle.
+func (c *Controller) ExportSchedulerConfigs() (ConfigBundle, error) {
+	names, configs, err := c.storage.LoadAllSchedulerConfigs()
+	if err != nil {
+		return nil, err
+	}
+	bundle := make(ConfigBundle, len(names))
+	for i, name := range names {
+		bundle[name] = json.RawMessage(configs[i])
+	}
+	return bundle, nil
+}
+
+// ImportSchedulerConfigs persists the configs in the bundle and reloads the
+// running schedulers. Every config is validated by the decoder of its scheduler
+// type first, nothing is applied if any of them is invalid. If saving or
+// reloading fails, the saved configs are rolled back and the running schedulers
+// are reloaded with the old ones.
+// NOTE: the configs of the schedulers which are not running are only persisted,
+// the schedulers are not added. Adding such a scheduler later creates it from
+// its arguments, which replaces the imported config.
+func (c *Controller) ImportSchedulerConfigs(bundle ConfigBundle) error {
+	for name, data := range bundle {
+		if err := validateSchedulerConfig(c.opController, name, data); err != nil {
+			return err
+		}
+	}
+	c.RLock()
+	defer c.RUnlock()
+	oldConfigs := make(map[string]string, len(bundle))
+	for name := range bundle {
+		data, err := c.storage.LoadSchedulerConfig(name)
+		if err != nil {
+			return err
+		}
+		oldConfigs[name] = data
+	}
+	saved := make([]string, 0, len(bundle))
+	err := func() error {
+		for name, data := range bundle {
+			if err := c.storage.SaveSchedulerConfig(name, data); err != nil {
+				return err
+			}
+			saved = append(saved, name)
+		}
+		for name := range bundle {
+			if s, ok := c.schedulers[name]; ok {
+				if err := s.ReloadConfig(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		c.rollbackSchedulerConfigs(saved, oldConfigs)
+	}
+	return err
+}
+
+// rollbackSchedulerConfigs restores the old configs of the given schedulers
+// and reloads the running ones. The caller must hold the read lock.
+func (c *Controller) rollbackSchedulerConfigs(names []string, oldConfigs map[string]string) {
+	for _, name := range names {
+		var err error
+		if data := oldConfigs[name]; len(data) == 0 {
+			err = c.storage.RemoveSchedulerConfig(name)
+		} else {
+			err = c.storage.SaveSchedulerConfig(name, []byte(data))
+		}
+		if err != nil {
+			log.Error("can not roll back the scheduler config", zap.String("scheduler-name", name), errs.ZapError(err))
+			continue
+		}
+		if s, ok := c.schedulers[name]; ok {
+			if err := s.ReloadConfig(); err != nil {
+				log.Error("can not reload the rolled back scheduler config", zap.String("scheduler-name", name), errs.ZapError(err))
+			}
+		}
+	}
+}
+
+// validateSchedulerConfig checks the config by creating a scheduler from it
+// against a throwaway storage.
+func validateSchedulerConfig(oc *operator.Controller, name string, data []byte) error {
+	typ := FindSchedulerTypeByName(name)
+	if typ == "" {
+		return errs.ErrSchedulerCreateFuncNotRegistered.FastGenByArgs(name)
+	}
+	_, err := CreateScheduler(typ, oc, storage.NewStorageWithMemoryBackend(), ConfigJSONDecoder(data), func(string) error { return nil })
+	if err != nil {
+		return errs.ErrSchedulerConfig.FastGenByArgs(fmt.Sprintf("of %s: %v", name, err))
+	}
+	return nil
+}
+
 // ScheduleController is used to manage a scheduler.
 type ScheduleController struct {
 	Scheduler
@@ -15,7 +15,9 @@
 package schedulers
 
 import (
+	"bytes"
 	"net/url"
+	"sort"
 	"strconv"
 	"time"
 
@@ -27,6 +29,7 @@ import (
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/schedule/placement"
 	"github.com/tikv/pd/pkg/schedule/plan"
+	"github.com/tikv/pd/pkg/slice"
 	"github.com/tikv/pd/pkg/statistics"
 	"go.uber.org/zap"
 )
@@ -233,6 +236,34 @@ func getKeyRanges(args []string) ([]core.KeyRange, error) {
 	return ranges, nil
 }
 
+// mergeKeyRanges sorts the key ranges by the start key and merges the overlapping
+// or adjacent ones, the empty end key means the range is unbounded.
+func mergeKeyRanges(ranges []core.KeyRange) []core.KeyRange {
+	if len(ranges) <= 1 {
+		return ranges
+	}
+	sorted := slice.Clone(ranges)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].StartKey, sorted[j].StartKey) < 0
+	})
+	merged := []core.KeyRange{sorted[0]}
+	for _, r := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		// The unbounded range covers all the rest.
+		if len(last.EndKey) == 0 {
+			break
+		}
+		if bytes.Compare(r.StartKey, last.EndKey) > 0 {
+			merged = append(merged, r)
+			continue
+		}
+		if len(r.EndKey) == 0 || bytes.Compare(r.EndKey, last.EndKey) > 0 {
+			last.EndKey = r.EndKey
+		}
+	}
+	return merged
+}
+
 type pendingInfluence struct {
 	op                *operator.Operator
 	froms             []uint64
@@ -392,7 +423,8 @@ func (q *retryQuota) GC(keepStores []*core.StoreInfo) {
 }
 
 // pauseAndResumeLeaderTransfer checks the old and new store IDs, and pause or resume the leader transfer.
-func pauseAndResumeLeaderTransfer[T any](cluster *core.BasicCluster, old, new map[uint64]T) {
+// The reason is recorded for the newly paused stores.
+func pauseAndResumeLeaderTransfer[T any](cluster *core.BasicCluster, reason string, old, new map[uint64]T) {
 	for id := range old {
 		if _, ok := new[id]; ok {
 			continue
@@ -403,6 +435,6 @@ func pauseAndResumeLeaderTransfer[T any](cluster *core.BasicCluster, old, new ma
 		if _, ok := old[id]; ok {
 			continue
 		}
-		cluster.PauseLeaderTransfer(id)
+		cluster.PauseLeaderTransferWithReason(id, reason)
 	}
 }
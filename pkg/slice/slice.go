@@ -14,6 +14,8 @@
 
 package slice
 
+import "math/rand"
+
 // AnyOf returns true if any element in the slice matches the predict func.
 func AnyOf[T any](s []T, p func(int) bool) bool {
 	for i := 0; i < len(s); i++ {
@@ -47,6 +49,16 @@ func Contains[T comparable](slice []T, value T) bool {
 	return false
 }
 
+// ContainsFunc returns true if any element in the slice satisfies the predicate.
+func ContainsFunc[T any](slice []T, p func(T) bool) bool {
+	for _, v := range slice {
+		if p(v) {
+			return true
+		}
+	}
+	return false
+}
+
 // Remove removes the value from the slice.
 func Remove[T comparable](slice []T, value T) []T {
 	i, j := 0, 0
@@ -58,3 +70,110 @@ func Remove[T comparable](slice []T, value T) []T {
 	}
 	return slice[:j]
 }
+
+// MaxBy returns the element with the largest key and true, or the zero value
+// and false if the slice is empty. If several elements share the largest key,
+// the first one wins.
+func MaxBy[T any](s []T, key func(T) float64) (T, bool) {
+	var res T
+	if len(s) == 0 {
+		return res, false
+	}
+	res = s[0]
+	maxKey := key(res)
+	for _, v := range s[1:] {
+		if k := key(v); k > maxKey {
+			res, maxKey = v, k
+		}
+	}
+	return res, true
+}
+
+// MinBy returns the element with the smallest key and true, or the zero value
+// and false if the slice is empty. If several elements share the smallest key,
+// the first one wins.
+func MinBy[T any](s []T, key func(T) float64) (T, bool) {
+	return MaxBy(s, func(v T) float64 { return -key(v) })
+}
+
+// SumBy returns the sum of the keys of all elements in the slice.
+func SumBy[T any](s []T, key func(T) float64) float64 {
+	var sum float64
+	for _, v := range s {
+		sum += key(v)
+	}
+	return sum
+}
+
+// AverageBy returns the average of the keys of all elements in the slice,
+// or 0 if the slice is empty.
+func AverageBy[T any](s []T, key func(T) float64) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+	return SumBy(s, key) / float64(len(s))
+}
+
+// DiffUnordered returns the elements only in a and the elements only in b,
+// ignoring the order. Duplicates are counted, e.g. the extra copy of a value
+// which appears twice in a but once in b is returned in onlyInA. The returned
+// elements keep their order in the input slices.
+func DiffUnordered[T comparable](a, b []T) (onlyInA, onlyInB []T) {
+	counts := make(map[T]int, len(b))
+	for _, v := range b {
+		counts[v]++
+	}
+	for _, v := range a {
+		if counts[v] > 0 {
+			counts[v]--
+			continue
+		}
+		onlyInA = append(onlyInA, v)
+	}
+	// The remaining counts are the elements only in b.
+	for _, v := range b {
+		if counts[v] > 0 {
+			counts[v]--
+			onlyInB = append(onlyInB, v)
+		}
+	}
+	return onlyInA, onlyInB
+}
+
+// Clone returns a shallow copy of the slice, the nil slice is kept as nil.
+func Clone[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+// CloneFunc returns a copy of the slice whose elements are copied by the given
+// func, which can be used to deep copy the elements. The nil slice is kept as nil.
+func CloneFunc[T any](s []T, f func(T) T) []T {
+	if s == nil {
+		return nil
+	}
+	res := make([]T, len(s))
+	for i, v := range s {
+		res[i] = f(v)
+	}
+	return res
+}
+
+// EqualAsMultiset returns true if the slices contain the same elements with the
+// same counts, ignoring the order. Unlike a membership check, [a a b] and
+// [a b b] are not equal.
+func EqualAsMultiset[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	onlyInA, _ := DiffUnordered(a, b)
+	return len(onlyInA) == 0
+}
+
+// Shuffle reorders the elements of the slice randomly in place with the given
+// random source, so the permutation is reproducible with a seeded source.
+func Shuffle[T any](s []T, r *rand.Rand) {
+	r.Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+}
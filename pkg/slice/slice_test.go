@@ -15,8 +15,10 @@
 package slice_test
 
 import (
+	"math/rand"
 	"testing"
 
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/pd/pkg/slice"
 )
@@ -58,6 +60,17 @@ func TestSliceContains(t *testing.T) {
 	re.False(slice.Contains(is, int64(4)))
 }
 
+func TestSliceContainsFunc(t *testing.T) {
+	re := require.New(t)
+	peers := []*metapb.Peer{
+		{Id: 1, StoreId: 10},
+		{Id: 2, StoreId: 20},
+	}
+	re.True(slice.ContainsFunc(peers, func(p *metapb.Peer) bool { return p.GetStoreId() == 20 }))
+	re.False(slice.ContainsFunc(peers, func(p *metapb.Peer) bool { return p.GetStoreId() == 30 }))
+	re.False(slice.ContainsFunc([]*metapb.Peer{}, func(*metapb.Peer) bool { return true }))
+}
+
 func TestSliceRemoveGenericTypes(t *testing.T) {
 	re := require.New(t)
 	ss := []string{"a", "b", "c"}
@@ -94,3 +107,137 @@ func TestSliceRemove(t *testing.T) {
 	is = slice.Remove(is, 1)
 	re.Equal([]int64{}, is)
 }
+
+func TestSliceMaxMinBy(t *testing.T) {
+	re := require.New(t)
+	type store struct {
+		id          uint64
+		leaderCount int
+	}
+	leaderCount := func(s store) float64 { return float64(s.leaderCount) }
+
+	_, ok := slice.MaxBy([]store{}, leaderCount)
+	re.False(ok)
+	_, ok = slice.MinBy(nil, leaderCount)
+	re.False(ok)
+
+	stores := []store{{1, 10}, {2, 30}, {3, 5}, {4, 30}, {5, 5}}
+	s, ok := slice.MaxBy(stores, leaderCount)
+	re.True(ok)
+	// The first one wins on ties.
+	re.Equal(uint64(2), s.id)
+	s, ok = slice.MinBy(stores, leaderCount)
+	re.True(ok)
+	re.Equal(uint64(3), s.id)
+}
+
+func TestSliceSumAverageBy(t *testing.T) {
+	re := require.New(t)
+	identity := func(v float64) float64 { return v }
+	testCases := []struct {
+		s       []float64
+		sum     float64
+		average float64
+	}{
+		{nil, 0, 0},
+		{[]float64{}, 0, 0},
+		{[]float64{4}, 4, 4},
+		{[]float64{1, 2, 3, 4, 5.5}, 15.5, 3.1},
+	}
+	for _, testCase := range testCases {
+		re.InDelta(testCase.sum, slice.SumBy(testCase.s, identity), 1e-9)
+		re.InDelta(testCase.average, slice.AverageBy(testCase.s, identity), 1e-9)
+	}
+}
+
+func TestSliceDiffUnordered(t *testing.T) {
+	re := require.New(t)
+	testCases := []struct {
+		a, b             []uint64
+		onlyInA, onlyInB []uint64
+	}{
+		{nil, nil, nil, nil},
+		{[]uint64{1, 2, 3}, []uint64{3, 1, 2}, nil, nil},
+		{[]uint64{1, 2, 3}, nil, []uint64{1, 2, 3}, nil},
+		{nil, []uint64{3, 2}, nil, []uint64{3, 2}},
+		{[]uint64{4, 1, 2, 5}, []uint64{2, 6, 1, 3}, []uint64{4, 5}, []uint64{6, 3}},
+		// The duplicates are counted.
+		{[]uint64{1, 1, 2}, []uint64{2, 1}, []uint64{1}, nil},
+		{[]uint64{1, 2}, []uint64{2, 2, 2, 1}, nil, []uint64{2, 2}},
+	}
+	for _, testCase := range testCases {
+		onlyInA, onlyInB := slice.DiffUnordered(testCase.a, testCase.b)
+		re.Equal(testCase.onlyInA, onlyInA)
+		re.Equal(testCase.onlyInB, onlyInB)
+	}
+
+	onlyInA, onlyInB := slice.DiffUnordered([]string{"a", "b"}, []string{"b", "c"})
+	re.Equal([]string{"a"}, onlyInA)
+	re.Equal([]string{"c"}, onlyInB)
+}
+
+func TestSliceClone(t *testing.T) {
+	re := require.New(t)
+	re.Nil(slice.Clone[int](nil))
+	re.Empty(slice.Clone([]int{}))
+	re.NotNil(slice.Clone([]int{}))
+
+	s := []int{1, 2, 3}
+	cloned := slice.Clone(s)
+	re.Equal(s, cloned)
+	cloned[0] = 4
+	re.Equal([]int{1, 2, 3}, s)
+
+	re.Nil(slice.CloneFunc[[]byte](nil, nil))
+	bytesSlice := [][]byte{[]byte("a"), []byte("b")}
+	shallow := slice.Clone(bytesSlice)
+	deep := slice.CloneFunc(bytesSlice, func(b []byte) []byte {
+		return append([]byte(nil), b...)
+	})
+	re.Equal(bytesSlice, deep)
+	// The shallow copy shares the elements while the deep copy does not.
+	bytesSlice[0][0] = 'c'
+	re.Equal([]byte("c"), shallow[0])
+	re.Equal([]byte("a"), deep[0])
+}
+
+func TestSliceEqualAsMultiset(t *testing.T) {
+	re := require.New(t)
+	testCases := []struct {
+		a, b  []string
+		equal bool
+	}{
+		{nil, nil, true},
+		{nil, []string{}, true},
+		{[]string{"a", "b"}, []string{"b", "a"}, true},
+		{[]string{"a", "a", "b"}, []string{"a", "b", "a"}, true},
+		// The duplicate counts must match.
+		{[]string{"a", "a", "b"}, []string{"a", "b", "b"}, false},
+		{[]string{"a", "b"}, []string{"a", "b", "b"}, false},
+		{[]string{"a"}, []string{"b"}, false},
+	}
+	for _, testCase := range testCases {
+		re.Equal(testCase.equal, slice.EqualAsMultiset(testCase.a, testCase.b))
+		re.Equal(testCase.equal, slice.EqualAsMultiset(testCase.b, testCase.a))
+	}
+}
+
+func TestSliceShuffle(t *testing.T) {
+	re := require.New(t)
+	origin := make([]int, 100)
+	for i := range origin {
+		origin[i] = i
+	}
+	s1, s2 := slice.Clone(origin), slice.Clone(origin)
+	// The same seed produces the same permutation.
+	slice.Shuffle(s1, rand.New(rand.NewSource(1)))
+	slice.Shuffle(s2, rand.New(rand.NewSource(1)))
+	re.Equal(s1, s2)
+	re.NotEqual(origin, s1)
+	// All elements are preserved.
+	re.True(slice.EqualAsMultiset(origin, s1))
+
+	var empty []int
+	slice.Shuffle(empty, rand.New(rand.NewSource(1)))
+	re.Empty(empty)
+}
@@ -16,6 +16,7 @@ package endpoint
 
 import (
 	"context"
+	"errors"
 	"math"
 	"strconv"
 	"time"
@@ -23,9 +24,12 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/encryption"
 	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
 )
 
 // MetaStorage defines the storage operations on the PD cluster meta info.
@@ -43,7 +47,7 @@ type MetaStorage interface {
 // RegionStorage defines the storage operations on the Region meta info.
 type RegionStorage interface {
 	LoadRegion(regionID uint64, region *metapb.Region) (ok bool, err error)
-	LoadRegions(ctx context.Context, f func(region *core.RegionInfo) []*core.RegionInfo) error
+	LoadRegions(ctx context.Context, f func(region *core.RegionInfo) []*core.RegionInfo, opts ...LoadRegionsOption) error
 	SaveRegion(region *metapb.Region) error
 	DeleteRegion(region *metapb.Region) error
 	Flush() error
@@ -57,6 +61,11 @@ const (
 	MaxKVRangeLimit = 10000
 	// MinKVRangeLimit is the min limit of the number of keys in a range.
 	MinKVRangeLimit = 100
+
+	// minParallelDecodeRegions is the min number of regions to decode in parallel.
+	minParallelDecodeRegions = 1000
+	// regionDecodeConcurrency is the max number of goroutines to decode regions.
+	regionDecodeConcurrency = 4
 )
 
 // LoadMeta loads cluster meta from the storage. This method will only
@@ -165,15 +174,65 @@ func (se *StorageEndpoint) LoadRegion(regionID uint64, region *metapb.Region) (o
 	return true, err
 }
 
+// RetryPolicy decides whether to retry the region loading after it fails with a
+// transient error. It's given the number of the retries made so far and the error,
+// and returns the backoff before the next retry, or false to give up.
+type RetryPolicy func(retries int, err error) (time.Duration, bool)
+
+// NewBackoffRetryPolicy returns a RetryPolicy which retries at most maxRetries times,
+// with the backoff starting from baseBackoff and doubled for each retry up to maxBackoff.
+func NewBackoffRetryPolicy(maxRetries int, baseBackoff, maxBackoff time.Duration) RetryPolicy {
+	return func(retries int, _ error) (time.Duration, bool) {
+		if retries >= maxRetries {
+			return 0, false
+		}
+		backoff := baseBackoff
+		for i := 0; i < retries && backoff < maxBackoff; i++ {
+			backoff *= 2
+		}
+		return min(backoff, maxBackoff), true
+	}
+}
+
+// LoadRegionsOption configures the region loading.
+type LoadRegionsOption func(*loadRegionsOptions)
+
+type loadRegionsOptions struct {
+	retryPolicy RetryPolicy
+}
+
+// WithRetryPolicy makes the region loading retry on the transient errors with the
+// given policy, rather than failing outright. The context cancellation and timeout
+// are never retried.
+func WithRetryPolicy(policy RetryPolicy) LoadRegionsOption {
+	return func(opts *loadRegionsOptions) {
+		opts.retryPolicy = policy
+	}
+}
+
 // LoadRegions loads all regions from storage to RegionsInfo.
-func (se *StorageEndpoint) LoadRegions(ctx context.Context, f func(region *core.RegionInfo) []*core.RegionInfo) error {
-	nextID := uint64(0)
+func (se *StorageEndpoint) LoadRegions(ctx context.Context, f func(region *core.RegionInfo) []*core.RegionInfo, opts ...LoadRegionsOption) error {
+	_, err := se.LoadRegionsFrom(ctx, 0, f, opts...)
+	return err
+}
+
+// LoadRegionsFrom loads the regions whose ID is not less than the given checkpoint from storage
+// to RegionsInfo. It returns the checkpoint to resume from, which is the ID next to the last
+// loaded region, so that an interrupted loading, e.g. by a timeout, could be continued by
+// passing the returned checkpoint to the next call without reprocessing the loaded regions.
+func (se *StorageEndpoint) LoadRegionsFrom(ctx context.Context, checkpoint uint64, f func(region *core.RegionInfo) []*core.RegionInfo, opts ...LoadRegionsOption) (uint64, error) {
+	options := &loadRegionsOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	nextID := checkpoint
 	endKey := RegionPath(math.MaxUint64)
 
 	// Since the region key may be very long, using a larger rangeLimit will cause
 	// the message packet to exceed the grpc message size limit (4MB). Here we use
 	// a variable rangeLimit to work around.
 	rangeLimit := MaxKVRangeLimit
+	retries := 0
 	for {
 		failpoint.Inject("slowLoadRegion", func() {
 			rangeLimit = 1
@@ -181,40 +240,102 @@ func (se *StorageEndpoint) LoadRegions(ctx context.Context, f func(region *core.
 		})
 		startKey := RegionPath(nextID)
 		_, res, err := se.LoadRange(startKey, endKey, rangeLimit)
+		failpoint.Inject("loadRegionsTransientError", func() {
+			res, err = nil, errors.New("transient error of loading regions")
+		})
 		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nextID, err
+			}
 			if rangeLimit /= 2; rangeLimit >= MinKVRangeLimit {
 				continue
 			}
-			return err
+			if options.retryPolicy == nil {
+				return nextID, err
+			}
+			backoff, ok := options.retryPolicy(retries, err)
+			if !ok {
+				return nextID, err
+			}
+			retries++
+			log.Warn("failed to load regions, retry later",
+				zap.Uint64("next-region-id", nextID), zap.Int("retries", retries),
+				zap.Duration("backoff", backoff), zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return nextID, ctx.Err()
+			case <-time.After(backoff):
+			}
+			rangeLimit = MinKVRangeLimit
+			continue
 		}
+		retried := retries > 0
+		retries = 0
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nextID, ctx.Err()
 		default:
 		}
 
-		for _, r := range res {
-			region := &metapb.Region{}
-			if err := region.Unmarshal([]byte(r)); err != nil {
-				return errs.ErrProtoUnmarshal.Wrap(err).GenWithStackByArgs()
-			}
-			if err = encryption.DecryptRegion(region, se.encryptionKeyManager); err != nil {
-				return err
+		regions, decodeErrs := se.decodeRegions(res)
+		for i, region := range regions {
+			if decodeErrs[i] != nil {
+				return nextID, decodeErrs[i]
 			}
 
 			nextID = region.GetId() + 1
 			overlaps := f(core.NewRegionInfo(region, nil, core.SetSource(core.Storage)))
 			for _, item := range overlaps {
 				if err := se.DeleteRegion(item.GetMeta()); err != nil {
-					return err
+					return nextID, err
 				}
 			}
 		}
 
 		if len(res) < rangeLimit {
-			return nil
+			return nextID, nil
+		}
+		// The range limit is shrunk to the min one for the retries, restore
+		// it once the transient error is gone.
+		if retried {
+			rangeLimit = MaxKVRangeLimit
+		}
+	}
+}
+
+// decodeRegions unmarshals and decrypts the regions in parallel. The decoding
+// error of each region is returned at the same index, so that the regions
+// before the first failed one could still be loaded in order.
+func (se *StorageEndpoint) decodeRegions(values []string) ([]*metapb.Region, []error) {
+	regions := make([]*metapb.Region, len(values))
+	decodeErrs := make([]error, len(values))
+	decode := func(start, end int) {
+		for i := start; i < end; i++ {
+			region := &metapb.Region{}
+			if err := region.Unmarshal([]byte(values[i])); err != nil {
+				decodeErrs[i] = errs.ErrProtoUnmarshal.Wrap(err).GenWithStackByArgs()
+				continue
+			}
+			decodeErrs[i] = encryption.DecryptRegion(region, se.encryptionKeyManager)
+			regions[i] = region
 		}
 	}
+	if len(values) < minParallelDecodeRegions {
+		decode(0, len(values))
+		return regions, decodeErrs
+	}
+	chunkSize := (len(values) + regionDecodeConcurrency - 1) / regionDecodeConcurrency
+	// The tasks never fail, so the pool is just used to bound the concurrency here.
+	pool := syncutil.NewWorkerPool(context.Background(), regionDecodeConcurrency)
+	for start := 0; start < len(values); start += chunkSize {
+		start, end := start, min(start+chunkSize, len(values))
+		_ = pool.Submit(func(context.Context) error {
+			decode(start, end)
+			return nil
+		})
+	}
+	_ = pool.Wait()
+	return regions, decodeErrs
 }
 
 // SaveRegion saves one region to storage.
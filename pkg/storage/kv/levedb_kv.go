@@ -19,6 +19,8 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/utils/syncutil"
@@ -31,7 +33,12 @@ type LevelDBKV struct {
 
 // NewLevelDBKV is used to store regions information.
 func NewLevelDBKV(path string) (*LevelDBKV, error) {
-	db, err := leveldb.OpenFile(path, nil)
+	return NewLevelDBKVWithOptions(path, nil)
+}
+
+// NewLevelDBKVWithOptions is the same as `NewLevelDBKV`, but opens the LevelDB with the given options.
+func NewLevelDBKVWithOptions(path string, o *opt.Options) (*LevelDBKV, error) {
+	db, err := leveldb.OpenFile(path, o)
 	if err != nil {
 		return nil, errs.ErrLevelDBOpen.Wrap(err).GenWithStackByCause()
 	}
@@ -52,7 +59,10 @@ func (kv *LevelDBKV) Load(key string) (string, error) {
 
 // LoadRange gets a range of value for a given key range.
 func (kv *LevelDBKV) LoadRange(startKey, endKey string, limit int) ([]string, []string, error) {
-	iter := kv.NewIterator(&util.Range{Start: []byte(startKey), Limit: []byte(endKey)}, nil)
+	return loadRange(kv.NewIterator(&util.Range{Start: []byte(startKey), Limit: []byte(endKey)}, nil), limit)
+}
+
+func loadRange(iter iterator.Iterator, limit int) ([]string, []string, error) {
 	keys := make([]string, 0, limit)
 	values := make([]string, 0, limit)
 	count := 0
@@ -78,6 +88,44 @@ func (kv *LevelDBKV) Remove(key string) error {
 	return errors.WithStack(kv.Delete([]byte(key), nil))
 }
 
+// LevelDBSnapshotKV reads from a snapshot of the LevelDB, which is a consistent
+// view unaffected by the later writes, while the writes still go to the LevelDB.
+type LevelDBSnapshotKV struct {
+	*LevelDBKV
+	snapshot *leveldb.Snapshot
+}
+
+// NewSnapshotKV takes a snapshot of the LevelDB, it should be released after use.
+func (kv *LevelDBKV) NewSnapshotKV() (*LevelDBSnapshotKV, error) {
+	snapshot, err := kv.GetSnapshot()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &LevelDBSnapshotKV{LevelDBKV: kv, snapshot: snapshot}, nil
+}
+
+// Load gets a value for a given key from the snapshot.
+func (kv *LevelDBSnapshotKV) Load(key string) (string, error) {
+	v, err := kv.snapshot.Get([]byte(key), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return "", nil
+		}
+		return "", errors.WithStack(err)
+	}
+	return string(v), err
+}
+
+// LoadRange gets a range of value for a given key range from the snapshot.
+func (kv *LevelDBSnapshotKV) LoadRange(startKey, endKey string, limit int) ([]string, []string, error) {
+	return loadRange(kv.snapshot.NewIterator(&util.Range{Start: []byte(startKey), Limit: []byte(endKey)}, nil), limit)
+}
+
+// Release releases the snapshot.
+func (kv *LevelDBSnapshotKV) Release() {
+	kv.snapshot.Release()
+}
+
 // levelDBTxn implements kv.Txn.
 // It utilizes leveldb.Batch to batch user operations to an atomic execution unit.
 type levelDBTxn struct {
@@ -18,9 +18,14 @@ import (
 	"context"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/encryption"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/storage/endpoint"
@@ -38,6 +43,42 @@ const (
 	defaultDirtyFlushTick = time.Second
 )
 
+// LevelDBOptions is used to tune the LevelDB which stores the region meta information.
+// A zero field means using the LevelDB default, which keeps the same behavior as before.
+type LevelDBOptions struct {
+	// WriteBufferSize is the size in bytes of the memtable before it is flushed
+	// into a sorted table file. 4MiB by default.
+	WriteBufferSize int
+	// BlockCacheSize is the capacity in bytes of the block cache. 8MiB by default.
+	BlockCacheSize int
+	// CompactionTableSize is the size limit in bytes of a table file generated by
+	// the compaction. 2MiB by default. LevelDB always compacts in a single goroutine,
+	// so a larger table is the way to let a write-heavy workload compact faster.
+	CompactionTableSize int
+}
+
+// Validate checks whether the options are valid.
+func (o *LevelDBOptions) Validate() error {
+	if o.WriteBufferSize < 0 {
+		return errors.Errorf("invalid leveldb write buffer size %d", o.WriteBufferSize)
+	}
+	if o.BlockCacheSize < 0 {
+		return errors.Errorf("invalid leveldb block cache size %d", o.BlockCacheSize)
+	}
+	if o.CompactionTableSize < 0 {
+		return errors.Errorf("invalid leveldb compaction table size %d", o.CompactionTableSize)
+	}
+	return nil
+}
+
+func (o *LevelDBOptions) toLevelDBOptions() *opt.Options {
+	return &opt.Options{
+		WriteBuffer:         o.WriteBufferSize,
+		BlockCacheCapacity:  o.BlockCacheSize,
+		CompactionTableSize: o.CompactionTableSize,
+	}
+}
+
 // levelDBBackend is a storage backend that stores data in LevelDB,
 // which is mainly used to store the PD Region meta information.
 type levelDBBackend struct {
@@ -51,6 +92,10 @@ type levelDBBackend struct {
 	flushTime time.Time
 	ctx       context.Context
 	cancel    context.CancelFunc
+	// changes are the region changes waiting for the batch to be flushed,
+	// they will be published to the hub once they become durable.
+	changes []RegionChange
+	hub     *regionChangeHub
 }
 
 // newLevelDBBackend is used to create a new LevelDB backend.
@@ -59,7 +104,20 @@ func newLevelDBBackend(
 	filePath string,
 	ekm *encryption.Manager,
 ) (*levelDBBackend, error) {
-	levelDB, err := kv.NewLevelDBKV(filePath)
+	return newLevelDBBackendWithOptions(ctx, filePath, ekm, &LevelDBOptions{})
+}
+
+// newLevelDBBackendWithOptions is used to create a new LevelDB backend with the given options.
+func newLevelDBBackendWithOptions(
+	ctx context.Context,
+	filePath string,
+	ekm *encryption.Manager,
+	opts *LevelDBOptions,
+) (*levelDBBackend, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	levelDB, err := kv.NewLevelDBKVWithOptions(filePath, opts.toLevelDBOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +128,7 @@ func newLevelDBBackend(
 		flushRate:       defaultFlushRate,
 		batch:           make(map[string][]byte, defaultBatchSize),
 		flushTime:       time.Now().Add(defaultFlushRate),
+		hub:             newRegionChangeHub(),
 	}
 	lb.ctx, lb.cancel = context.WithCancel(ctx)
 	go lb.backgroundFlush()
@@ -106,12 +165,43 @@ func (lb *levelDBBackend) backgroundFlush() {
 	}
 }
 
+// LoadRegions loads all regions from a snapshot of the LevelDB, see `LoadRegionsFrom`.
+func (lb *levelDBBackend) LoadRegions(ctx context.Context, f func(region *core.RegionInfo) []*core.RegionInfo, opts ...endpoint.LoadRegionsOption) error {
+	_, err := lb.LoadRegionsFrom(ctx, 0, f, opts...)
+	return err
+}
+
+// LoadRegionsFrom loads the regions from a LevelDB snapshot taken at the beginning,
+// so that the regions saved or deleted concurrently, e.g. by the batch flush, can't
+// make the ranges loaded one by one see an inconsistent view.
+func (lb *levelDBBackend) LoadRegionsFrom(ctx context.Context, checkpoint uint64, f func(region *core.RegionInfo) []*core.RegionInfo, opts ...endpoint.LoadRegionsOption) (uint64, error) {
+	snapshot, err := lb.Base.(*kv.LevelDBKV).NewSnapshotKV()
+	if err != nil {
+		return checkpoint, err
+	}
+	defer snapshot.Release()
+	return endpoint.NewStorageEndpoint(snapshot, lb.ekm).LoadRegionsFrom(ctx, checkpoint, f, opts...)
+}
+
 // SaveIntoBatch saves the key-value pair into the batch cache, and it will
 // only be saved to the underlying storage when the `Flush` method is
 // called or the cache is full.
 func (lb *levelDBBackend) SaveIntoBatch(key string, value []byte) error {
 	lb.mu.Lock()
 	defer lb.mu.Unlock()
+	return lb.saveIntoBatchLocked(key, value)
+}
+
+// saveRegionIntoBatch is the same as `SaveIntoBatch`, but also records the
+// region change to be published once it is flushed.
+func (lb *levelDBBackend) saveRegionIntoBatch(region *metapb.Region, value []byte) error {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.recordChangeLocked(region, false)
+	return lb.saveIntoBatchLocked(endpoint.RegionPath(region.GetId()), value)
+}
+
+func (lb *levelDBBackend) saveIntoBatchLocked(key string, value []byte) error {
 	if lb.cacheSize < lb.batchSize-1 {
 		lb.batch[key] = value
 		lb.cacheSize++
@@ -123,6 +213,50 @@ func (lb *levelDBBackend) SaveIntoBatch(key string, value []byte) error {
 	return lb.flushLocked()
 }
 
+// saveRegions saves the regions into the underlying storage immediately,
+// together with the pending ones in the batch cache, in a single write.
+// The values must be in the same order as the regions.
+func (lb *levelDBBackend) saveRegions(regions []*metapb.Region, values [][]byte) error {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	for i, region := range regions {
+		lb.recordChangeLocked(region, false)
+		lb.batch[endpoint.RegionPath(region.GetId())] = values[i]
+	}
+	return lb.flushLocked()
+}
+
+// removeRegion removes the region from the underlying storage immediately.
+func (lb *levelDBBackend) removeRegion(region *metapb.Region) error {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	if err := lb.Remove(endpoint.RegionPath(region.GetId())); err != nil {
+		return err
+	}
+	lb.recordChangeLocked(region, true)
+	// Publish the deletion right away unless there are earlier changes
+	// still waiting in the batch, to keep the changes in the write order.
+	if len(lb.batch) == 0 {
+		lb.publishChangesLocked()
+	}
+	return nil
+}
+
+func (lb *levelDBBackend) recordChangeLocked(region *metapb.Region, deleted bool) {
+	if !lb.hub.hasSubscribers() {
+		return
+	}
+	lb.changes = append(lb.changes, RegionChange{
+		Region:  proto.Clone(region).(*metapb.Region),
+		Deleted: deleted,
+	})
+}
+
+func (lb *levelDBBackend) publishChangesLocked() {
+	lb.hub.publish(lb.changes)
+	lb.changes = nil
+}
+
 // Flush saves the batch cache to the underlying storage.
 func (lb *levelDBBackend) Flush() error {
 	lb.mu.Lock()
@@ -136,10 +270,20 @@ func (lb *levelDBBackend) flushLocked() error {
 	}
 	lb.cacheSize = 0
 	lb.batch = make(map[string][]byte, lb.batchSize)
+	lb.publishChangesLocked()
 	return nil
 }
 
 func (lb *levelDBBackend) saveBatchLocked() error {
+	// The background flush keeps flushing the empty batch after the flush time,
+	// skip recording them to not drown the real flushes.
+	if len(lb.batch) > 0 {
+		start := time.Now()
+		defer func() {
+			levelDBFlushDuration.Observe(time.Since(start).Seconds())
+		}()
+		levelDBFlushBatchSize.Observe(float64(len(lb.batch)))
+	}
 	batch := new(leveldb.Batch)
 	for key, value := range lb.batch {
 		batch.Put([]byte(key), value)
@@ -157,6 +301,7 @@ func (lb *levelDBBackend) Close() error {
 		log.Error("meet error before closing the leveldb storage", errs.ZapError(err))
 	}
 	lb.cancel()
+	lb.hub.close()
 	err = lb.Base.(*kv.LevelDBKV).Close()
 	if err != nil {
 		return errs.ErrLevelDBClose.Wrap(err).GenWithStackByArgs()
@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/pd/pkg/utils/testutil"
 )
@@ -121,3 +122,48 @@ func TestLevelDBBackend(t *testing.T) {
 	err = backend.Close()
 	re.NoError(err)
 }
+
+func TestLevelDBBackendFlushMetrics(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	backend, err := newLevelDBBackend(ctx, t.TempDir(), nil)
+	re.NoError(err)
+	defer backend.Close()
+
+	registry := prometheus.NewPedanticRegistry()
+	re.NoError(registry.Register(levelDBFlushDuration))
+	re.NoError(registry.Register(levelDBFlushBatchSize))
+	// observed returns the sample count and sum of the histograms of the flush duration and batch size.
+	observed := func() (durationCount, batchCount uint64, batchSum float64) {
+		mfs, err := registry.Gather()
+		re.NoError(err)
+		re.Len(mfs, 2)
+		for _, mf := range mfs {
+			histogram := mf.GetMetric()[0].GetHistogram()
+			switch mf.GetName() {
+			case "pd_region_storage_flush_duration_seconds":
+				durationCount = histogram.GetSampleCount()
+			case "pd_region_storage_flush_batch_size":
+				batchCount, batchSum = histogram.GetSampleCount(), histogram.GetSampleSum()
+			}
+		}
+		return
+	}
+
+	durationCount, batchCount, batchSum := observed()
+	for i := 0; i < 3; i++ {
+		re.NoError(backend.SaveIntoBatch(fmt.Sprintf("k%d", i), []byte("v")))
+	}
+	re.NoError(backend.Flush())
+	newDurationCount, newBatchCount, newBatchSum := observed()
+	re.Equal(durationCount+1, newDurationCount)
+	re.Equal(batchCount+1, newBatchCount)
+	re.Equal(batchSum+3, newBatchSum)
+	// Flushing the empty batch is not recorded.
+	re.NoError(backend.Flush())
+	durationCount, batchCount, batchSum = observed()
+	re.Equal(newDurationCount, durationCount)
+	re.Equal(newBatchCount, batchCount)
+	re.Equal(newBatchSum, batchSum)
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	levelDBFlushDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "region_storage",
+			Name:      "flush_duration_seconds",
+			Help:      "Bucketed histogram of the duration (s) of flushing the batch into the local region storage.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 13),
+		})
+
+	levelDBFlushBatchSize = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "region_storage",
+			Name:      "flush_batch_size",
+			Help:      "Bucketed histogram of the number of the keys flushed into the local region storage in a batch.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		})
+)
+
+func init() {
+	prometheus.MustRegister(levelDBFlushDuration)
+	prometheus.MustRegister(levelDBFlushBatchSize)
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"sync"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+)
+
+// regionChangeChanSize is the buffer size of each subscriber channel.
+const regionChangeChanSize = 1024
+
+// RegionChange is a region meta change which has been persisted in the RegionStorage.
+type RegionChange struct {
+	Region *metapb.Region
+	// Deleted indicates that the region is deleted from the storage.
+	Deleted bool
+}
+
+// regionChangeHub dispatches the region changes to the subscribers.
+// To never block the write path, a change will be dropped for the
+// subscriber whose channel buffer is full.
+type regionChangeHub struct {
+	mu          syncutil.RWMutex
+	nextID      uint64
+	subscribers map[uint64]chan RegionChange
+	closed      bool
+}
+
+func newRegionChangeHub() *regionChangeHub {
+	return &regionChangeHub{subscribers: make(map[uint64]chan RegionChange)}
+}
+
+func (h *regionChangeHub) subscribe() (<-chan RegionChange, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch := make(chan RegionChange, regionChangeChanSize)
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	id := h.nextID
+	h.nextID++
+	h.subscribers[id] = ch
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if _, ok := h.subscribers[id]; ok {
+				delete(h.subscribers, id)
+				close(ch)
+			}
+		})
+	}
+}
+
+func (h *regionChangeHub) hasSubscribers() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers) > 0
+}
+
+func (h *regionChangeHub) publish(changes []RegionChange) {
+	if len(changes) == 0 {
+		return
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for id, ch := range h.subscribers {
+	publishLoop:
+		for i, change := range changes {
+			select {
+			case ch <- change:
+			default:
+				log.Warn("region change subscriber is too slow, drop the changes",
+					zap.Uint64("subscriber-id", id), zap.Int("dropped", len(changes)-i))
+				break publishLoop
+			}
+		}
+	}
+}
+
+func (h *regionChangeHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id, ch := range h.subscribers {
+		delete(h.subscribers, id)
+		close(ch)
+	}
+	h.closed = true
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+)
+
+// RegionSource is the authoritative region view to compare the storage against,
+// e.g. the in-memory `core.BasicCluster`.
+type RegionSource interface {
+	GetRegion(regionID uint64) *core.RegionInfo
+	GetRegions() []*core.RegionInfo
+}
+
+// RegionConsistencyReport is the result of comparing the regions in the storage
+// with the ones in the cluster. All the region IDs are sorted in ascending order.
+type RegionConsistencyReport struct {
+	// OnlyInStorage contains the regions which are persisted but not in the cluster.
+	OnlyInStorage []uint64 `json:"only-in-storage"`
+	// OnlyInCluster contains the regions which are in the cluster but not persisted.
+	OnlyInCluster []uint64 `json:"only-in-cluster"`
+	// EpochMismatch contains the regions whose persisted epoch differs from the cluster.
+	EpochMismatch []uint64 `json:"epoch-mismatch"`
+}
+
+// IsConsistent returns true if no difference is found.
+func (r *RegionConsistencyReport) IsConsistent() bool {
+	return len(r.OnlyInStorage) == 0 && len(r.OnlyInCluster) == 0 && len(r.EpochMismatch) == 0
+}
+
+// CompareWithCluster loads all regions from the storage and compares them with the cluster
+// to detect the metadata drift. The storage will not be modified during the comparison.
+func CompareWithCluster(ctx context.Context, s endpoint.RegionStorage, cluster RegionSource) (*RegionConsistencyReport, error) {
+	report := &RegionConsistencyReport{}
+	stored := make(map[uint64]struct{})
+	err := s.LoadRegions(ctx, func(region *core.RegionInfo) []*core.RegionInfo {
+		stored[region.GetID()] = struct{}{}
+		origin := cluster.GetRegion(region.GetID())
+		switch {
+		case origin == nil:
+			report.OnlyInStorage = append(report.OnlyInStorage, region.GetID())
+		case origin.GetRegionEpoch().GetVersion() != region.GetRegionEpoch().GetVersion() ||
+			origin.GetRegionEpoch().GetConfVer() != region.GetRegionEpoch().GetConfVer():
+			report.EpochMismatch = append(report.EpochMismatch, region.GetID())
+		}
+		// Return no overlaps to keep the storage untouched.
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, region := range cluster.GetRegions() {
+		if _, ok := stored[region.GetID()]; !ok {
+			report.OnlyInCluster = append(report.OnlyInCluster, region.GetID())
+		}
+	}
+	for _, ids := range [][]uint64{report.OnlyInStorage, report.OnlyInCluster, report.EpochMismatch} {
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+	return report, nil
+}
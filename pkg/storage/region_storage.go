@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/encryption"
@@ -45,27 +46,76 @@ func (s *RegionStorage) LoadRegion(regionID uint64, region *metapb.Region) (bool
 }
 
 // LoadRegions implements the `endpoint.RegionStorage` interface.
-func (s *RegionStorage) LoadRegions(ctx context.Context, f func(region *core.RegionInfo) []*core.RegionInfo) error {
-	return s.backend.LoadRegions(ctx, f)
+func (s *RegionStorage) LoadRegions(ctx context.Context, f func(region *core.RegionInfo) []*core.RegionInfo, opts ...endpoint.LoadRegionsOption) error {
+	return s.backend.LoadRegions(ctx, f, opts...)
+}
+
+// LoadRegionsFrom loads the regions from the given checkpoint and returns the checkpoint to resume from.
+func (s *RegionStorage) LoadRegionsFrom(ctx context.Context, checkpoint uint64, f func(region *core.RegionInfo) []*core.RegionInfo, opts ...endpoint.LoadRegionsOption) (uint64, error) {
+	return s.backend.LoadRegionsFrom(ctx, checkpoint, f, opts...)
 }
 
 // SaveRegion implements the `endpoint.RegionStorage` interface.
 // Instead of saving the region directly, it will encrypt the region and then save it in batch.
 func (s *RegionStorage) SaveRegion(region *metapb.Region) error {
-	encryptedRegion, err := encryption.EncryptRegion(region, s.backend.ekm)
+	value, err := s.encodeRegion(region)
 	if err != nil {
 		return err
 	}
+	return s.backend.saveRegionIntoBatch(region, value)
+}
+
+// SaveRegions saves the regions into the underlying storage in a single LevelDB batch
+// without waiting for the next flush. If a region fails to be encoded, the regions
+// before it are still saved and the returned error tells how many have been saved.
+func (s *RegionStorage) SaveRegions(regions []*metapb.Region) error {
+	var (
+		saved     int
+		encodeErr error
+		values    = make([][]byte, 0, len(regions))
+	)
+	for _, region := range regions {
+		value, err := s.encodeRegion(region)
+		if err != nil {
+			encodeErr = err
+			break
+		}
+		values = append(values, value)
+		saved++
+	}
+	if err := s.backend.saveRegions(regions[:saved], values); err != nil {
+		return err
+	}
+	if encodeErr != nil {
+		return errors.Annotatef(encodeErr, "only %d of %d regions are saved", saved, len(regions))
+	}
+	return nil
+}
+
+func (s *RegionStorage) encodeRegion(region *metapb.Region) ([]byte, error) {
+	encryptedRegion, err := encryption.EncryptRegion(region, s.backend.ekm)
+	if err != nil {
+		return nil, err
+	}
 	value, err := proto.Marshal(encryptedRegion)
 	if err != nil {
-		return errs.ErrProtoMarshal.Wrap(err).GenWithStackByCause()
+		return nil, errs.ErrProtoMarshal.Wrap(err).GenWithStackByCause()
 	}
-	return s.backend.SaveIntoBatch(endpoint.RegionPath(region.GetId()), value)
+	return value, nil
 }
 
 // DeleteRegion implements the `endpoint.RegionStorage` interface.
 func (s *RegionStorage) DeleteRegion(region *metapb.Region) error {
-	return s.backend.Remove((endpoint.RegionPath(region.GetId())))
+	return s.backend.removeRegion(region)
+}
+
+// Subscribe returns a channel to receive the region changes in the write order
+// once they are durable, and a function to cancel the subscription. A slow
+// subscriber never blocks the write path: when its channel buffer is full,
+// the changes will be dropped for it. The channel is closed after the
+// subscription is canceled or the storage is closed.
+func (s *RegionStorage) Subscribe() (<-chan RegionChange, func()) {
+	return s.backend.hub.subscribe()
 }
 
 // Flush implements the `endpoint.RegionStorage` interface.
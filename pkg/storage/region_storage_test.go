@@ -18,6 +18,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/pd/pkg/core"
@@ -93,3 +94,180 @@ func TestRegionStorage(t *testing.T) {
 	err = regionStorage.Close()
 	re.NoError(err)
 }
+
+func TestRegionStorageSaveRegions(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	regionStorage, err := NewRegionStorageWithLevelDBBackend(ctx, t.TempDir(), nil)
+	re.NoError(err)
+	defer regionStorage.Close()
+
+	// A pending region should be saved together with the batch.
+	re.NoError(regionStorage.SaveRegion(newTestRegionMeta(0)))
+	n := 50
+	expected := make([]*metapb.Region, 0, n)
+	for i := 1; i <= n; i++ {
+		expected = append(expected, newTestRegionMeta(uint64(i)))
+	}
+	re.NoError(regionStorage.SaveRegions(expected))
+
+	// No flush is needed to load the regions back.
+	regions := make([]*metapb.Region, 0, n+1)
+	err = regionStorage.LoadRegions(ctx, func(region *core.RegionInfo) []*core.RegionInfo {
+		regions = append(regions, region.GetMeta())
+		return nil
+	})
+	re.NoError(err)
+	re.Len(regions, n+1)
+	re.Equal(newTestRegionMeta(0), regions[0])
+	re.Equal(expected, regions[1:])
+}
+
+func TestRegionStorageWithLevelDBOptions(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := NewRegionStorageWithLevelDBOptions(ctx, t.TempDir(), nil, &LevelDBOptions{WriteBufferSize: -1})
+	re.Error(err)
+
+	regionStorage, err := NewRegionStorageWithLevelDBOptions(ctx, t.TempDir(), nil, &LevelDBOptions{
+		WriteBufferSize:     64 * 1024 * 1024,
+		BlockCacheSize:      128 * 1024 * 1024,
+		CompactionTableSize: 8 * 1024 * 1024,
+	})
+	re.NoError(err)
+	defer regionStorage.Close()
+	region := newTestRegionMeta(1)
+	re.NoError(regionStorage.SaveRegion(region))
+	re.NoError(regionStorage.Flush())
+	newRegion := &metapb.Region{}
+	ok, err := regionStorage.LoadRegion(region.GetId(), newRegion)
+	re.True(ok)
+	re.NoError(err)
+	re.Equal(region, newRegion)
+}
+
+func TestRegionStorageSubscribe(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	regionStorage, err := NewRegionStorageWithLevelDBBackend(ctx, t.TempDir(), nil)
+	re.NoError(err)
+	changes, unsubscribe := regionStorage.Subscribe()
+
+	// The batched changes are not published until they are flushed.
+	re.NoError(regionStorage.SaveRegion(newTestRegionMeta(1)))
+	re.NoError(regionStorage.SaveRegion(newTestRegionMeta(2)))
+	re.Empty(changes)
+	// The deletion waits for the earlier changes to keep the write order.
+	re.NoError(regionStorage.DeleteRegion(newTestRegionMeta(1)))
+	re.Empty(changes)
+	re.NoError(regionStorage.Flush())
+	re.NoError(regionStorage.SaveRegions([]*metapb.Region{newTestRegionMeta(3)}))
+	re.NoError(regionStorage.DeleteRegion(newTestRegionMeta(2)))
+
+	expected := []RegionChange{
+		{Region: newTestRegionMeta(1)},
+		{Region: newTestRegionMeta(2)},
+		{Region: newTestRegionMeta(1), Deleted: true},
+		{Region: newTestRegionMeta(3)},
+		{Region: newTestRegionMeta(2), Deleted: true},
+	}
+	for _, change := range expected {
+		re.Equal(change, <-changes)
+	}
+	re.Empty(changes)
+
+	// The channel is closed after unsubscribing.
+	unsubscribe()
+	unsubscribe()
+	_, ok := <-changes
+	re.False(ok)
+	re.NoError(regionStorage.SaveRegions([]*metapb.Region{newTestRegionMeta(4)}))
+
+	// The channel is closed after the storage is closed.
+	changes, _ = regionStorage.Subscribe()
+	re.NoError(regionStorage.Close())
+	_, ok = <-changes
+	re.False(ok)
+}
+
+func BenchmarkRegionStorageSaveRegion(b *testing.B) {
+	benchmarkRegionStorageSave(b, func(s *RegionStorage, regions []*metapb.Region) error {
+		for _, region := range regions {
+			if err := s.SaveRegion(region); err != nil {
+				return err
+			}
+		}
+		return s.Flush()
+	})
+}
+
+func BenchmarkRegionStorageSaveRegions(b *testing.B) {
+	benchmarkRegionStorageSave(b, func(s *RegionStorage, regions []*metapb.Region) error {
+		return s.SaveRegions(regions)
+	})
+}
+
+func benchmarkRegionStorageSave(b *testing.B, save func(*RegionStorage, []*metapb.Region) error) {
+	re := require.New(b)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	regionStorage, err := NewRegionStorageWithLevelDBBackend(ctx, b.TempDir(), nil)
+	re.NoError(err)
+	defer regionStorage.Close()
+	n := 10000
+	regions := make([]*metapb.Region, 0, n)
+	for i := 0; i < n; i++ {
+		regions = append(regions, newTestRegionMeta(uint64(i)))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		re.NoError(save(regionStorage, regions))
+	}
+}
+
+func TestRegionStorageLoadRegionsWithConcurrentDelete(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	regionStorage, err := NewRegionStorageWithLevelDBBackend(ctx, t.TempDir(), nil)
+	re.NoError(err)
+	defer regionStorage.Close()
+	n := 3
+	expected := make([]*metapb.Region, 0, n)
+	for i := 0; i < n; i++ {
+		expected = append(expected, newTestRegionMeta(uint64(i)))
+	}
+	re.NoError(regionStorage.SaveRegions(expected))
+
+	// Load the regions one by one slowly, and delete the last region and save
+	// a new one after the first region is loaded.
+	fname := "github.com/tikv/pd/pkg/storage/endpoint/slowLoadRegion"
+	re.NoError(failpoint.Enable(fname, "return(true)"))
+	defer func() {
+		re.NoError(failpoint.Disable(fname))
+	}()
+	regions := make([]*metapb.Region, 0, n)
+	err = regionStorage.LoadRegions(ctx, func(region *core.RegionInfo) []*core.RegionInfo {
+		regions = append(regions, region.GetMeta())
+		if len(regions) == 1 {
+			re.NoError(regionStorage.DeleteRegion(expected[n-1]))
+			re.NoError(regionStorage.SaveRegions([]*metapb.Region{newTestRegionMeta(uint64(n))}))
+		}
+		return nil
+	})
+	re.NoError(err)
+	// The load sees the regions at the beginning.
+	re.Equal(expected, regions)
+
+	// The next load sees the changes.
+	regions = regions[:0]
+	err = regionStorage.LoadRegions(ctx, func(region *core.RegionInfo) []*core.RegionInfo {
+		regions = append(regions, region.GetMeta())
+		return nil
+	})
+	re.NoError(err)
+	re.Equal(append(expected[:n-1:n-1], newTestRegionMeta(uint64(n))), regions)
+}
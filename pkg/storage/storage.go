@@ -71,6 +71,21 @@ func NewRegionStorageWithLevelDBBackend(
 	return newRegionStorage(levelDBBackend), nil
 }
 
+// NewRegionStorageWithLevelDBOptions is the same as `NewRegionStorageWithLevelDBBackend`,
+// but opens the LevelDB with the given tuning options.
+func NewRegionStorageWithLevelDBOptions(
+	ctx context.Context,
+	filePath string,
+	ekm *encryption.Manager,
+	opts *LevelDBOptions,
+) (*RegionStorage, error) {
+	levelDBBackend, err := newLevelDBBackendWithOptions(ctx, filePath, ekm, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newRegionStorage(levelDBBackend), nil
+}
+
 // TODO: support other KV storage backends like BadgerDB in the future.
 
 type coreStorage struct {
@@ -157,11 +172,11 @@ func (ps *coreStorage) LoadRegion(regionID uint64, region *metapb.Region) (ok bo
 }
 
 // LoadRegions loads all regions from storage to RegionsInfo.
-func (ps *coreStorage) LoadRegions(ctx context.Context, f func(region *core.RegionInfo) []*core.RegionInfo) error {
+func (ps *coreStorage) LoadRegions(ctx context.Context, f func(region *core.RegionInfo) []*core.RegionInfo, opts ...endpoint.LoadRegionsOption) error {
 	if atomic.LoadInt32(&ps.useRegionStorage) > 0 {
-		return ps.regionStorage.LoadRegions(ctx, f)
+		return ps.regionStorage.LoadRegions(ctx, f, opts...)
 	}
-	return ps.Storage.LoadRegions(ctx, f)
+	return ps.Storage.LoadRegions(ctx, f, opts...)
 }
 
 // SaveRegion saves one region to storage.
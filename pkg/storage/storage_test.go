@@ -275,6 +275,164 @@ func TestLoadRegions(t *testing.T) {
 	}
 }
 
+func TestLoadRegionsFromCheckpoint(t *testing.T) {
+	re := require.New(t)
+	storage := newMemoryBackend()
+	// Make sure the regions can't be loaded in one range.
+	n := endpoint.MaxKVRangeLimit + endpoint.MaxKVRangeLimit/2
+	mustSaveRegions(re, storage, n)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	loaded := make(map[uint64]int, n)
+	loadFunc := func(region *core.RegionInfo) []*core.RegionInfo {
+		loaded[region.GetID()]++
+		// Interrupt the loading after the first region.
+		cancel()
+		return nil
+	}
+	checkpoint, err := storage.LoadRegionsFrom(ctx, 0, loadFunc)
+	re.ErrorIs(err, context.Canceled)
+	re.Equal(uint64(endpoint.MaxKVRangeLimit), checkpoint)
+	re.Len(loaded, endpoint.MaxKVRangeLimit)
+
+	// Resume from the checkpoint.
+	checkpoint, err = storage.LoadRegionsFrom(context.Background(), checkpoint, loadFunc)
+	re.NoError(err)
+	re.Equal(uint64(n), checkpoint)
+	re.Len(loaded, n)
+	for id, count := range loaded {
+		re.Equal(1, count, "region %d is loaded more than once", id)
+	}
+}
+
+func TestLoadRegionsWithCorruptedRegion(t *testing.T) {
+	re := require.New(t)
+	storage := newMemoryBackend()
+	// Make sure the regions are decoded in parallel.
+	n := 3000
+	mustSaveRegions(re, storage, n)
+	re.NoError(storage.Save(endpoint.RegionPath(2000), "corrupted"))
+
+	var loaded []uint64
+	checkpoint, err := storage.LoadRegionsFrom(context.Background(), 0, func(region *core.RegionInfo) []*core.RegionInfo {
+		loaded = append(loaded, region.GetID())
+		return nil
+	})
+	re.Error(err)
+	// The regions before the corrupted one are loaded in order.
+	re.Equal(uint64(2000), checkpoint)
+	re.Len(loaded, 2000)
+	for i, id := range loaded {
+		re.Equal(uint64(i), id)
+	}
+}
+
+func TestLoadRegionsWithRetryPolicy(t *testing.T) {
+	re := require.New(t)
+	storage := newMemoryBackend()
+	n := 10
+	mustSaveRegions(re, storage, n)
+
+	var retried []int
+	policy := func(retries int, _ error) (time.Duration, bool) {
+		retried = append(retried, retries)
+		return time.Millisecond, true
+	}
+	fname := "github.com/tikv/pd/pkg/storage/endpoint/loadRegionsTransientError"
+	// Fail outright without the retry policy.
+	re.NoError(failpoint.Enable(fname, "return(true)"))
+	cache := core.NewBasicCluster()
+	re.Error(storage.LoadRegions(context.Background(), cache.CheckAndPutRegion))
+	re.Zero(cache.GetTotalRegionCount())
+	re.NoError(failpoint.Disable(fname))
+
+	// The transient errors are retried until the loading succeeds, the first
+	// errors are consumed by shrinking the range limit.
+	re.NoError(failpoint.Enable(fname, "10*return(true)"))
+	re.NoError(storage.LoadRegions(context.Background(), cache.CheckAndPutRegion, endpoint.WithRetryPolicy(policy)))
+	re.NoError(failpoint.Disable(fname))
+	re.Equal(n, cache.GetTotalRegionCount())
+	re.Equal([]int{0, 1, 2, 3}, retried)
+
+	// The range limit is restored after the retries, so the rest regions are
+	// loaded in one range before the cancellation is noticed.
+	storage = newMemoryBackend()
+	n = 10 * endpoint.MinKVRangeLimit
+	mustSaveRegions(re, storage, n)
+	ctx, cancel := context.WithCancel(context.Background())
+	loaded := 0
+	loadFunc := func(*core.RegionInfo) []*core.RegionInfo {
+		if loaded++; loaded > endpoint.MinKVRangeLimit {
+			cancel()
+		}
+		return nil
+	}
+	re.NoError(failpoint.Enable(fname, "10*return(true)"))
+	re.NoError(storage.LoadRegions(ctx, loadFunc, endpoint.WithRetryPolicy(policy)))
+	re.NoError(failpoint.Disable(fname))
+	re.Equal(n, loaded)
+
+	// The context cancellation aborts the loading immediately.
+	retried = nil
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	re.NoError(failpoint.Enable(fname, "return(true)"))
+	defer func() {
+		re.NoError(failpoint.Disable(fname))
+	}()
+	re.Error(storage.LoadRegions(ctx, cache.CheckAndPutRegion, endpoint.WithRetryPolicy(policy)))
+	re.Empty(retried)
+
+	// Give up after the max retries.
+	re.Error(storage.LoadRegions(context.Background(), cache.CheckAndPutRegion,
+		endpoint.WithRetryPolicy(endpoint.NewBackoffRetryPolicy(2, time.Millisecond, 2*time.Millisecond))))
+}
+
+func TestBackoffRetryPolicy(t *testing.T) {
+	re := require.New(t)
+	policy := endpoint.NewBackoffRetryPolicy(4, 10*time.Millisecond, 30*time.Millisecond)
+	for retries, expected := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond} {
+		backoff, ok := policy(retries, nil)
+		re.True(ok)
+		re.Equal(expected, backoff)
+	}
+	_, ok := policy(4, nil)
+	re.False(ok)
+}
+
+func TestCompareWithCluster(t *testing.T) {
+	re := require.New(t)
+	storage := NewStorageWithMemoryBackend()
+	cluster := core.NewBasicCluster()
+
+	regions := mustSaveRegions(re, storage, 10)
+	for _, region := range regions {
+		cluster.CheckAndPutRegion(core.NewRegionInfo(region, nil))
+	}
+	report, err := CompareWithCluster(context.Background(), storage, cluster)
+	re.NoError(err)
+	re.True(report.IsConsistent())
+
+	// A region only in the storage.
+	re.NoError(storage.SaveRegion(newTestRegionMeta(100)))
+	// A region only in the cluster.
+	re.NoError(storage.DeleteRegion(regions[3]))
+	// A region with stale epoch in the storage.
+	region := cluster.GetRegion(5)
+	cluster.PutRegion(region.Clone(core.WithIncVersion()))
+
+	report, err = CompareWithCluster(context.Background(), storage, cluster)
+	re.NoError(err)
+	re.False(report.IsConsistent())
+	re.Equal([]uint64{100}, report.OnlyInStorage)
+	re.Equal([]uint64{3}, report.OnlyInCluster)
+	re.Equal([]uint64{5}, report.EpochMismatch)
+	// The storage should be untouched.
+	ok, err := storage.LoadRegion(100, &metapb.Region{})
+	re.NoError(err)
+	re.True(ok)
+}
+
 func mustSaveRegions(re *require.Assertions, s endpoint.RegionStorage, n int) []*metapb.Region {
 	regions := make([]*metapb.Region, 0, n)
 	for i := 0; i < n; i++ {
@@ -85,6 +85,7 @@ type mockServer struct {
 	member, leader *pdpb.Member
 	storage        storage.Storage
 	bc             *core.BasicCluster
+	batchSize      int
 }
 
 func (s *mockServer) LoopContext() context.Context {
@@ -122,3 +123,7 @@ func (*mockServer) GetTLSConfig() *grpcutil.TLSConfig {
 func (s *mockServer) GetBasicCluster() *core.BasicCluster {
 	return s.bc
 }
+
+func (s *mockServer) GetRegionSyncBatchSize() int {
+	return s.batchSize
+}
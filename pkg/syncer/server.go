@@ -17,6 +17,7 @@ package syncer
 import (
 	"context"
 	"io"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -42,7 +43,7 @@ import (
 const (
 	defaultBucketRate        = 20 * units.MiB // 20MB/s
 	defaultBucketCapacity    = 20 * units.MiB // 20MB
-	maxSyncRegionBatchSize   = 100
+	defaultRegionBatchSize   = 100
 	syncerKeepAliveInterval  = 10 * time.Second
 	defaultHistoryBufferSize = 10000
 )
@@ -69,6 +70,7 @@ type Server interface {
 	GetRegions() []*core.RegionInfo
 	GetTLSConfig() *grpcutil.TLSConfig
 	GetBasicCluster() *core.BasicCluster
+	GetRegionSyncBatchSize() int
 }
 
 // RegionSyncer is used to sync the region information without raft.
@@ -76,6 +78,7 @@ type RegionSyncer struct {
 	mu struct {
 		syncutil.RWMutex
 		streams      map[string]ServerStream
+		downstreams  map[string]*downstreamProgress
 		clientCtx    context.Context
 		clientCancel context.CancelFunc
 	}
@@ -102,9 +105,73 @@ func NewRegionSyncer(s Server) *RegionSyncer {
 		tlsConfig: s.GetTLSConfig(),
 	}
 	syncer.mu.streams = make(map[string]ServerStream)
+	syncer.mu.downstreams = make(map[string]*downstreamProgress)
 	return syncer
 }
 
+// getBatchSize returns the max number of regions grouped in one message.
+func (s *RegionSyncer) getBatchSize() int {
+	if size := s.server.GetRegionSyncBatchSize(); size > 0 {
+		return size
+	}
+	return defaultRegionBatchSize
+}
+
+// downstreamProgress is the progress of a downstream synced by the leader.
+type downstreamProgress struct {
+	// nextIndex is the next index of the history records to sync to the downstream.
+	nextIndex    uint64
+	lastSyncTime time.Time
+}
+
+// DownstreamStatus is the region sync status of a downstream of the leader.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type DownstreamStatus struct {
+	Name string `json:"name"`
+	// Connected is false if the stream to the downstream is broken, and the
+	// lag grows until the downstream reconnects to catch up.
+	Connected bool `json:"connected"`
+	// LagRegions is the number of the region changes not synced to the downstream yet.
+	LagRegions uint64 `json:"lag_regions"`
+	// LastSyncTime is the last time that the leader synced to the downstream,
+	// the leader syncs at least every 10s even if no region is changed.
+	LastSyncTime time.Time `json:"last_sync_time"`
+}
+
+// GetDownstreamStatuses returns the region sync status of all the downstreams
+// ever synced by the leader, the downstream with a large lag should be avoided
+// to serve the follower reads.
+func (s *RegionSyncer) GetDownstreamStatuses() []DownstreamStatus {
+	nextIndex := s.history.GetNextIndex()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	statuses := make([]DownstreamStatus, 0, len(s.mu.downstreams))
+	for name, progress := range s.mu.downstreams {
+		status := DownstreamStatus{
+			Name:         name,
+			LastSyncTime: progress.lastSyncTime,
+		}
+		_, status.Connected = s.mu.streams[name]
+		if nextIndex > progress.nextIndex {
+			status.LagRegions = nextIndex - progress.nextIndex
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// updateDownstreamProgressLocked records that the history records before nextIndex
+// have been synced to the downstream.
+func (s *RegionSyncer) updateDownstreamProgressLocked(name string, nextIndex uint64) {
+	s.mu.downstreams[name] = &downstreamProgress{
+		nextIndex:    nextIndex,
+		lastSyncTime: time.Now(),
+	}
+}
+
 // RunServer runs the server of the region syncer.
 // regionNotifier is used to get the changed regions.
 func (s *RegionSyncer) RunServer(ctx context.Context, regionNotifier <-chan *core.RegionInfo) {
@@ -118,6 +185,7 @@ func (s *RegionSyncer) RunServer(ctx context.Context, regionNotifier <-chan *cor
 		ticker.Stop()
 		s.mu.Lock()
 		s.mu.streams = make(map[string]ServerStream)
+		s.mu.downstreams = make(map[string]*downstreamProgress)
 		s.mu.Unlock()
 	}()
 
@@ -138,8 +206,8 @@ func (s *RegionSyncer) RunServer(ctx context.Context, regionNotifier <-chan *cor
 			leaders = append(leaders, first.GetLeader())
 			startIndex := s.history.GetNextIndex()
 			s.history.Record(first)
-			pending := len(regionNotifier)
-			for i := 0; i < pending && i < maxSyncRegionBatchSize; i++ {
+			pending, batchSize := len(regionNotifier), s.getBatchSize()
+			for i := 0; i < pending && len(requests) < batchSize; i++ {
 				region := <-regionNotifier
 				requests = append(requests, region.GetMeta())
 				stats = append(stats, region.GetStat())
@@ -212,11 +280,13 @@ func (s *RegionSyncer) Sync(ctx context.Context, stream pdpb.PD_SyncRegionsServe
 			zap.String("requested-server", request.GetMember().GetName()),
 			zap.String("url", request.GetMember().GetClientUrls()[0]))
 
+		// The records after the index are synced by the broadcast once the stream is bound.
+		nextIndex := s.history.GetNextIndex()
 		err = s.syncHistoryRegion(ctx, request, stream)
 		if err != nil {
 			return err
 		}
-		s.bindStream(request.GetMember().GetName(), stream)
+		s.bindStream(request.GetMember().GetName(), stream, nextIndex)
 	}
 }
 
@@ -243,11 +313,11 @@ func (s *RegionSyncer) syncHistoryRegion(ctx context.Context, request *pdpb.Sync
 		if startIndex == 0 {
 			regions := s.server.GetRegions()
 			lastIndex := 0
-			start := time.Now()
-			metas := make([]*metapb.Region, 0, maxSyncRegionBatchSize)
-			stats := make([]*pdpb.RegionStat, 0, maxSyncRegionBatchSize)
-			leaders := make([]*metapb.Peer, 0, maxSyncRegionBatchSize)
-			buckets := make([]*metapb.Buckets, 0, maxSyncRegionBatchSize)
+			start, batchSize := time.Now(), s.getBatchSize()
+			metas := make([]*metapb.Region, 0, batchSize)
+			stats := make([]*pdpb.RegionStat, 0, batchSize)
+			leaders := make([]*metapb.Peer, 0, batchSize)
+			buckets := make([]*metapb.Buckets, 0, batchSize)
 			for syncedIndex, r := range regions {
 				select {
 				case <-ctx.Done():
@@ -271,7 +341,7 @@ func (s *RegionSyncer) syncHistoryRegion(ctx context.Context, request *pdpb.Sync
 					bucket = r.GetBuckets()
 				}
 				buckets = append(buckets, bucket)
-				if len(metas) < maxSyncRegionBatchSize && syncedIndex < len(regions)-1 {
+				if len(metas) < batchSize && syncedIndex < len(regions)-1 {
 					continue
 				}
 				resp := &pdpb.SyncRegionResponse{
@@ -334,30 +404,36 @@ func (s *RegionSyncer) syncHistoryRegion(ctx context.Context, request *pdpb.Sync
 	return stream.Send(resp)
 }
 
-// bindStream binds the established server stream.
-func (s *RegionSyncer) bindStream(name string, stream ServerStream) {
+// bindStream binds the established server stream, whose history records
+// before nextIndex have been synced.
+func (s *RegionSyncer) bindStream(name string, stream ServerStream, nextIndex uint64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.mu.streams[name] = stream
+	s.updateDownstreamProgressLocked(name, nextIndex)
 }
 
 func (s *RegionSyncer) broadcast(regions *pdpb.SyncRegionResponse) {
-	var failed []string
+	var failed, succeeded []string
 	s.mu.RLock()
 	for name, sender := range s.mu.streams {
 		err := sender.Send(regions)
 		if err != nil {
 			log.Error("region syncer send data meet error", errs.ZapError(errs.ErrGRPCSend, err))
 			failed = append(failed, name)
+		} else {
+			succeeded = append(succeeded, name)
 		}
 	}
 	s.mu.RUnlock()
-	if len(failed) > 0 {
-		s.mu.Lock()
-		for _, name := range failed {
-			delete(s.mu.streams, name)
-			log.Info("region syncer delete the stream", zap.String("stream", name))
-		}
-		s.mu.Unlock()
+	s.mu.Lock()
+	nextIndex := regions.GetStartIndex() + uint64(len(regions.GetRegions()))
+	for _, name := range succeeded {
+		s.updateDownstreamProgressLocked(name, nextIndex)
+	}
+	for _, name := range failed {
+		delete(s.mu.streams, name)
+		log.Info("region syncer delete the stream", zap.String("stream", name))
 	}
+	s.mu.Unlock()
 }
@@ -0,0 +1,94 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/storage"
+)
+
+type mockSyncRegionsServer struct {
+	pdpb.PD_SyncRegionsServer
+	resps []*pdpb.SyncRegionResponse
+}
+
+func (s *mockSyncRegionsServer) Send(resp *pdpb.SyncRegionResponse) error {
+	// The slices in the response are reused by the sender after sending.
+	s.resps = append(s.resps, proto.Clone(resp).(*pdpb.SyncRegionResponse))
+	return nil
+}
+
+func TestSyncRegionBatchSize(t *testing.T) {
+	re := require.New(t)
+	rs, err := storage.NewRegionStorageWithLevelDBBackend(context.Background(), t.TempDir(), nil)
+	re.NoError(err)
+	defer rs.Close()
+	server := &mockServer{
+		ctx:     context.Background(),
+		storage: storage.NewCoreStorage(storage.NewStorageWithMemoryBackend(), rs),
+		bc:      core.NewBasicCluster(),
+	}
+	const regionCount = 250
+	for i := uint64(1); i <= regionCount; i++ {
+		peer := &metapb.Peer{Id: i, StoreId: 1}
+		region := &metapb.Region{
+			Id:       i,
+			StartKey: []byte(fmt.Sprintf("%20d", i)),
+			EndKey:   []byte(fmt.Sprintf("%20d", i+1)),
+			Peers:    []*metapb.Peer{peer},
+		}
+		server.bc.PutRegion(core.NewRegionInfo(region, peer))
+	}
+
+	fullSync := func(batchSize int) []*pdpb.SyncRegionResponse {
+		server.batchSize = batchSize
+		rc := NewRegionSyncer(server)
+		// The history doesn't contain the index 0, so a full synchronization is required.
+		rc.history.ResetWithIndex(regionCount)
+		stream := &mockSyncRegionsServer{}
+		re.NoError(rc.syncHistoryRegion(context.Background(), &pdpb.SyncRegionRequest{}, stream))
+		// All the regions are synced in order regardless of the batch size.
+		var startIndex uint64
+		ids := make(map[uint64]struct{})
+		for _, resp := range stream.resps {
+			re.Equal(startIndex, resp.GetStartIndex())
+			re.LessOrEqual(len(resp.GetRegions()), batchSize)
+			re.Len(resp.GetRegionStats(), len(resp.GetRegions()))
+			re.Len(resp.GetRegionLeaders(), len(resp.GetRegions()))
+			for _, region := range resp.GetRegions() {
+				ids[region.GetId()] = struct{}{}
+			}
+			startIndex += uint64(len(resp.GetRegions()))
+		}
+		re.Len(ids, regionCount)
+		return stream.resps
+	}
+
+	re.Len(fullSync(1), regionCount)
+	re.Len(fullSync(defaultRegionBatchSize), 3)
+	re.Len(fullSync(regionCount), 1)
+	re.Len(fullSync(1000), 1)
+	// The default batch size is used if it's not configured.
+	server.batchSize = 0
+	re.Equal(defaultRegionBatchSize, NewRegionSyncer(server).getBatchSize())
+}
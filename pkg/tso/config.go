@@ -49,3 +49,20 @@ type Config interface {
 	// GetTLSConfig returns the TLS config.
 	GetTLSConfig() *grpcutil.TLSConfig
 }
+
+// GetMaxTSOsPerPhysicalTick returns the theoretical max number of TSOs which can be
+// generated in one physical update interval, the physical time will be forced to
+// increase once the logical time reaches the limit.
+func GetMaxTSOsPerPhysicalTick() int64 {
+	return MaxLogical
+}
+
+// GetMaxTSOsPerSecond returns the theoretical max number of TSOs which can be generated
+// in a second with the TSO update physical interval of the config.
+func GetMaxTSOsPerSecond(cfg Config) int64 {
+	interval := cfg.GetTSOUpdatePhysicalInterval()
+	if interval <= 0 {
+		return 0
+	}
+	return GetMaxTSOsPerPhysicalTick() * int64(time.Second) / int64(interval)
+}
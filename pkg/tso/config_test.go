@@ -0,0 +1,36 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tso
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxTSOs(t *testing.T) {
+	re := require.New(t)
+	// At most 1<<18 (262144) TSOs can be generated in the interval.
+	re.Equal(int64(262144), GetMaxTSOsPerPhysicalTick())
+
+	// The default interval is 50ms.
+	cfg := &TestServiceConfig{TSOUpdatePhysicalInterval: 50 * time.Millisecond}
+	re.Equal(int64(262144*20), GetMaxTSOsPerSecond(cfg))
+	cfg.TSOUpdatePhysicalInterval = time.Millisecond
+	re.Equal(int64(262144*1000), GetMaxTSOsPerSecond(cfg))
+	cfg.TSOUpdatePhysicalInterval = 0
+	re.Zero(GetMaxTSOsPerSecond(cfg))
+}
@@ -323,7 +323,7 @@ var globalTSOOverflowFlag = true
 func (gta *GlobalTSOAllocator) precheckLogical(maxTSO *pdpb.Timestamp, suffixBits int) bool {
 	failpoint.Inject("globalTSOOverflow", func() {
 		if globalTSOOverflowFlag {
-			maxTSO.Logical = maxLogical
+			maxTSO.Logical = MaxLogical
 			globalTSOOverflowFlag = false
 		}
 	})
@@ -332,7 +332,7 @@ func (gta *GlobalTSOAllocator) precheckLogical(maxTSO *pdpb.Timestamp, suffixBit
 		return false
 	}
 	// Check if the logical part will reach the overflow condition after being differentiated.
-	if caliLogical := gta.timestampOracle.calibrateLogical(maxTSO.Logical, suffixBits); caliLogical >= maxLogical {
+	if caliLogical := gta.timestampOracle.calibrateLogical(maxTSO.Logical, suffixBits); caliLogical >= MaxLogical {
 		log.Error("estimated logical part outside of max logical interval, please check ntp time",
 			logutil.CondUint32("keyspace-group-id", gta.getGroupID(), gta.getGroupID() > 0),
 			zap.Reflect("max-tso", maxTSO), errs.ZapError(errs.ErrLogicOverflow))
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"sort"
 )
 
 // BuildKeyRangeKey build key for a keyRange
@@ -25,6 +26,13 @@ func BuildKeyRangeKey(startKey, endKey []byte) string {
 	return fmt.Sprintf("%s-%s", hex.EncodeToString(startKey), hex.EncodeToString(endKey))
 }
 
+// KeyRange is a key range, the empty start key and end key mean the start and
+// the end of the whole key space respectively.
+type KeyRange struct {
+	StartKey []byte `json:"start-key"`
+	EndKey   []byte `json:"end-key"`
+}
+
 // MaxKey return the bigger key for the given keys.
 func MaxKey(a, b []byte) []byte {
 	if bytes.Compare(a, b) > 0 {
@@ -41,6 +49,27 @@ func MinKey(a, b []byte) []byte {
 	return a
 }
 
+// CompareKeys compares the end keys a and b like bytes.Compare, except that the
+// empty key means the end of the key space, i.e. it's larger than any other key.
+func CompareKeys(a, b []byte) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	default:
+		return bytes.Compare(a, b)
+	}
+}
+
+// CompareStartKeys compares the start keys a and b, the empty key means the start
+// of the key space, i.e. it's smaller than any other key as bytes.Compare does.
+func CompareStartKeys(a, b []byte) int {
+	return bytes.Compare(a, b)
+}
+
 type boundary int
 
 const (
@@ -66,3 +95,32 @@ func less(a, b []byte, boundary boundary) bool {
 func Between(startKey, endKey, key []byte) bool {
 	return less(startKey, key, left) && less(key, endKey, right)
 }
+
+// CoversRange returns true if the union of the ranges fully covers the target
+// range. The adjacent ranges are considered continuous, e.g. [a, b) and [b, c)
+// cover [a, c).
+func CoversRange(ranges []KeyRange, target KeyRange) bool {
+	// The empty target is always covered.
+	if len(target.EndKey) > 0 && bytes.Compare(target.StartKey, target.EndKey) >= 0 {
+		return true
+	}
+	sorted := append(make([]KeyRange, 0, len(ranges)), ranges...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].StartKey, sorted[j].StartKey) < 0
+	})
+	// The keys before covered are all covered.
+	covered := target.StartKey
+	for _, r := range sorted {
+		if bytes.Compare(r.StartKey, covered) > 0 {
+			return false
+		}
+		if len(r.EndKey) == 0 {
+			return true
+		}
+		covered = MaxKey(covered, r.EndKey)
+		if len(target.EndKey) > 0 && bytes.Compare(covered, target.EndKey) >= 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -128,3 +128,64 @@ func TestBetween(t *testing.T) {
 		re.Equal(data.expect, Between(data.startKey, data.endKey, data.key))
 	}
 }
+
+func TestCoversRange(t *testing.T) {
+	re := require.New(t)
+	newRange := func(startKey, endKey string) KeyRange {
+		return KeyRange{StartKey: []byte(startKey), EndKey: []byte(endKey)}
+	}
+	testCases := []struct {
+		ranges []KeyRange
+		target KeyRange
+		expect bool
+	}{
+		// The full coverage with the adjacent ranges.
+		{[]KeyRange{newRange("a", "b"), newRange("b", "c")}, newRange("a", "c"), true},
+		{[]KeyRange{newRange("b", "d"), newRange("", "b")}, newRange("", "c"), true},
+		{[]KeyRange{newRange("", "b"), newRange("b", "")}, newRange("", ""), true},
+		{[]KeyRange{newRange("a", "")}, newRange("b", ""), true},
+		// The gap between the ranges.
+		{[]KeyRange{newRange("a", "b"), newRange("c", "d")}, newRange("a", "d"), false},
+		{[]KeyRange{newRange("a", "b"), newRange("c", "d")}, newRange("b", "c"), false},
+		{[]KeyRange{newRange("b", "d")}, newRange("a", "c"), false},
+		{[]KeyRange{newRange("a", "d")}, newRange("a", ""), false},
+		{nil, newRange("a", "b"), false},
+		// The overlapping but complete coverage.
+		{[]KeyRange{newRange("a", "c"), newRange("b", "e"), newRange("d", "f")}, newRange("b", "f"), true},
+		{[]KeyRange{newRange("a", "f"), newRange("b", "c")}, newRange("c", "d"), true},
+		// The empty target is always covered.
+		{nil, newRange("b", "a"), true},
+	}
+	for _, testCase := range testCases {
+		re.Equal(testCase.expect, CoversRange(testCase.ranges, testCase.target), "%v %v", testCase.ranges, testCase.target)
+	}
+}
+
+func TestCompareKeys(t *testing.T) {
+	re := require.New(t)
+	testCases := []struct {
+		a, b     string
+		endCmp   int
+		startCmp int
+	}{
+		{"a", "b", -1, -1},
+		{"b", "a", 1, 1},
+		{"a", "a", 0, 0},
+		{"a", "ab", -1, -1},
+		{"\x00", "\xff", -1, -1},
+		// The empty end key is +infinity, while the empty start key is -infinity.
+		{"", "", 0, 0},
+		{"", "a", 1, -1},
+		{"a", "", -1, 1},
+		{"", "\xff\xff", 1, -1},
+	}
+	for _, testCase := range testCases {
+		a, b := []byte(testCase.a), []byte(testCase.b)
+		re.Equal(testCase.endCmp, CompareKeys(a, b), "%q %q", a, b)
+		re.Equal(testCase.startCmp, CompareStartKeys(a, b), "%q %q", a, b)
+	}
+	// The nil key is the same as the empty key.
+	re.Equal(0, CompareKeys(nil, []byte{}))
+	re.Equal(1, CompareKeys(nil, []byte("a")))
+	re.Equal(-1, CompareStartKeys(nil, []byte("a")))
+}
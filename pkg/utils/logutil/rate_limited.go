@@ -0,0 +1,97 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logutil
+
+import (
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RateLimitedLogger collapses the repeated identical messages. A message is logged
+// at most once per interval, and the next one logged after the interval carries
+// how many similar messages have been suppressed in between.
+type RateLimitedLogger struct {
+	mu       syncutil.Mutex
+	interval time.Duration
+	// lastLogged records the last time each message is logged and
+	// how many times it has been suppressed since then.
+	lastLogged map[string]*rateLimitedEntry
+
+	// logger and now can be replaced in tests.
+	logger func() *zap.Logger
+	now    func() time.Time
+}
+
+type rateLimitedEntry struct {
+	loggedAt   time.Time
+	suppressed int
+}
+
+// NewRateLimitedLogger creates a RateLimitedLogger which logs the same message at most once per interval.
+func NewRateLimitedLogger(interval time.Duration) *RateLimitedLogger {
+	return &RateLimitedLogger{
+		interval:   interval,
+		lastLogged: make(map[string]*rateLimitedEntry),
+		logger:     log.L,
+		now:        time.Now,
+	}
+}
+
+// Debug logs a message at debug level if it's not suppressed.
+func (l *RateLimitedLogger) Debug(msg string, fields ...zap.Field) {
+	l.log(zapcore.DebugLevel, msg, fields)
+}
+
+// Info logs a message at info level if it's not suppressed.
+func (l *RateLimitedLogger) Info(msg string, fields ...zap.Field) {
+	l.log(zapcore.InfoLevel, msg, fields)
+}
+
+// Warn logs a message at warn level if it's not suppressed.
+func (l *RateLimitedLogger) Warn(msg string, fields ...zap.Field) {
+	l.log(zapcore.WarnLevel, msg, fields)
+}
+
+func (l *RateLimitedLogger) log(level zapcore.Level, msg string, fields []zap.Field) {
+	l.mu.Lock()
+	now := l.now()
+	entry, ok := l.lastLogged[msg]
+	if ok && now.Sub(entry.loggedAt) < l.interval {
+		entry.suppressed++
+		l.mu.Unlock()
+		return
+	}
+	var suppressed int
+	if ok {
+		suppressed = entry.suppressed
+	}
+	l.lastLogged[msg] = &rateLimitedEntry{loggedAt: now}
+	// Drop the stale messages to keep the map small.
+	for m, e := range l.lastLogged {
+		if now.Sub(e.loggedAt) >= l.interval && e.suppressed == 0 && m != msg {
+			delete(l.lastLogged, m)
+		}
+	}
+	l.mu.Unlock()
+
+	if suppressed > 0 {
+		fields = append(fields, zap.Int("suppressed-similar-messages", suppressed))
+	}
+	l.logger().Log(level, msg, fields...)
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRateLimitedLogger(t *testing.T) {
+	re := require.New(t)
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+	now := time.Now()
+	l := NewRateLimitedLogger(time.Second)
+	l.logger = func() *zap.Logger { return logger }
+	l.now = func() time.Time { return now }
+
+	// A burst of identical messages is collapsed into one.
+	for i := 0; i < 100; i++ {
+		l.Debug("fail to create operator", zap.Int("i", i))
+	}
+	l.Warn("another message")
+	re.Equal(2, logs.Len())
+	re.Equal(int64(0), logs.All()[0].ContextMap()["i"])
+	re.Equal("another message", logs.All()[1].Message)
+
+	// The suppressed count is reported after the interval.
+	now = now.Add(time.Second)
+	for i := 0; i < 100; i++ {
+		l.Debug("fail to create operator", zap.Int("i", i))
+	}
+	re.Equal(3, logs.Len())
+	entry := logs.All()[2]
+	re.Equal(zapcore.DebugLevel, entry.Level)
+	re.Equal(int64(99), entry.ContextMap()["suppressed-similar-messages"])
+
+	// The rate is at most once per interval.
+	for i := 0; i < 10; i++ {
+		now = now.Add(100 * time.Millisecond)
+		l.Info("fail to create operator")
+	}
+	re.Equal(4, logs.Len())
+	re.Equal(int64(108), logs.All()[3].ContextMap()["suppressed-similar-messages"])
+}
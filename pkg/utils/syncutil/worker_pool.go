@@ -0,0 +1,93 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// WorkerPool runs the submitted tasks in parallel with bounded concurrency.
+// Once a task fails, the context passed to the tasks is canceled and the
+// following submissions are rejected. All errors are collected and returned
+// by Wait.
+type WorkerPool struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	mu   Mutex
+	errs []error
+}
+
+// NewWorkerPool creates a WorkerPool running at most concurrency tasks at the
+// same time. A non-positive concurrency is treated as 1.
+func NewWorkerPool(ctx context.Context, concurrency int) *WorkerPool {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &WorkerPool{
+		ctx:    ctx,
+		cancel: cancel,
+		sem:    make(chan struct{}, concurrency),
+	}
+}
+
+// Submit runs the task in a new goroutine, it blocks until there is a free
+// worker. It returns the error of the context without running the task if the
+// context is canceled, either by the caller or by a failed task.
+func (p *WorkerPool) Submit(task func(ctx context.Context) error) error {
+	// Check the context first since select picks a random ready case.
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	case p.sem <- struct{}{}:
+		// The worker may be freed by a task quitting on the cancellation.
+		if err := p.ctx.Err(); err != nil {
+			<-p.sem
+			return err
+		}
+	}
+	p.wg.Add(1)
+	go func() {
+		defer func() {
+			<-p.sem
+			p.wg.Done()
+		}()
+		if err := task(p.ctx); err != nil {
+			p.mu.Lock()
+			p.errs = append(p.errs, err)
+			p.mu.Unlock()
+			p.cancel()
+		}
+	}()
+	return nil
+}
+
+// Wait waits for all submitted tasks to finish and returns the joined errors
+// of the failed tasks. The pool should not be used after Wait.
+func (p *WorkerPool) Wait() error {
+	p.wg.Wait()
+	p.cancel()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return errors.Join(p.errs...)
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPoolConcurrency(t *testing.T) {
+	re := require.New(t)
+	const concurrency = 4
+	pool := NewWorkerPool(context.Background(), concurrency)
+	var running, maxRunning, finished atomic.Int32
+	for i := 0; i < 100; i++ {
+		re.NoError(pool.Submit(func(context.Context) error {
+			cur := running.Add(1)
+			for {
+				old := maxRunning.Load()
+				if cur <= old || maxRunning.CompareAndSwap(old, cur) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			finished.Add(1)
+			return nil
+		}))
+	}
+	re.NoError(pool.Wait())
+	re.Equal(int32(100), finished.Load())
+	re.LessOrEqual(maxRunning.Load(), int32(concurrency))
+	re.Positive(maxRunning.Load())
+}
+
+func TestWorkerPoolErrors(t *testing.T) {
+	re := require.New(t)
+	err1, err2 := errors.New("err1"), errors.New("err2")
+	pool := NewWorkerPool(context.Background(), 2)
+	start := make(chan struct{})
+	for _, err := range []error{err1, err2} {
+		err := err
+		re.NoError(pool.Submit(func(context.Context) error {
+			<-start
+			return err
+		}))
+	}
+	close(start)
+	err := pool.Wait()
+	re.ErrorIs(err, err1)
+	re.ErrorIs(err, err2)
+
+	// The following submissions are rejected after a task fails.
+	pool = NewWorkerPool(context.Background(), 2)
+	re.NoError(pool.Submit(func(context.Context) error { return err1 }))
+	var canceled atomic.Bool
+	re.NoError(pool.Submit(func(ctx context.Context) error {
+		<-ctx.Done()
+		canceled.Store(true)
+		return nil
+	}))
+	re.Eventually(func() bool {
+		return pool.Submit(func(context.Context) error { return nil }) != nil
+	}, time.Second, time.Millisecond)
+	re.ErrorIs(pool.Wait(), err1)
+	re.True(canceled.Load())
+}
+
+func TestWorkerPoolCancel(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := NewWorkerPool(ctx, 1)
+	started := make(chan struct{})
+	re.NoError(pool.Submit(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	<-started
+	// The submission blocks since the only worker is busy, and it is
+	// unblocked by the cancellation.
+	submitted := make(chan error)
+	go func() {
+		submitted <- pool.Submit(func(context.Context) error { return nil })
+	}()
+	select {
+	case <-submitted:
+		re.FailNow("the submission should be blocked")
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	re.ErrorIs(<-submitted, context.Canceled)
+	re.ErrorIs(pool.Wait(), context.Canceled)
+}
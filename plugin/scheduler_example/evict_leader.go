@@ -18,10 +18,10 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/pingcap/errors"
-	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/core/constant"
 	"github.com/tikv/pd/pkg/errs"
@@ -32,6 +32,7 @@ import (
 	"github.com/tikv/pd/pkg/schedule/schedulers"
 	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/utils/apiutil"
+	"github.com/tikv/pd/pkg/utils/logutil"
 	"github.com/tikv/pd/pkg/utils/syncutil"
 	"github.com/unrolled/render"
 )
@@ -44,6 +45,10 @@ const (
 	noStoreInSchedulerInfo = "No store in user-evict-leader-scheduler-config"
 )
 
+// scheduleLogger collapses the repeated logs of the failed scheduling, which may
+// flood the log when the cluster is degraded.
+var scheduleLogger = logutil.NewRateLimitedLogger(10 * time.Second)
+
 func init() {
 	schedulers.RegisterSliceDecoderBuilder(EvictLeaderType, func(args []string) schedulers.ConfigDecoder {
 		return func(v any) error {
@@ -189,7 +194,7 @@ func (s *evictLeaderScheduler) PrepareConfig(cluster sche.SchedulerCluster) erro
 	defer s.conf.mu.RUnlock()
 	var res error
 	for id := range s.conf.StoreIDWitRanges {
-		if err := cluster.PauseLeaderTransfer(id); err != nil {
+		if err := cluster.PauseLeaderTransferWithReason(id, EvictLeaderName); err != nil {
 			res = err
 		}
 	}
@@ -205,6 +210,10 @@ func (s *evictLeaderScheduler) CleanConfig(cluster sche.SchedulerCluster) {
 }
 
 func (s *evictLeaderScheduler) IsScheduleAllowed(cluster sche.SchedulerCluster) bool {
+	// Wait for the region information to catch up after the leader switch.
+	if !cluster.IsPrepared() {
+		return false
+	}
 	allowed := s.OpController.OperatorCount(operator.OpLeader) < cluster.GetSchedulerConfig().GetLeaderScheduleLimit()
 	if !allowed {
 		operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpLeader.String()).Inc()
@@ -231,10 +240,12 @@ func (s *evictLeaderScheduler) Schedule(cluster sche.SchedulerCluster, _ bool) (
 		}
 		op, err := operator.CreateTransferLeaderOperator(EvictLeaderType, cluster, region, target.GetID(), []uint64{}, operator.OpLeader)
 		if err != nil {
-			log.Debug("fail to create evict leader operator", errs.ZapError(err))
+			scheduleLogger.Debug("fail to create evict leader operator", errs.ZapError(err))
 			continue
 		}
 		op.SetPriorityLevel(constant.High)
+		// Attribute the operators in the metrics, a campaign ID can be used instead.
+		op.SetSource(EvictLeaderName)
 		ops = append(ops, op)
 	}
 
@@ -258,7 +269,7 @@ func (handler *evictLeaderHandler) UpdateConfig(w http.ResponseWriter, r *http.R
 	if ok {
 		id = (uint64)(idFloat)
 		if _, exists = handler.config.StoreIDWitRanges[id]; !exists {
-			if err := handler.config.cluster.PauseLeaderTransfer(id); err != nil {
+			if err := handler.config.cluster.PauseLeaderTransferWithReason(id, EvictLeaderName); err != nil {
 				handler.rd.JSON(w, http.StatusInternalServerError, err.Error())
 				return
 			}
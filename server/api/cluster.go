@@ -56,3 +56,33 @@ func (h *clusterHandler) GetClusterStatus(w http.ResponseWriter, _ *http.Request
 	}
 	h.rd.JSON(w, http.StatusOK, status)
 }
+
+// ClusterStats is the cluster-wide totals of the stores and regions.
+type ClusterStats struct {
+	StoreCount int `json:"store_count"`
+	// StoreCountByState is the count of the stores grouped by the node state,
+	// e.g. Serving and Removing.
+	StoreCountByState map[string]int `json:"store_count_by_state"`
+	RegionCount       int            `json:"region_count"`
+	LeaderCount       int            `json:"leader_count"`
+}
+
+// @Tags     cluster
+// @Summary  Get the cluster-wide totals of the stores and regions.
+// @Produce  json
+// @Success  200  {object}  ClusterStats
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /cluster/stats [get]
+func (h *clusterHandler) GetClusterStats(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	stats := &ClusterStats{
+		StoreCountByState: make(map[string]int),
+		RegionCount:       rc.GetTotalRegionCount(),
+		LeaderCount:       rc.GetTotalLeaderCount(),
+	}
+	for _, store := range rc.GetStores() {
+		stats.StoreCount++
+		stats.StoreCountByState[store.GetNodeState().String()]++
+	}
+	h.rd.JSON(w, http.StatusOK, stats)
+}
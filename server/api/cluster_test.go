@@ -21,6 +21,7 @@ import (
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/stretchr/testify/suite"
+	"github.com/tikv/pd/pkg/core"
 	sc "github.com/tikv/pd/pkg/schedule/config"
 	"github.com/tikv/pd/pkg/schedule/placement"
 	tu "github.com/tikv/pd/pkg/utils/testutil"
@@ -105,3 +106,30 @@ func (suite *clusterTestSuite) testGetClusterStatus() {
 	re.True(status.RaftBootstrapTime.After(now))
 	re.True(status.IsInitialized)
 }
+
+func (suite *clusterTestSuite) TestClusterStats() {
+	re := suite.Require()
+	rc := suite.svr.GetRaftCluster()
+	if rc == nil {
+		mustBootstrapCluster(re, suite.svr)
+		rc = suite.svr.GetRaftCluster()
+	}
+	for _, id := range []uint64{2, 3, 4} {
+		mustPutStore(re, suite.svr, id, metapb.StoreState_Up, metapb.NodeState_Serving, nil)
+	}
+	rc.GetBasicCluster().PutStore(rc.GetStore(4).Clone(core.SetStoreState(metapb.StoreState_Offline, false)))
+	mustPutRegion(re, suite.svr, 10, 2, []byte("a"), []byte("b"))
+	mustPutRegion(re, suite.svr, 11, 3, []byte("b"), []byte("c"))
+
+	url := fmt.Sprintf("%s/cluster/stats", suite.urlPrefix)
+	stats := &ClusterStats{}
+	re.NoError(tu.ReadGetJSON(re, testDialClient, url, stats))
+	stores := rc.GetStores()
+	re.Equal(len(stores), stats.StoreCount)
+	re.Equal(1, stats.StoreCountByState[metapb.NodeState_Removing.String()])
+	re.Equal(len(stores)-1, stats.StoreCountByState[metapb.NodeState_Serving.String()])
+	re.Equal(rc.GetTotalRegionCount(), stats.RegionCount)
+	re.GreaterOrEqual(stats.RegionCount, 2)
+	// Every region has a leader.
+	re.Equal(stats.RegionCount, stats.LeaderCount)
+}
@@ -17,6 +17,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/tikv/pd/pkg/syncer"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/cluster"
 	"github.com/unrolled/render"
@@ -73,6 +74,20 @@ func (h *healthHandler) GetHealthStatus(w http.ResponseWriter, _ *http.Request)
 	h.rd.JSON(w, http.StatusOK, healths)
 }
 
+// @Summary  Region sync status of the followers synced by the leader.
+// @Produce  json
+// @Success  200  {array}   syncer.DownstreamStatus
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /health/region-syncer [get]
+func (h *healthHandler) GetRegionSyncerStatus(w http.ResponseWriter, r *http.Request) {
+	regionSyncer := getCluster(r).GetRegionSyncer()
+	if regionSyncer == nil {
+		h.rd.JSON(w, http.StatusOK, []syncer.DownstreamStatus{})
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, regionSyncer.GetDownstreamStatuses())
+}
+
 // @Summary  Ping PD servers.
 // @Router   /ping [get]
 func (*healthHandler) Ping(http.ResponseWriter, *http.Request) {}
@@ -196,3 +196,28 @@ func (h *operatorHandler) GetOperatorRecords(w http.ResponseWriter, r *http.Requ
 	}
 	h.r.JSON(w, http.StatusOK, records)
 }
+
+// @Tags     operator
+// @Summary  lists the recent finished operators of the region.
+// @Param    region_id  path  integer  true  "A Region's Id"
+// @Produce  json
+// @Success  200  {object}  []operator.RegionOpRecord
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /operators/records/{region_id} [get]
+func (h *operatorHandler) GetRegionOperatorRecords(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["region_id"]
+
+	regionID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	records, err := h.Handler.GetRegionOperatorRecords(regionID)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, records)
+}
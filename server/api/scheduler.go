@@ -194,6 +194,11 @@ func (h *schedulerHandler) CreateScheduler(w http.ResponseWriter, r *http.Reques
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+	case schedulers.HotBucketLeaderName:
+		if err := h.AddHotBucketLeaderScheduler(); err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
 	case schedulers.GrantHotRegionName:
 		leaderID, ok := input["store-leader-id"].(string)
 		if !ok {
@@ -298,6 +303,46 @@ func (h *schedulerHandler) PauseOrResumeScheduler(w http.ResponseWriter, r *http
 	h.r.JSON(w, http.StatusOK, "Pause or resume the scheduler successfully.")
 }
 
+// @Tags     scheduler
+// @Summary  Export the configs of all schedulers as a single bundle.
+// @Produce  json
+// @Success  200  {object}  schedulers.ConfigBundle
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /schedulers/configs/export [get]
+func (h *schedulerHandler) ExportSchedulerConfigs(w http.ResponseWriter, _ *http.Request) {
+	bundle, err := h.Handler.ExportSchedulerConfigs()
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, bundle)
+}
+
+// @Tags     scheduler
+// @Summary  Import the scheduler configs exported as a bundle.
+// @Accept   json
+// @Param    body  body  schedulers.ConfigBundle  true  "The bundle of the scheduler configs."
+// @Produce  json
+// @Success  200  {string}  string  "The scheduler configs are imported."
+// @Failure  400  {string}  string  "Bad format request."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /schedulers/configs/import [post]
+func (h *schedulerHandler) ImportSchedulerConfigs(w http.ResponseWriter, r *http.Request) {
+	var bundle schedulers.ConfigBundle
+	if err := apiutil.ReadJSONRespondError(h.r, w, r.Body, &bundle); err != nil {
+		return
+	}
+	if err := h.Handler.ImportSchedulerConfigs(bundle); err != nil {
+		if errs.ErrSchedulerConfig.Equal(err) || errs.ErrSchedulerCreateFuncNotRegistered.Equal(err) {
+			h.r.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, "The scheduler configs are imported.")
+}
+
 type schedulerConfigHandler struct {
 	svr *server.Server
 	rd  *render.Render
@@ -46,6 +46,7 @@ func NewHandler(_ context.Context, svr *server.Server) (http.Handler, apiutil.AP
 	//	"/operators", http.MethodGet
 	//	"/operators", http.MethodPost
 	//	"/operators/records",http.MethodGet
+	//	"/operators/records/{region_id}",http.MethodGet
 	//	"/operators/{region_id}", http.MethodGet
 	//	"/operators/{region_id}", http.MethodDelete
 	//	"/checker/{name}", http.MethodPost
@@ -1602,12 +1602,44 @@ func (c *RaftCluster) PauseLeaderTransfer(storeID uint64) error {
 	return c.core.PauseLeaderTransfer(storeID)
 }
 
+// PauseLeaderTransferWithReason is like PauseLeaderTransfer, and records the reason of the pause.
+func (c *RaftCluster) PauseLeaderTransferWithReason(storeID uint64, reason string) error {
+	return c.core.PauseLeaderTransferWithReason(storeID, reason)
+}
+
 // ResumeLeaderTransfer cleans a store's pause state. The store can be selected
 // as source or target of TransferLeader again.
 func (c *RaftCluster) ResumeLeaderTransfer(storeID uint64) {
 	c.core.ResumeLeaderTransfer(storeID)
 }
 
+// PauseLeaderTransferBatch is like PauseLeaderTransferWithReason for multiple stores under one lock acquisition.
+func (c *RaftCluster) PauseLeaderTransferBatch(storeIDs []uint64, reason string) error {
+	return c.core.PauseLeaderTransferBatch(storeIDs, reason)
+}
+
+// ResumeLeaderTransferBatch is like ResumeLeaderTransfer for multiple stores under one lock acquisition.
+func (c *RaftCluster) ResumeLeaderTransferBatch(storeIDs []uint64) {
+	c.core.ResumeLeaderTransferBatch(storeIDs)
+}
+
+// AddLeaderTransferPauser pauses the leader transfer of the store on behalf of the pauser,
+// the pause can be shared by multiple pausers.
+func (c *RaftCluster) AddLeaderTransferPauser(storeID uint64, pauser string) error {
+	return c.core.AddLeaderTransferPauser(storeID, pauser)
+}
+
+// RemoveLeaderTransferPauser releases the pause of leader transfer held by the pauser.
+func (c *RaftCluster) RemoveLeaderTransferPauser(storeID uint64, pauser string) {
+	c.core.RemoveLeaderTransferPauser(storeID, pauser)
+}
+
+// GetLeaderTransferPausers returns the names of the sources currently pausing the
+// leader transfer of the store.
+func (c *RaftCluster) GetLeaderTransferPausers(storeID uint64) []string {
+	return c.core.GetLeaderTransferPausers(storeID)
+}
+
 // SlowStoreEvicted marks a store as a slow store and prevents transferring
 // leader to the store
 func (c *RaftCluster) SlowStoreEvicted(storeID uint64) error {
@@ -168,6 +168,8 @@ type Config struct {
 	MicroService MicroServiceConfig `toml:"micro-service" json:"micro-service"`
 
 	Controller rm.ControllerConfig `toml:"controller" json:"controller"`
+
+	ResourceManager rm.ManagerConfig `toml:"resource-manager" json:"resource-manager"`
 }
 
 // NewConfig creates a new config.
@@ -212,6 +214,8 @@ const (
 	defaultMaxResetTSGap     = 24 * time.Hour
 	defaultKeyType           = "table"
 
+	defaultRegionSyncBatchSize = 100
+
 	// DefaultMinResolvedTSPersistenceInterval is the default value of min resolved ts persistent interval.
 	DefaultMinResolvedTSPersistenceInterval = time.Second
 
@@ -471,7 +475,7 @@ func (c *Config) Adjust(meta *toml.MetaData, reloading bool) error {
 
 	c.Controller.Adjust(configMetaData.Child("controller"))
 
-	return nil
+	return c.ResourceManager.Validate()
 }
 
 func (c *Config) adjustLog(meta *configutil.ConfigMetaData) {
@@ -530,6 +534,9 @@ type PDServerConfig struct {
 	GCTunerThreshold float64 `toml:"gc-tuner-threshold" json:"gc-tuner-threshold"`
 	// BlockSafePointV1 is used to control gc safe point v1 and service safe point v1 can not be updated.
 	BlockSafePointV1 bool `toml:"block-safe-point-v1" json:"block-safe-point-v1,string"`
+	// RegionSyncBatchSize is the max number of regions grouped in one message when
+	// the leader syncs the regions to the followers.
+	RegionSyncBatchSize int `toml:"region-sync-batch-size" json:"region-sync-batch-size"`
 }
 
 func (c *PDServerConfig) adjust(meta *configutil.ConfigMetaData) error {
@@ -552,6 +559,9 @@ func (c *PDServerConfig) adjust(meta *configutil.ConfigMetaData) error {
 	if !meta.IsDefined("min-resolved-ts-persistence-interval") {
 		configutil.AdjustDuration(&c.MinResolvedTSPersistenceInterval, DefaultMinResolvedTSPersistenceInterval)
 	}
+	if !meta.IsDefined("region-sync-batch-size") {
+		configutil.AdjustInt(&c.RegionSyncBatchSize, defaultRegionSyncBatchSize)
+	}
 	if !meta.IsDefined("server-memory-limit") {
 		configutil.AdjustFloat64(&c.ServerMemoryLimit, defaultServerMemoryLimit)
 	}
@@ -632,6 +642,9 @@ func (c *PDServerConfig) Validate() error {
 	if c.FlowRoundByDigit < 0 {
 		return errs.ErrConfigItem.GenWithStack("flow round by digit cannot be negative number")
 	}
+	if c.RegionSyncBatchSize <= 0 {
+		return errs.ErrConfigItem.GenWithStack("region sync batch size should be positive")
+	}
 	if c.ServerMemoryLimit < minServerMemoryLimit || c.ServerMemoryLimit > maxServerMemoryLimit {
 		return errors.New(fmt.Sprintf("server-memory-limit should between %v and %v", minServerMemoryLimit, maxServerMemoryLimit))
 	}
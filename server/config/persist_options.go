@@ -849,6 +849,11 @@ func (o *PersistOptions) GetMinResolvedTSPersistenceInterval() time.Duration {
 	return o.GetPDServerConfig().MinResolvedTSPersistenceInterval.Duration
 }
 
+// GetRegionSyncBatchSize gets the max number of regions in one message of the region syncer.
+func (o *PersistOptions) GetRegionSyncBatchSize() int {
+	return o.GetPDServerConfig().RegionSyncBatchSize
+}
+
 // SetTTLData set temporary configuration
 func (o *PersistOptions) SetTTLData(parCtx context.Context, client *clientv3.Client, key string, value string, ttl time.Duration) error {
 	if o.ttl == nil {
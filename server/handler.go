@@ -321,6 +321,11 @@ func (h *Handler) AddSplitBucketScheduler() error {
 	return h.AddScheduler(schedulers.SplitBucketType)
 }
 
+// AddHotBucketLeaderScheduler adds a hot-bucket-leader-scheduler.
+func (h *Handler) AddHotBucketLeaderScheduler() error {
+	return h.AddScheduler(schedulers.HotBucketLeaderType)
+}
+
 // AddRandomMergeScheduler adds a random-merge-scheduler.
 func (h *Handler) AddRandomMergeScheduler() error {
 	return h.AddScheduler(schedulers.RandomMergeType)
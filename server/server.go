@@ -885,6 +885,11 @@ func (s *Server) GetBasicCluster() *core.BasicCluster {
 	return s.basicCluster
 }
 
+// GetRegionSyncBatchSize returns the max number of regions in one message of the region syncer.
+func (s *Server) GetRegionSyncBatchSize() int {
+	return s.persistOptions.GetRegionSyncBatchSize()
+}
+
 // GetPersistOptions returns the schedule option.
 func (s *Server) GetPersistOptions() *config.PersistOptions {
 	return s.persistOptions
@@ -1416,6 +1421,11 @@ func (s *Server) GetControllerConfig() *rm_server.ControllerConfig {
 	return &s.cfg.Controller
 }
 
+// GetResourceManagerConfig gets the resource group manager config.
+func (s *Server) GetResourceManagerConfig() *rm_server.ManagerConfig {
+	return &s.cfg.ResourceManager
+}
+
 // GetRaftCluster gets Raft cluster.
 // If cluster has not been bootstrapped, return nil.
 func (s *Server) GetRaftCluster() *cluster.RaftCluster {
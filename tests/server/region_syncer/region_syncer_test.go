@@ -16,6 +16,7 @@ package syncer_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/syncer"
 	"github.com/tikv/pd/pkg/utils/testutil"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/tests"
@@ -295,3 +297,67 @@ func TestPrepareCheckerWithTransferLeader(t *testing.T) {
 	re.True(rc.IsPrepared())
 	re.NoError(failpoint.Disable("github.com/tikv/pd/pkg/schedule/changeCoordinatorTicker"))
 }
+
+func TestRegionSyncerLag(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cluster, err := tests.NewTestCluster(ctx, 3, func(conf *config.Config, _ string) { conf.PDServerCfg.UseRegionStorage = true })
+	defer func() {
+		cluster.Destroy()
+		cancel()
+	}()
+	re.NoError(err)
+
+	re.NoError(cluster.RunInitialServers())
+	cluster.WaitLeader()
+	leaderServer := cluster.GetLeaderServer()
+	re.NoError(leaderServer.BootstrapCluster())
+	rc := leaderServer.GetServer().GetRaftCluster()
+	re.NotNil(rc)
+	re.True(cluster.WaitRegionSyncerClientsReady(2))
+	followerName := cluster.GetFollower()
+	followerSyncer := cluster.GetServer(followerName).GetServer().DirectlyGetRaftCluster().GetRegionSyncer()
+
+	dialClient := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	url := leaderServer.GetAddr() + "/pd/api/v1/health/region-syncer"
+	getFollowerStatus := func() *syncer.DownstreamStatus {
+		var statuses []syncer.DownstreamStatus
+		re.NoError(testutil.ReadGetJSON(re, dialClient, url, &statuses))
+		for i := range statuses {
+			if statuses[i].Name == followerName {
+				return &statuses[i]
+			}
+		}
+		return nil
+	}
+
+	regions := tests.InitRegions(10)
+	for _, region := range regions {
+		re.NoError(rc.HandleRegionHeartbeat(region))
+	}
+	testutil.Eventually(re, func() bool {
+		status := getFollowerStatus()
+		return status != nil && status.Connected && status.LagRegions == 0
+	})
+
+	// The lag grows after the follower stops syncing.
+	followerSyncer.StopSyncWithLeader()
+	var lag uint64
+	testutil.Eventually(re, func() bool {
+		for i := range regions {
+			regions[i] = regions[i].Clone(core.SetWrittenBytes(regions[i].GetBytesWritten() + 1))
+			re.NoError(rc.HandleRegionHeartbeat(regions[i]))
+		}
+		status := getFollowerStatus()
+		lag = status.LagRegions
+		return !status.Connected && lag > 0
+	})
+
+	// The lag decreases after the follower catches up.
+	followerSyncer.StartSyncWithLeader(leaderServer.GetAddr())
+	testutil.Eventually(re, func() bool {
+		status := getFollowerStatus()
+		re.LessOrEqual(status.LagRegions, lag)
+		return status.Connected && status.LagRegions == 0
+	})
+}
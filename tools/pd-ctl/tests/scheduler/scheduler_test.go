@@ -140,6 +140,9 @@ func (suite *schedulerTestSuite) checkScheduler(cluster *pdTests.TestCluster) {
 		testutil.Eventually(re, func() bool {
 			configInfo := make(map[string]any)
 			mustExec(re, cmd, []string{"-u", pdAddr, "scheduler", "config", schedulerName}, &configInfo)
+			// The version of the evict-leader config grows with every change, it's
+			// covered by the unit test of the scheduler.
+			delete(configInfo, "version")
 			return reflect.DeepEqual(expectedConfig, configInfo)
 		})
 	}